@@ -0,0 +1,127 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nkeys"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+// creds issues credentials for a new user, signed with the account key or
+// signing key read from the seed file given by -key, and writes them to
+// the file given by -out, or stdout, in the format of a .creds file.
+func creds(
+	args []string,
+	_ io.Reader,
+	stdout io.Writer,
+) error {
+	flags := flag.NewFlagSet("creds", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	keyFile := flags.String("key", "", "seed file of the signing account key")
+	account := flags.String("account", "", "account public key, for signing keys")
+	name := flags.String("name", "", "user name")
+	expires := flags.Duration("expires", 0, "time until the credentials expire")
+	pub := flags.String("pub", "", "comma-separated subjects allowed to publish")
+	sub := flags.String("sub", "", "comma-separated subjects allowed to subscribe")
+	out := flags.String("out", "", "creds file to write instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *keyFile == "" {
+		return errors.New("no key file given")
+	}
+
+	seed, err := os.ReadFile(*keyFile)
+	if err != nil {
+		return fmt.Errorf("error reading key: %w", err)
+	}
+
+	signer, err := nkeys.FromSeed(bytes.TrimSpace(seed))
+	if err != nil {
+		return fmt.Errorf("error reading key: %w", err)
+	}
+
+	user := &server.UserJWT{
+		Name:          *name,
+		IssuerAccount: *account,
+		Permissions:   permissions(*pub, *sub),
+	}
+	if *expires > 0 {
+		user.Expires = time.Now().Add(*expires)
+	}
+
+	credentials, err := server.NewCredentials(signer, user)
+	if err != nil {
+		return err
+	}
+
+	if *out != "" {
+		return credentials.WriteFile(*out)
+	}
+
+	// Credentials just issued always encode.
+	data, _ := credentials.Encode()
+	_, err = stdout.Write(data)
+
+	return err
+}
+
+// permissions returns the permissions allowing the comma-separated
+// subjects pub and sub, or nil to allow everything when both are empty.
+func permissions(
+	pub string,
+	sub string,
+) *natsserver.Permissions {
+	if pub == "" && sub == "" {
+		return nil
+	}
+
+	return &natsserver.Permissions{
+		Publish:   subjectPermission(pub),
+		Subscribe: subjectPermission(sub),
+	}
+}
+
+// subjectPermission returns the permission allowing the comma-separated
+// subjects, or nil to allow everything when there are none.
+func subjectPermission(
+	subjects string,
+) *natsserver.SubjectPermission {
+	if subjects == "" {
+		return nil
+	}
+
+	return &natsserver.SubjectPermission{
+		Allow: strings.Split(subjects, ","),
+	}
+}
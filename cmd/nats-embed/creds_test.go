@@ -0,0 +1,267 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+	"github.com/stretchr/testify/suite"
+)
+
+type CredsTestSuite struct {
+	suite.Suite
+
+	dir     string
+	account nkeys.KeyPair
+	keyFile string
+}
+
+func (s *CredsTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+
+	var err error
+	s.account, err = nkeys.CreateAccount()
+	s.Require().NoError(err)
+	s.keyFile = s.writeSeed("account.nk", s.account)
+}
+
+func (s *CredsTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *CredsTestSuite) TestCreds() {
+	tests := []struct {
+		name         string
+		args         func() []string
+		stdout       io.Writer
+		validateFunc func(out string, err error)
+	}{
+		{
+			name: "writes creds to stdout",
+			args: func() []string {
+				return []string{
+					"-key", s.keyFile,
+					"-name", "alice",
+					"-pub", "orders.>,billing",
+					"-sub", "_INBOX.>",
+					"-expires", "1h",
+				}
+			},
+			validateFunc: func(out string, err error) {
+				s.Require().NoError(err)
+				claims := s.decode([]byte(out))
+
+				s.Equal("alice", claims.Name)
+				s.Equal(s.publicKey(s.account), claims.Issuer)
+				s.Empty(claims.IssuerAccount)
+				s.Equal(
+					jwt.StringList{"orders.>", "billing"},
+					claims.Pub.Allow,
+				)
+				s.Equal(jwt.StringList{"_INBOX.>"}, claims.Sub.Allow)
+				s.InDelta(
+					time.Now().Add(time.Hour).Unix(),
+					claims.Expires,
+					5,
+				)
+			},
+		},
+		{
+			name: "writes creds file signed with signing key",
+			args: func() []string {
+				signer, err := nkeys.CreateAccount()
+				s.Require().NoError(err)
+				return []string{
+					"-key", s.writeSeed("signer.nk", signer),
+					"-account", s.publicKey(s.account),
+					"-sub", "events",
+					"-out", filepath.Join(s.dir, "user.creds"),
+				}
+			},
+			validateFunc: func(out string, err error) {
+				s.Require().NoError(err)
+				s.Empty(out)
+
+				path := filepath.Join(s.dir, "user.creds")
+				info, err := os.Stat(path)
+				s.Require().NoError(err)
+				s.Equal(os.FileMode(0o600), info.Mode().Perm())
+
+				data, err := os.ReadFile(path)
+				s.Require().NoError(err)
+				claims := s.decode(data)
+				s.Equal(s.publicKey(s.account), claims.IssuerAccount)
+				s.Empty(claims.Pub.Allow)
+				s.Equal(jwt.StringList{"events"}, claims.Sub.Allow)
+				s.Zero(claims.Expires)
+			},
+		},
+		{
+			name: "allows everything without subjects",
+			args: func() []string {
+				return []string{"-key", s.keyFile}
+			},
+			validateFunc: func(out string, err error) {
+				s.Require().NoError(err)
+				claims := s.decode([]byte(out))
+				s.Equal(jwt.Permissions{}, claims.Permissions)
+			},
+		},
+		{
+			name: "returns error for unknown flag",
+			args: func() []string {
+				return []string{"-seed", s.keyFile}
+			},
+			validateFunc: func(_ string, err error) {
+				s.EqualError(err, "flag provided but not defined: -seed")
+			},
+		},
+		{
+			name: "returns error without key file",
+			args: func() []string {
+				return nil
+			},
+			validateFunc: func(_ string, err error) {
+				s.EqualError(err, "no key file given")
+			},
+		},
+		{
+			name: "returns error when key file cannot be read",
+			args: func() []string {
+				return []string{"-key", filepath.Join(s.dir, "missing.nk")}
+			},
+			validateFunc: func(_ string, err error) {
+				s.ErrorContains(err, "error reading key: open ")
+			},
+		},
+		{
+			name: "returns error when key file holds no seed",
+			args: func() []string {
+				path := filepath.Join(s.dir, "junk.nk")
+				s.Require().NoError(os.WriteFile(path, []byte("junk"), 0o600))
+				return []string{"-key", path}
+			},
+			validateFunc: func(_ string, err error) {
+				s.ErrorContains(err, "error reading key: ")
+			},
+		},
+		{
+			name: "returns error when key is not an account key",
+			args: func() []string {
+				user, err := nkeys.CreateUser()
+				s.Require().NoError(err)
+				return []string{"-key", s.writeSeed("user.nk", user)}
+			},
+			validateFunc: func(_ string, err error) {
+				s.EqualError(
+					err,
+					"error issuing credentials: signer is not an account key",
+				)
+			},
+		},
+		{
+			name: "returns error when creds file cannot be written",
+			args: func() []string {
+				return []string{
+					"-key", s.keyFile,
+					"-out", filepath.Join(s.dir, "missing", "user.creds"),
+				}
+			},
+			validateFunc: func(_ string, err error) {
+				s.ErrorContains(err, "error writing credentials: ")
+			},
+		},
+		{
+			name: "returns error when creds cannot be written",
+			args: func() []string {
+				return []string{"-key", s.keyFile}
+			},
+			stdout: failingWriter{},
+			validateFunc: func(_ string, err error) {
+				s.EqualError(err, "write failed")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			var out bytes.Buffer
+			stdout := tc.stdout
+			if stdout == nil {
+				stdout = &out
+			}
+
+			err := creds(tc.args(), nil, stdout)
+			tc.validateFunc(out.String(), err)
+		})
+	}
+}
+
+// decode returns the user claims of the .creds file data, checking that
+// it holds a user seed.
+func (s *CredsTestSuite) decode(
+	data []byte,
+) *jwt.UserClaims {
+	token, err := jwt.ParseDecoratedJWT(data)
+	s.Require().NoError(err)
+	claims, err := jwt.DecodeUserClaims(token)
+	s.Require().NoError(err)
+
+	key, err := jwt.ParseDecoratedUserNKey(data)
+	s.Require().NoError(err)
+	s.Equal(claims.Subject, s.publicKey(key))
+
+	return claims
+}
+
+// writeSeed writes the seed of key to the file name, returning its path.
+func (s *CredsTestSuite) writeSeed(
+	name string,
+	key nkeys.KeyPair,
+) string {
+	seed, err := key.Seed()
+	s.Require().NoError(err)
+	path := filepath.Join(s.dir, name)
+	s.Require().NoError(os.WriteFile(path, seed, 0o600))
+
+	return path
+}
+
+// publicKey returns the public key of key.
+func (s *CredsTestSuite) publicKey(
+	key nkeys.KeyPair,
+) string {
+	pub, err := key.PublicKey()
+	s.Require().NoError(err)
+
+	return pub
+}
+
+func TestCredsTestSuite(t *testing.T) {
+	suite.Run(t, new(CredsTestSuite))
+}
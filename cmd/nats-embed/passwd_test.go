@@ -0,0 +1,135 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type PasswdTestSuite struct {
+	suite.Suite
+}
+
+// failingWriter is an io.Writer whose writes fail.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func (s *PasswdTestSuite) TestPasswd() {
+	tests := []struct {
+		name         string
+		args         []string
+		stdin        io.Reader
+		stdout       io.Writer
+		validateFunc func(out string, err error)
+	}{
+		{
+			name:  "writes hash of first line",
+			args:  []string{"-cost", "4"},
+			stdin: strings.NewReader("s3cr3t\r\nignored\n"),
+			validateFunc: func(out string, err error) {
+				s.Require().NoError(err)
+				hash := strings.TrimSuffix(out, "\n")
+				s.NoError(
+					bcrypt.CompareHashAndPassword([]byte(hash), []byte("s3cr3t")),
+				)
+				cost, err := bcrypt.Cost([]byte(hash))
+				s.Require().NoError(err)
+				s.Equal(4, cost)
+			},
+		},
+		{
+			name:  "reads password without newline",
+			args:  []string{"-cost", "4"},
+			stdin: strings.NewReader("s3cr3t"),
+			validateFunc: func(out string, err error) {
+				s.Require().NoError(err)
+				s.True(strings.HasPrefix(out, "$2a$04$"))
+			},
+		},
+		{
+			name:  "returns error for unknown flag",
+			args:  []string{"-rounds", "4"},
+			stdin: strings.NewReader("s3cr3t\n"),
+			validateFunc: func(_ string, err error) {
+				s.EqualError(err, "flag provided but not defined: -rounds")
+			},
+		},
+		{
+			name:  "returns error when stdin cannot be read",
+			stdin: iotest.ErrReader(errors.New("read failed")),
+			validateFunc: func(_ string, err error) {
+				s.EqualError(err, "error reading password: read failed")
+			},
+		},
+		{
+			name:  "returns error when password is empty",
+			stdin: strings.NewReader("\n"),
+			validateFunc: func(_ string, err error) {
+				s.EqualError(err, "empty password")
+			},
+		},
+		{
+			name:  "returns error when cost is invalid",
+			args:  []string{"-cost", "99"},
+			stdin: strings.NewReader("s3cr3t\n"),
+			validateFunc: func(_ string, err error) {
+				s.ErrorContains(err, "error hashing password: ")
+			},
+		},
+		{
+			name:   "returns error when hash cannot be written",
+			args:   []string{"-cost", "4"},
+			stdin:  strings.NewReader("s3cr3t\n"),
+			stdout: failingWriter{},
+			validateFunc: func(_ string, err error) {
+				s.EqualError(err, "write failed")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			var out bytes.Buffer
+			stdout := tc.stdout
+			if stdout == nil {
+				stdout = &out
+			}
+
+			err := passwd(tc.args, tc.stdin, stdout)
+			tc.validateFunc(out.String(), err)
+		})
+	}
+}
+
+func TestPasswdTestSuite(t *testing.T) {
+	suite.Run(t, new(PasswdTestSuite))
+}
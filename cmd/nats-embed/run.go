@@ -0,0 +1,71 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// commands are the subcommands of nats-embed, by name.
+var commands = map[string]func(
+	args []string,
+	stdin io.Reader,
+	stdout io.Writer,
+) error{
+	"passwd": passwd,
+	"creds":  creds,
+}
+
+// usage describes how to run nats-embed.
+const usage = `usage: nats-embed <command> [flags]
+
+commands:
+  passwd    bcrypt hash a password read from stdin
+  creds     issue a user .creds file signed with an account key
+`
+
+// run runs the subcommand named by args[0] with the remaining arguments,
+// returning the exit code.
+func run(
+	args []string,
+	stdin io.Reader,
+	stdout io.Writer,
+	stderr io.Writer,
+) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	command, ok := commands[args[0]]
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	if err := command(args[1:], stdin, stdout); err != nil {
+		_, _ = fmt.Fprintf(stderr, "nats-embed %s: %v\n", args[0], err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RunTestSuite struct {
+	suite.Suite
+}
+
+func (s *RunTestSuite) TestRun() {
+	tests := []struct {
+		name         string
+		args         []string
+		stdin        string
+		validateFunc func(code int, stdout string, stderr string)
+	}{
+		{
+			name:  "runs command",
+			args:  []string{"passwd", "-cost", "4"},
+			stdin: "s3cr3t\n",
+			validateFunc: func(code int, stdout string, stderr string) {
+				s.Equal(0, code)
+				s.True(strings.HasPrefix(stdout, "$2a$04$"))
+				s.Empty(stderr)
+			},
+		},
+		{
+			name: "prints usage without command",
+			validateFunc: func(code int, stdout string, stderr string) {
+				s.Equal(2, code)
+				s.Empty(stdout)
+				s.Equal(usage, stderr)
+			},
+		},
+		{
+			name: "prints usage for unknown command",
+			args: []string{"frobnicate"},
+			validateFunc: func(code int, _ string, stderr string) {
+				s.Equal(2, code)
+				s.True(strings.HasPrefix(
+					stderr,
+					"unknown command \"frobnicate\"\n\nusage: ",
+				))
+			},
+		},
+		{
+			name: "prints command error",
+			args: []string{"passwd"},
+			validateFunc: func(code int, _ string, stderr string) {
+				s.Equal(1, code)
+				s.Equal("nats-embed passwd: empty password\n", stderr)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			var stdout, stderr bytes.Buffer
+			code := run(tc.args, strings.NewReader(tc.stdin), &stdout, &stderr)
+			tc.validateFunc(code, stdout.String(), stderr.String())
+		})
+	}
+}
+
+func TestRunTestSuite(t *testing.T) {
+	suite.Run(t, new(RunTestSuite))
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+// teams maps each user to the team whose account it is bound to. A real
+// handler would ask a database or an identity provider instead.
+var teams = map[string]string{
+	"alice": "orders",
+	"bob":   "billing",
+}
+
+func main() {
+	logger := slog.Default()
+
+	opts := &server.Options{
+		Options: &natsserver.Options{
+			Accounts: []*natsserver.Account{
+				natsserver.NewAccount("orders"),
+				natsserver.NewAccount("billing"),
+			},
+			NoSigs: true,
+		},
+		ReadyTimeout: 5 * time.Second,
+		AuthCallout: &server.AuthCallout{
+			Handler: server.AuthenticatorFunc(authenticate),
+		},
+	}
+
+	s := server.New(logger, opts)
+	err := s.Start()
+	if err != nil {
+		logger.Error("failed to start server", "error", err)
+		os.Exit(1)
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	s.Stop()
+}
+
+// authenticate lets every user of a team connect with the password
+// "secret", bound to the team's account and limited to its subjects.
+func authenticate(
+	_ context.Context,
+	req *server.AuthRequest,
+) (*server.AuthResult, error) {
+	team, ok := teams[req.Username]
+	if !ok || req.Password != "secret" {
+		return nil, server.ErrInvalidCredentials
+	}
+
+	subjects := &natsserver.SubjectPermission{
+		Allow: []string{team + ".>", "_INBOX.>"},
+	}
+
+	return &server.AuthResult{
+		Account: team,
+		Permissions: &natsserver.Permissions{
+			Publish:   subjects,
+			Subscribe: subjects,
+		},
+		Expires: time.Now().Add(8 * time.Hour),
+	}, nil
+}
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/osapi-io/nats-server/pkg/auth"
 	"github.com/osapi-io/nats-server/pkg/server"
 )
 
@@ -34,26 +35,8 @@ func main() {
 	trace := debug
 	logger := slog.Default()
 
-	nkeyUsers := []*natsserver.NkeyUser{
-		{
-			Nkey: "UAZMBGU3ASBL22E5WW6F3EFAW3CNUGGFRRYI6VRPPOHLNCNJZDTXFOPG", // Service 1
-		},
-		{
-			Nkey: "UCL5D5YPOGDRFAS354LW7D3E4S7FOJXZGR3ULHGQFWTFQ3PCRTUAT3ZD", // Service 2 disabled
-			Permissions: &natsserver.Permissions{
-				Publish: &natsserver.SubjectPermission{
-					Allow: []string{},
-				},
-				Subscribe: &natsserver.SubjectPermission{
-					Allow: []string{},
-				},
-			},
-		},
-	}
-
 	opts := &server.Options{
 		Options: &natsserver.Options{
-			Nkeys:     nkeyUsers,
 			JetStream: true,
 			Debug:     debug,
 			Trace:     trace,
@@ -64,8 +47,23 @@ func main() {
 		ReadyTimeout: 5 * time.Second,
 	}
 
+	err := auth.New().
+		// Service 1
+		NkeyUser("UAZMBGU3ASBL22E5WW6F3EFAW3CNUGGFRRYI6VRPPOHLNCNJZDTXFOPG").
+		// Service 2 disabled
+		NkeyUser(
+			"UCL5D5YPOGDRFAS354LW7D3E4S7FOJXZGR3ULHGQFWTFQ3PCRTUAT3ZD",
+			auth.PublishAllow(),
+			auth.SubscribeAllow(),
+		).
+		Apply(opts)
+	if err != nil {
+		logger.Error("failed to build auth", "error", err)
+		os.Exit(1)
+	}
+
 	s := server.New(logger, opts)
-	err := s.Start()
+	err = s.Start()
 	if err != nil {
 		logger.Error("failed to start server", "error", err)
 		os.Exit(1)
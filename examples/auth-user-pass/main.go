@@ -36,16 +36,18 @@ func main() {
 	trace := debug
 	logger := slog.Default()
 
+	// Passwords are read from the environment when the server starts, so
+	// they are not kept in the source.
 	systemAccount := natsserver.NewAccount("system")
 	systemUser := &natsserver.User{
 		Username: "system",
-		Password: "systempassword",
+		Password: "env://NATS_SYSTEM_PASSWORD",
 		Account:  systemAccount,
 	}
 
 	regularUser := &natsserver.User{
 		Username: "myuser",
-		Password: "mypassword",
+		Password: "env://NATS_USER_PASSWORD",
 	}
 
 	opts := &server.Options{
@@ -4,10 +4,7 @@ go 1.25.0
 
 replace github.com/osapi-io/nats-server => ../../
 
-require (
-	github.com/nats-io/nats-server/v2 v2.14.5
-	github.com/osapi-io/nats-server v0.0.0-00010101000000-000000000000
-)
+require github.com/osapi-io/nats-server v0.0.0-00010101000000-000000000000
 
 require (
 	github.com/antithesishq/antithesis-sdk-go v0.7.2-default-no-op // indirect
@@ -15,6 +12,8 @@ require (
 	github.com/klauspost/compress v1.19.2 // indirect
 	github.com/minio/highwayhash v1.0.4 // indirect
 	github.com/nats-io/jwt/v2 v2.8.2 // indirect
+	github.com/nats-io/nats-server/v2 v2.14.5 // indirect
+	github.com/nats-io/nats.go v1.51.0 // indirect
 	github.com/nats-io/nkeys v0.4.16 // indirect
 	github.com/nats-io/nuid v1.0.1 // indirect
 	golang.org/x/crypto v0.55.0 // indirect
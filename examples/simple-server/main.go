@@ -25,30 +25,20 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
-	natsserver "github.com/nats-io/nats-server/v2/server"
 	"github.com/osapi-io/nats-server/pkg/server"
 )
 
 func main() {
-	debug := true
-	trace := debug
 	logger := slog.Default()
 
-	opts := &server.Options{
-		Options: &natsserver.Options{
-			JetStream: true,
-			Debug:     debug,
-			Trace:     trace,
-			StoreDir:  ".nats/jetstream/",
-			NoSigs:    true,
-			NoLog:     false,
-		},
-		ReadyTimeout: 5 * time.Second,
-	}
-
-	s := server.New(logger, opts)
+	s := server.NewWithOptions(
+		logger,
+		server.WithHost("0.0.0.0"),
+		server.WithPort(4222),
+		server.WithJetStream(".nats/jetstream/"),
+		server.WithDebug(true),
+	)
 	err := s.Start()
 	if err != nil {
 		logger.Error("failed to start server", "error", err)
@@ -0,0 +1,329 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nkeys"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+// Build checks the declarations and builds them, returning every problem
+// found. Imports are checked against the exports of the accounts they
+// import from.
+func (b *Builder) Build() (*Auth, error) {
+	out := &Auth{SystemAccount: b.systemAccount}
+
+	var errs []error
+	accounts := make(map[string]*natsserver.Account, len(b.accounts))
+	declared := make([]*account, 0, len(b.accounts))
+	for _, a := range b.accounts {
+		switch {
+		case a.name == "":
+			errs = append(errs, errors.New("account has no name"))
+			continue
+		case accounts[a.name] != nil:
+			errs = append(errs, fmt.Errorf(
+				"account %q: declared more than once",
+				a.name,
+			))
+			continue
+		}
+
+		acc := natsserver.NewAccount(a.name)
+		accounts[a.name] = acc
+		out.Accounts = append(out.Accounts, acc)
+		declared = append(declared, a)
+	}
+
+	// Every export is in place before the imports of them are checked.
+	for _, a := range declared {
+		errs = append(errs, a.addExports(accounts)...)
+	}
+	for _, a := range declared {
+		errs = append(errs, a.addImports(accounts)...)
+	}
+
+	seen := make(map[string]bool, len(b.users))
+	for _, u := range b.users {
+		if err := u.check(accounts, seen); len(err) > 0 {
+			errs = append(errs, err...)
+			continue
+		}
+
+		if u.nkey {
+			out.Nkeys = append(out.Nkeys, &natsserver.NkeyUser{
+				Nkey:        u.name,
+				Account:     accounts[u.account],
+				Permissions: u.permissions(),
+			})
+			continue
+		}
+
+		out.Users = append(out.Users, &natsserver.User{
+			Username:    u.name,
+			Password:    u.password,
+			Account:     accounts[u.account],
+			Permissions: u.permissions(),
+		})
+	}
+
+	if b.systemAccount != "" && accounts[b.systemAccount] == nil {
+		errs = append(errs, fmt.Errorf(
+			"system account %q: not declared",
+			b.systemAccount,
+		))
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("error building auth: %w", errors.Join(errs...))
+	}
+
+	return out, nil
+}
+
+// Apply builds the declarations into opts, replacing its accounts, users,
+// nkey users, and system account. Opts is left unchanged when the build
+// fails.
+func (b *Builder) Apply(
+	opts *server.Options,
+) error {
+	built, err := b.Build()
+	if err != nil {
+		return err
+	}
+
+	if opts.Options == nil {
+		opts.Options = &natsserver.Options{}
+	}
+	opts.Accounts = built.Accounts
+	opts.Users = built.Users
+	opts.Nkeys = built.Nkeys
+	opts.SystemAccount = built.SystemAccount
+
+	return nil
+}
+
+// addExports adds the exports of a to its account in accounts.
+func (a *account) addExports(
+	accounts map[string]*natsserver.Account,
+) []error {
+	var errs []error
+	for _, e := range a.exports {
+		if !natsserver.IsValidSubject(e.subject) {
+			errs = append(errs, fmt.Errorf(
+				"account %q: invalid export subject %q",
+				a.name,
+				e.subject,
+			))
+			continue
+		}
+
+		// No accounts makes the export public.
+		var to []*natsserver.Account
+		unknown := false
+		for _, name := range e.to {
+			acc, ok := accounts[name]
+			if !ok {
+				errs = append(errs, fmt.Errorf(
+					"account %q: export of %q to unknown account %q",
+					a.name,
+					e.subject,
+					name,
+				))
+				unknown = true
+				continue
+			}
+			to = append(to, acc)
+		}
+		if unknown {
+			continue
+		}
+
+		// Exporting a valid subject from a declared account cannot fail.
+		if e.service {
+			_ = accounts[a.name].AddServiceExport(e.subject, to)
+		} else {
+			_ = accounts[a.name].AddStreamExport(e.subject, to)
+		}
+	}
+
+	return errs
+}
+
+// addImports adds the imports of a to its account in accounts.
+func (a *account) addImports(
+	accounts map[string]*natsserver.Account,
+) []error {
+	var errs []error
+	for _, i := range a.imports {
+		from, ok := accounts[i.from]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf(
+				"account %q: import from unknown account %q",
+				a.name,
+				i.from,
+			))
+			continue
+		case !natsserver.IsValidSubject(i.subject):
+			errs = append(errs, fmt.Errorf(
+				"account %q: invalid import subject %q",
+				a.name,
+				i.subject,
+			))
+			continue
+		}
+
+		var err error
+		if i.service {
+			local := i.to
+			if local == "" {
+				local = i.subject
+			}
+			err = accounts[a.name].AddServiceImport(from, local, i.subject)
+		} else {
+			err = accounts[a.name].AddStreamImport(from, i.subject, i.to)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf(
+				"account %q: error importing %q from %q: %w",
+				a.name,
+				i.subject,
+				i.from,
+				err,
+			))
+		}
+	}
+
+	return errs
+}
+
+// check returns the problems with u, given the accounts declared and the
+// users seen before it, to which it adds u.
+func (u *user) check(
+	accounts map[string]*natsserver.Account,
+	seen map[string]bool,
+) []error {
+	var errs []error
+	switch {
+	case u.name == "":
+		errs = append(errs, errors.New("user has no name"))
+	case seen[u.name]:
+		errs = append(errs, fmt.Errorf(
+			"user %q: declared more than once",
+			u.name,
+		))
+	case u.nkey && !nkeys.IsValidPublicUserKey(u.name):
+		errs = append(errs, fmt.Errorf(
+			"user %q: not a public user nkey",
+			u.name,
+		))
+	}
+	seen[u.name] = true
+
+	if u.account != "" && accounts[u.account] == nil {
+		errs = append(errs, fmt.Errorf(
+			"user %q: unknown account %q",
+			u.name,
+			u.account,
+		))
+	}
+
+	check := func(kind string, subjects []string, queue bool) {
+		for _, subject := range subjects {
+			if !validPermission(subject, queue) {
+				errs = append(errs, fmt.Errorf(
+					"user %q: invalid %s subject %q",
+					u.name,
+					kind,
+					subject,
+				))
+			}
+		}
+	}
+	if u.publish != nil {
+		check("publish allow", u.publish.allow, false)
+		check("publish deny", u.publish.deny, false)
+	}
+	if u.subscribe != nil {
+		check("subscribe allow", u.subscribe.allow, true)
+		check("subscribe deny", u.subscribe.deny, true)
+	}
+
+	return errs
+}
+
+// permissions returns the permissions of u as the NATS server takes them,
+// or nil when u has none.
+func (u *user) permissions() *natsserver.Permissions {
+	if u.publish == nil && u.subscribe == nil && u.responses == nil {
+		return nil
+	}
+
+	perms := &natsserver.Permissions{
+		Publish:   u.publish.permission(),
+		Subscribe: u.subscribe.permission(),
+	}
+	if u.responses != nil {
+		perms.Response = &natsserver.ResponsePermission{
+			MaxMsgs: u.responses.maxMsgs,
+			Expires: u.responses.expires,
+		}
+	}
+
+	return perms
+}
+
+// permission returns s as the NATS server takes it, or nil for none.
+func (s *subjects) permission() *natsserver.SubjectPermission {
+	if s == nil {
+		return nil
+	}
+
+	return &natsserver.SubjectPermission{
+		Allow: s.allow,
+		Deny:  s.deny,
+	}
+}
+
+// validPermission reports whether subject may be given in a permission,
+// followed by a space and a queue group when queue is set, as the NATS
+// server checks them.
+func validPermission(
+	subject string,
+	queue bool,
+) bool {
+	if natsserver.IsValidSubject(subject) {
+		return true
+	}
+
+	fields := strings.Fields(subject)
+
+	return queue && len(fields) == 2 &&
+		natsserver.IsValidSubject(fields[0]) &&
+		natsserver.IsValidSubject(fields[1])
+}
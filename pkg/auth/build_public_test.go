@@ -0,0 +1,266 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/auth"
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+type BuildPublicTestSuite struct {
+	suite.Suite
+}
+
+func (s *BuildPublicTestSuite) TestBuild() {
+	tests := []struct {
+		name         string
+		builder      *auth.Builder
+		validateFunc func(built *auth.Auth, err error)
+	}{
+		{
+			name: "builds accounts users and permissions",
+			builder: auth.New().
+				Account("SYS").
+				Account("orders", auth.ExportService("orders.create")).
+				Account("web", auth.ImportService("orders", "orders.create", "")).
+				SystemAccount("SYS").
+				User("admin", "secret", auth.InAccount("SYS")).
+				User(
+					"web",
+					"secret",
+					auth.InAccount("web"),
+					auth.PublishAllow("orders.create"),
+					auth.SubscribeAllow("_INBOX.>"),
+				).
+				NkeyUser(
+					nkeyUser,
+					auth.InAccount("orders"),
+					auth.SubscribeAllow("orders.create orders"),
+					auth.AllowResponses(1, time.Minute),
+				),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Require().NoError(err)
+				s.Require().Len(built.Accounts, 3)
+				s.Equal("SYS", built.SystemAccount)
+				s.Require().Len(built.Users, 2)
+				s.Same(built.Accounts[0], built.Users[0].Account)
+				s.Nil(built.Users[0].Permissions)
+				s.Same(built.Accounts[2], built.Users[1].Account)
+				s.Require().Len(built.Nkeys, 1)
+				s.Same(built.Accounts[1], built.Nkeys[0].Account)
+				s.NotNil(built.Nkeys[0].Permissions.Response)
+			},
+		},
+		{
+			name: "returns every problem",
+			builder: auth.New().
+				Account("").
+				Account("a", auth.ExportStream("bad..subject")).
+				Account("a").
+				Account(
+					"b",
+					auth.ExportStream("events.>", "a", "missing"),
+					auth.ImportStream("missing", "events.>", ""),
+					auth.ImportService("a", "bad..subject", ""),
+				).
+				SystemAccount("SYS").
+				User("", "secret").
+				User("alice", "secret", auth.InAccount("missing")).
+				User("alice", "secret").
+				NkeyUser("not-an-nkey").
+				User(
+					"bob",
+					"secret",
+					auth.PublishAllow("orders.>", "bad..subject"),
+					auth.PublishDeny("jobs.* workers"),
+					auth.SubscribeAllow("jobs.* workers", "a b c"),
+					auth.SubscribeDeny("bad..subject queue"),
+				),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Nil(built)
+				s.Require().Error(err)
+				s.Equal(
+					"error building auth: "+
+						"account has no name\n"+
+						`account "a": declared more than once`+"\n"+
+						`account "a": invalid export subject "bad..subject"`+
+						"\n"+
+						`account "b": export of "events.>" to unknown `+
+						`account "missing"`+"\n"+
+						`account "b": import from unknown account "missing"`+
+						"\n"+
+						`account "b": invalid import subject "bad..subject"`+
+						"\n"+
+						"user has no name\n"+
+						`user "alice": unknown account "missing"`+"\n"+
+						`user "alice": declared more than once`+"\n"+
+						`user "not-an-nkey": not a public user nkey`+"\n"+
+						`user "bob": invalid publish allow subject `+
+						`"bad..subject"`+"\n"+
+						`user "bob": invalid publish deny subject `+
+						`"jobs.* workers"`+"\n"+
+						`user "bob": invalid subscribe allow subject "a b c"`+
+						"\n"+
+						`user "bob": invalid subscribe deny subject `+
+						`"bad..subject queue"`+"\n"+
+						`system account "SYS": not declared`,
+					err.Error(),
+				)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(tc.builder.Build())
+		})
+	}
+}
+
+func (s *BuildPublicTestSuite) TestApply() {
+	tests := []struct {
+		name         string
+		opts         func() *server.Options
+		builder      *auth.Builder
+		validateFunc func(opts *server.Options, err error)
+	}{
+		{
+			name: "replaces authentication of options",
+			opts: func() *server.Options {
+				return &server.Options{Options: &natsserver.Options{
+					Port:          4333,
+					Users:         []*natsserver.User{{Username: "old"}},
+					Nkeys:         []*natsserver.NkeyUser{{Nkey: nkeyUser}},
+					SystemAccount: "OLD",
+				}}
+			},
+			builder: auth.New().Account("app").User("alice", "secret"),
+			validateFunc: func(opts *server.Options, err error) {
+				s.Require().NoError(err)
+				s.Equal(4333, opts.Port)
+				s.Len(opts.Accounts, 1)
+				s.Require().Len(opts.Users, 1)
+				s.Equal("alice", opts.Users[0].Username)
+				s.Empty(opts.Nkeys)
+				s.Empty(opts.SystemAccount)
+			},
+		},
+		{
+			name:    "creates nats server options when missing",
+			opts:    func() *server.Options { return &server.Options{} },
+			builder: auth.New().NkeyUser(nkeyUser),
+			validateFunc: func(opts *server.Options, err error) {
+				s.Require().NoError(err)
+				s.Len(opts.Nkeys, 1)
+			},
+		},
+		{
+			name: "leaves options unchanged on error",
+			opts: func() *server.Options {
+				return &server.Options{Options: &natsserver.Options{
+					Users: []*natsserver.User{{Username: "old"}},
+				}}
+			},
+			builder: auth.New().User("alice", "secret", auth.InAccount("x")),
+			validateFunc: func(opts *server.Options, err error) {
+				s.ErrorContains(err, `user "alice": unknown account "x"`)
+				s.Equal("old", opts.Users[0].Username)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			opts := tc.opts()
+
+			tc.validateFunc(opts, tc.builder.Apply(opts))
+		})
+	}
+}
+
+func (s *BuildPublicTestSuite) TestApplyServes() {
+	opts := &server.Options{
+		Options: &natsserver.Options{
+			Host:   "127.0.0.1",
+			Port:   -1,
+			NoLog:  true,
+			NoSigs: true,
+		},
+	}
+	err := auth.New().
+		Account("orders", auth.ExportStream("orders.>", "web")).
+		Account("web", auth.ImportStream("orders", "orders.>", "from")).
+		User(
+			"producer",
+			"secret",
+			auth.InAccount("orders"),
+			auth.PublishAllow("orders.>"),
+		).
+		User(
+			"consumer",
+			"secret",
+			auth.InAccount("web"),
+			auth.PublishAllow(),
+			auth.SubscribeAllow("from.orders.>"),
+		).
+		Apply(opts)
+	s.Require().NoError(err)
+
+	ns, err := natsserver.NewServer(opts.Options)
+	s.Require().NoError(err)
+	go ns.Start()
+	defer ns.Shutdown()
+	s.Require().True(ns.ReadyForConnections(5 * time.Second))
+
+	consumer, err := nats.Connect(
+		ns.ClientURL(),
+		nats.UserInfo("consumer", "secret"),
+	)
+	s.Require().NoError(err)
+	defer consumer.Close()
+	sub, err := consumer.SubscribeSync("from.orders.>")
+	s.Require().NoError(err)
+	s.Require().NoError(consumer.Flush())
+
+	producer, err := nats.Connect(
+		ns.ClientURL(),
+		nats.UserInfo("producer", "secret"),
+	)
+	s.Require().NoError(err)
+	defer producer.Close()
+	s.Require().NoError(producer.Publish("orders.created", []byte("1")))
+
+	msg, err := sub.NextMsg(5 * time.Second)
+	s.Require().NoError(err)
+	s.Equal("from.orders.created", msg.Subject)
+	s.Equal("1", string(msg.Data))
+}
+
+func TestBuildPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(BuildPublicTestSuite))
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+// Package auth builds the accounts, users, and permissions of an embedded
+// NATS server from declarations, checking subjects and references between
+// them before the server sees them.
+package auth
+
+// New returns a Builder with nothing declared.
+func New() *Builder {
+	return &Builder{}
+}
+
+// Account declares the account name, configured by opts.
+func (b *Builder) Account(
+	name string,
+	opts ...AccountOption,
+) *Builder {
+	a := &account{name: name}
+	for _, opt := range opts {
+		opt(a)
+	}
+	b.accounts = append(b.accounts, a)
+
+	return b
+}
+
+// User declares a user authenticating with name and password, configured
+// by opts. The password may be bcrypt hashed or a secret reference, both
+// of which server.Options handles.
+func (b *Builder) User(
+	name string,
+	password string,
+	opts ...UserOption,
+) *Builder {
+	return b.user(&user{name: name, password: password}, opts)
+}
+
+// NkeyUser declares a user authenticating with the public user nkey,
+// configured by opts.
+func (b *Builder) NkeyUser(
+	nkey string,
+	opts ...UserOption,
+) *Builder {
+	return b.user(&user{name: nkey, nkey: true}, opts)
+}
+
+// SystemAccount makes the declared account name the system account.
+func (b *Builder) SystemAccount(
+	name string,
+) *Builder {
+	b.systemAccount = name
+
+	return b
+}
+
+// user configures u by opts and adds it to the users declared.
+func (b *Builder) user(
+	u *user,
+	opts []UserOption,
+) *Builder {
+	for _, opt := range opts {
+		opt(u)
+	}
+	b.users = append(b.users, u)
+
+	return b
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package auth_test
+
+import (
+	"testing"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/auth"
+)
+
+type BuilderPublicTestSuite struct {
+	suite.Suite
+}
+
+// nkeyUser is a public user nkey.
+const nkeyUser = "UAZMBGU3ASBL22E5WW6F3EFAW3CNUGGFRRYI6VRPPOHLNCNJZDTXFOPG"
+
+func (s *BuilderPublicTestSuite) TestAccount() {
+	tests := []struct {
+		name         string
+		builder      *auth.Builder
+		validateFunc func(built *auth.Auth, err error)
+	}{
+		{
+			name:    "declares accounts in order",
+			builder: auth.New().Account("a").Account("b"),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Require().NoError(err)
+				s.Require().Len(built.Accounts, 2)
+				s.Equal("a", built.Accounts[0].Name)
+				s.Equal("b", built.Accounts[1].Name)
+			},
+		},
+		{
+			name: "applies options",
+			builder: auth.New().
+				Account("a", auth.ExportService("svc.>")),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Require().NoError(err)
+				s.True(built.Accounts[0].IsExportService("svc.>"))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(tc.builder.Build())
+		})
+	}
+}
+
+func (s *BuilderPublicTestSuite) TestUser() {
+	tests := []struct {
+		name         string
+		builder      *auth.Builder
+		validateFunc func(built *auth.Auth, err error)
+	}{
+		{
+			name:    "declares user without permissions",
+			builder: auth.New().User("alice", "secret"),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Require().NoError(err)
+				s.Equal([]*natsserver.User{{
+					Username: "alice",
+					Password: "secret",
+				}}, built.Users)
+				s.Empty(built.Nkeys)
+			},
+		},
+		{
+			name: "keeps secret reference as password",
+			builder: auth.New().
+				User("alice", "env://ALICE_PASSWORD"),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Require().NoError(err)
+				s.Equal("env://ALICE_PASSWORD", built.Users[0].Password)
+			},
+		},
+		{
+			name: "applies options",
+			builder: auth.New().
+				Account("app").
+				User("alice", "secret", auth.InAccount("app")),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Require().NoError(err)
+				s.Same(built.Accounts[0], built.Users[0].Account)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(tc.builder.Build())
+		})
+	}
+}
+
+func (s *BuilderPublicTestSuite) TestNkeyUser() {
+	tests := []struct {
+		name         string
+		builder      *auth.Builder
+		validateFunc func(built *auth.Auth, err error)
+	}{
+		{
+			name:    "declares nkey user",
+			builder: auth.New().NkeyUser(nkeyUser),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Require().NoError(err)
+				s.Equal([]*natsserver.NkeyUser{{
+					Nkey: nkeyUser,
+				}}, built.Nkeys)
+				s.Empty(built.Users)
+			},
+		},
+		{
+			name: "applies options",
+			builder: auth.New().
+				NkeyUser(nkeyUser, auth.SubscribeAllow("events.>")),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Require().NoError(err)
+				s.Equal(
+					[]string{"events.>"},
+					built.Nkeys[0].Permissions.Subscribe.Allow,
+				)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(tc.builder.Build())
+		})
+	}
+}
+
+func (s *BuilderPublicTestSuite) TestSystemAccount() {
+	tests := []struct {
+		name         string
+		builder      *auth.Builder
+		validateFunc func(built *auth.Auth, err error)
+	}{
+		{
+			name:    "sets declared system account",
+			builder: auth.New().Account("SYS").SystemAccount("SYS"),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Require().NoError(err)
+				s.Equal("SYS", built.SystemAccount)
+			},
+		},
+		{
+			name: "uses last system account given",
+			builder: auth.New().
+				Account("A").
+				Account("B").
+				SystemAccount("A").
+				SystemAccount("B"),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Require().NoError(err)
+				s.Equal("B", built.SystemAccount)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(tc.builder.Build())
+		})
+	}
+}
+
+func (s *BuilderPublicTestSuite) TestNew() {
+	built, err := auth.New().Build()
+
+	s.Require().NoError(err)
+	s.Equal(&auth.Auth{}, built)
+}
+
+func TestBuilderPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(BuilderPublicTestSuite))
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package auth
+
+import "time"
+
+// InAccount binds the user to the declared account name. Users bound to
+// no account use the global account.
+func InAccount(
+	name string,
+) UserOption {
+	return func(u *user) {
+		u.account = name
+	}
+}
+
+// PublishAllow allows the user to publish only to subjects, which may hold
+// wildcards. Each use adds to the subjects allowed; with no subjects, none
+// are.
+func PublishAllow(
+	subjects ...string,
+) UserOption {
+	return func(u *user) {
+		u.publish = allow(u.publish, subjects)
+	}
+}
+
+// PublishDeny forbids the user to publish to subjects, even those allowed.
+func PublishDeny(
+	subjects ...string,
+) UserOption {
+	return func(u *user) {
+		u.publish = deny(u.publish, subjects)
+	}
+}
+
+// SubscribeAllow allows the user to subscribe only to subjects. A subject
+// may be followed by a space and a queue group, allowing only that group.
+// Each use adds to the subjects allowed; with no subjects, none are.
+func SubscribeAllow(
+	subjects ...string,
+) UserOption {
+	return func(u *user) {
+		u.subscribe = allow(u.subscribe, subjects)
+	}
+}
+
+// SubscribeDeny forbids the user to subscribe to subjects, even those
+// allowed.
+func SubscribeDeny(
+	subjects ...string,
+) UserOption {
+	return func(u *user) {
+		u.subscribe = deny(u.subscribe, subjects)
+	}
+}
+
+// AllowResponses lets the user reply to requests it receives, even without
+// permission to publish to their reply subjects, sending up to maxMsgs
+// replies within expires of each request. Zero takes the NATS defaults of
+// one reply within two minutes. As the NATS server does, a user allowed
+// responses without PublishAllow may publish nothing but replies.
+func AllowResponses(
+	maxMsgs int,
+	expires time.Duration,
+) UserOption {
+	return func(u *user) {
+		u.responses = &responses{
+			maxMsgs: maxMsgs,
+			expires: expires,
+		}
+	}
+}
+
+// ExportStream offers the messages published to subject, which may hold
+// wildcards, to the accounts named, or to every account when none are.
+func ExportStream(
+	subject string,
+	to ...string,
+) AccountOption {
+	return func(a *account) {
+		a.exports = append(a.exports, export{subject: subject, to: to})
+	}
+}
+
+// ExportService offers the service answering requests on subject to the
+// accounts named, or to every account when none are.
+func ExportService(
+	subject string,
+	to ...string,
+) AccountOption {
+	return func(a *account) {
+		a.exports = append(a.exports, export{
+			service: true,
+			subject: subject,
+			to:      to,
+		})
+	}
+}
+
+// ImportStream takes the messages exported by account from on subject,
+// delivering them under prefix, which may be empty.
+func ImportStream(
+	from string,
+	subject string,
+	prefix string,
+) AccountOption {
+	return func(a *account) {
+		a.imports = append(a.imports, accountImport{
+			from:    from,
+			subject: subject,
+			to:      prefix,
+		})
+	}
+}
+
+// ImportService sends requests made on the local subject to to the service
+// exported by account from on subject. An empty to uses subject.
+func ImportService(
+	from string,
+	subject string,
+	to string,
+) AccountOption {
+	return func(a *account) {
+		a.imports = append(a.imports, accountImport{
+			service: true,
+			from:    from,
+			subject: subject,
+			to:      to,
+		})
+	}
+}
+
+// allow returns s with subjects added to those allowed.
+func allow(
+	s *subjects,
+	add []string,
+) *subjects {
+	if s == nil {
+		s = &subjects{}
+	}
+	if s.allow == nil {
+		// An empty allow list, unlike none, allows nothing.
+		s.allow = []string{}
+	}
+	s.allow = append(s.allow, add...)
+
+	return s
+}
+
+// deny returns s with subjects added to those denied.
+func deny(
+	s *subjects,
+	add []string,
+) *subjects {
+	if s == nil {
+		s = &subjects{}
+	}
+	s.deny = append(s.deny, add...)
+
+	return s
+}
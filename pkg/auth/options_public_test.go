@@ -0,0 +1,403 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/auth"
+)
+
+type OptionsPublicTestSuite struct {
+	suite.Suite
+}
+
+// permissions builds a single user given opts, returning its permissions.
+func (s *OptionsPublicTestSuite) permissions(
+	opts ...auth.UserOption,
+) *natsserver.Permissions {
+	built, err := auth.New().User("alice", "secret", opts...).Build()
+	s.Require().NoError(err)
+
+	return built.Users[0].Permissions
+}
+
+func (s *OptionsPublicTestSuite) TestInAccount() {
+	tests := []struct {
+		name         string
+		builder      *auth.Builder
+		validateFunc func(built *auth.Auth, err error)
+	}{
+		{
+			name: "binds user to account",
+			builder: auth.New().
+				Account("app").
+				NkeyUser(nkeyUser, auth.InAccount("app")),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Require().NoError(err)
+				s.Same(built.Accounts[0], built.Nkeys[0].Account)
+			},
+		},
+		{
+			name: "leaves user without account in global account",
+			builder: auth.New().
+				Account("app").
+				User("alice", "secret", auth.InAccount("")),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Require().NoError(err)
+				s.Nil(built.Users[0].Account)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(tc.builder.Build())
+		})
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestPublishAllow() {
+	tests := []struct {
+		name         string
+		opts         []auth.UserOption
+		validateFunc func(perms *natsserver.Permissions)
+	}{
+		{
+			name: "adds to subjects allowed",
+			opts: []auth.UserOption{
+				auth.PublishAllow("orders.*"),
+				auth.PublishAllow("events.>", "audit"),
+			},
+			validateFunc: func(perms *natsserver.Permissions) {
+				s.Equal(&natsserver.Permissions{
+					Publish: &natsserver.SubjectPermission{
+						Allow: []string{"orders.*", "events.>", "audit"},
+					},
+				}, perms)
+			},
+		},
+		{
+			name: "allows nothing without subjects",
+			opts: []auth.UserOption{auth.PublishAllow()},
+			validateFunc: func(perms *natsserver.Permissions) {
+				s.NotNil(perms.Publish.Allow)
+				s.Empty(perms.Publish.Allow)
+				s.Nil(perms.Subscribe)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(s.permissions(tc.opts...))
+		})
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestPublishDeny() {
+	tests := []struct {
+		name         string
+		opts         []auth.UserOption
+		validateFunc func(perms *natsserver.Permissions)
+	}{
+		{
+			name: "denies subjects",
+			opts: []auth.UserOption{
+				auth.PublishAllow("orders.>"),
+				auth.PublishDeny("orders.admin"),
+			},
+			validateFunc: func(perms *natsserver.Permissions) {
+				s.Equal([]string{"orders.>"}, perms.Publish.Allow)
+				s.Equal([]string{"orders.admin"}, perms.Publish.Deny)
+			},
+		},
+		{
+			name: "leaves everything else allowed",
+			opts: []auth.UserOption{auth.PublishDeny("admin.>")},
+			validateFunc: func(perms *natsserver.Permissions) {
+				s.Nil(perms.Publish.Allow)
+				s.Equal([]string{"admin.>"}, perms.Publish.Deny)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(s.permissions(tc.opts...))
+		})
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestSubscribeAllow() {
+	tests := []struct {
+		name         string
+		opts         []auth.UserOption
+		validateFunc func(perms *natsserver.Permissions)
+	}{
+		{
+			name: "allows subjects and queue groups",
+			opts: []auth.UserOption{
+				auth.SubscribeAllow("events.>", "jobs.* workers"),
+			},
+			validateFunc: func(perms *natsserver.Permissions) {
+				s.Nil(perms.Publish)
+				s.Equal(
+					[]string{"events.>", "jobs.* workers"},
+					perms.Subscribe.Allow,
+				)
+			},
+		},
+		{
+			name: "allows nothing without subjects",
+			opts: []auth.UserOption{auth.SubscribeAllow()},
+			validateFunc: func(perms *natsserver.Permissions) {
+				s.NotNil(perms.Subscribe.Allow)
+				s.Empty(perms.Subscribe.Allow)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(s.permissions(tc.opts...))
+		})
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestSubscribeDeny() {
+	tests := []struct {
+		name         string
+		opts         []auth.UserOption
+		validateFunc func(perms *natsserver.Permissions)
+	}{
+		{
+			name: "denies subjects",
+			opts: []auth.UserOption{
+				auth.SubscribeDeny("secrets.>"),
+				auth.SubscribeDeny("admin.*"),
+			},
+			validateFunc: func(perms *natsserver.Permissions) {
+				s.Nil(perms.Subscribe.Allow)
+				s.Equal(
+					[]string{"secrets.>", "admin.*"},
+					perms.Subscribe.Deny,
+				)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(s.permissions(tc.opts...))
+		})
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestAllowResponses() {
+	tests := []struct {
+		name         string
+		opts         []auth.UserOption
+		validateFunc func(perms *natsserver.Permissions)
+	}{
+		{
+			name: "sets response permission",
+			opts: []auth.UserOption{
+				auth.AllowResponses(3, 5*time.Second),
+			},
+			validateFunc: func(perms *natsserver.Permissions) {
+				s.Equal(&natsserver.Permissions{
+					Response: &natsserver.ResponsePermission{
+						MaxMsgs: 3,
+						Expires: 5 * time.Second,
+					},
+				}, perms)
+			},
+		},
+		{
+			name: "leaves defaults to the nats server",
+			opts: []auth.UserOption{
+				auth.SubscribeAllow("svc.>"),
+				auth.AllowResponses(0, 0),
+			},
+			validateFunc: func(perms *natsserver.Permissions) {
+				s.Equal(
+					&natsserver.ResponsePermission{},
+					perms.Response,
+				)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(s.permissions(tc.opts...))
+		})
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestExportStream() {
+	tests := []struct {
+		name         string
+		builder      *auth.Builder
+		validateFunc func(built *auth.Auth, err error)
+	}{
+		{
+			name: "exports to every account",
+			builder: auth.New().
+				Account("a", auth.ExportStream("events.>")).
+				Account("b", auth.ImportStream("a", "events.>", "a")),
+			validateFunc: func(_ *auth.Auth, err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name: "exports to accounts named",
+			builder: auth.New().
+				Account("a", auth.ExportStream("events.>", "b")).
+				Account("b", auth.ImportStream("a", "events.>", "")).
+				Account("c", auth.ImportStream("a", "events.>", "")),
+			validateFunc: func(_ *auth.Auth, err error) {
+				s.ErrorContains(err, `account "c": error importing `+
+					`"events.>" from "a": stream import not authorized`)
+				s.NotContains(err.Error(), `account "b"`)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(tc.builder.Build())
+		})
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestExportService() {
+	tests := []struct {
+		name         string
+		builder      *auth.Builder
+		validateFunc func(built *auth.Auth, err error)
+	}{
+		{
+			name: "exports to every account",
+			builder: auth.New().
+				Account("a", auth.ExportService("svc.echo")).
+				Account("b", auth.ImportService("a", "svc.echo", "")),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Require().NoError(err)
+				s.True(built.Accounts[0].IsExportService("svc.echo"))
+				s.Equal(1, built.Accounts[1].NumServiceImports())
+			},
+		},
+		{
+			name: "exports to accounts named",
+			builder: auth.New().
+				Account("a", auth.ExportService("svc.echo", "b")).
+				Account("b").
+				Account("c", auth.ImportService("a", "svc.echo", "")),
+			validateFunc: func(_ *auth.Auth, err error) {
+				s.ErrorContains(err, `account "c": error importing `+
+					`"svc.echo" from "a": service import not authorized`)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(tc.builder.Build())
+		})
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestImportStream() {
+	tests := []struct {
+		name         string
+		builder      *auth.Builder
+		validateFunc func(built *auth.Auth, err error)
+	}{
+		{
+			name: "imports before export is declared",
+			builder: auth.New().
+				Account("b", auth.ImportStream("a", "events.>", "from.a")).
+				Account("a", auth.ExportStream("events.>")),
+			validateFunc: func(_ *auth.Auth, err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name: "rejects prefix with wildcards",
+			builder: auth.New().
+				Account("a", auth.ExportStream("events.>")).
+				Account("b", auth.ImportStream("a", "events.>", "a.*")),
+			validateFunc: func(_ *auth.Auth, err error) {
+				s.ErrorIs(err, natsserver.ErrStreamImportBadPrefix)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(tc.builder.Build())
+		})
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestImportService() {
+	tests := []struct {
+		name         string
+		builder      *auth.Builder
+		validateFunc func(built *auth.Auth, err error)
+	}{
+		{
+			name: "imports under local subject",
+			builder: auth.New().
+				Account("a", auth.ExportService("svc.echo")).
+				Account("b", auth.ImportService("a", "svc.echo", "echo")),
+			validateFunc: func(built *auth.Auth, err error) {
+				s.Require().NoError(err)
+				s.Equal(1, built.Accounts[1].NumServiceImports())
+			},
+		},
+		{
+			name: "rejects import of service not exported",
+			builder: auth.New().
+				Account("a").
+				Account("b", auth.ImportService("a", "svc.echo", "")),
+			validateFunc: func(_ *auth.Auth, err error) {
+				s.ErrorIs(err, natsserver.ErrServiceImportAuthorization)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(tc.builder.Build())
+		})
+	}
+}
+
+func TestOptionsPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(OptionsPublicTestSuite))
+}
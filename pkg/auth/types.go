@@ -0,0 +1,102 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package auth
+
+import (
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+)
+
+// Builder declares accounts and the users authenticating to them, and
+// builds them into the values natsserver.Options takes. Declarations may
+// be made in any order; problems with them are reported by Build.
+type Builder struct {
+	accounts      []*account
+	users         []*user
+	systemAccount string
+}
+
+// Auth is the authentication built by Builder.Build, as the fields of
+// natsserver.Options of the same names take it.
+type Auth struct {
+	Accounts      []*natsserver.Account
+	Users         []*natsserver.User
+	Nkeys         []*natsserver.NkeyUser
+	SystemAccount string
+}
+
+// AccountOption configures an account declared with Builder.Account.
+type AccountOption func(*account)
+
+// UserOption configures a user declared with Builder.User or
+// Builder.NkeyUser.
+type UserOption func(*user)
+
+// account is an account declared with Builder.Account.
+type account struct {
+	name    string
+	exports []export
+	imports []accountImport
+}
+
+// export is a stream or service an account offers to other accounts.
+type export struct {
+	service bool
+	subject string
+	// to names the accounts allowed to import it, or is empty when any
+	// account may.
+	to []string
+}
+
+// accountImport is a stream or service an account takes from another.
+type accountImport struct {
+	service bool
+	from    string
+	subject string
+	// to is the prefix of a stream or the local subject of a service.
+	to string
+}
+
+// user is a user declared with Builder.User or Builder.NkeyUser.
+type user struct {
+	// name is the username, or the public key of an nkey user.
+	name     string
+	nkey     bool
+	password string
+	account  string
+
+	publish   *subjects
+	subscribe *subjects
+	responses *responses
+}
+
+// subjects are the subjects a user may and may not use.
+type subjects struct {
+	allow []string
+	deny  []string
+}
+
+// responses is how a user may reply to requests it receives.
+type responses struct {
+	maxMsgs int
+	expires time.Duration
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Decode parses data in format into a Config. Unknown fields are errors, as
+// is a version other than Version, so typos and configurations written for
+// another schema are not silently ignored.
+func Decode(
+	data []byte,
+	format Format,
+) (*Config, error) {
+	c := &Config{}
+	if err := decode(data, format, c); err != nil {
+		return nil, fmt.Errorf("error decoding %s config: %w", format, err)
+	}
+
+	switch c.Version {
+	case Version:
+	case 0:
+		return nil, fmt.Errorf("error decoding %s config: missing version", format)
+	default:
+		return nil, fmt.Errorf(
+			"error decoding %s config: unsupported version %d",
+			format,
+			c.Version,
+		)
+	}
+
+	return c, nil
+}
+
+// decode strictly parses data in format into c. YAML and TOML are read
+// into generic values first and passed through JSON, so every format
+// shares the field names and checks of the JSON tags.
+func decode(
+	data []byte,
+	format Format,
+	c *Config,
+) error {
+	var value any
+	switch format {
+	case FormatJSON:
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	case FormatTOML:
+		if err := toml.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+
+	if format != FormatJSON {
+		var err error
+		if data, err = json.Marshal(value); err != nil {
+			return err
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	return dec.Decode(c)
+}
+
+// Encode writes c in format, setting its version to Version.
+func Encode(
+	c *Config,
+	format Format,
+) ([]byte, error) {
+	out := *c
+	out.Version = Version
+
+	data, err := encode(&out, format)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding %s config: %w", format, err)
+	}
+
+	return data, nil
+}
+
+// encode writes c in format. YAML and TOML are written from the generic
+// values c has in JSON, the reverse of decode.
+func encode(
+	c *Config,
+	format Format,
+) ([]byte, error) {
+	if format == FormatJSON {
+		data, err := json.MarshalIndent(c, "", "  ")
+
+		return append(data, '\n'), err
+	}
+
+	// Config holds nothing JSON cannot represent, so neither step fails.
+	data, _ := json.Marshal(c)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	_ = dec.Decode(&value)
+	value = integers(value)
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatYAML:
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		err = enc.Encode(value)
+	case FormatTOML:
+		err = toml.NewEncoder(&buf).Encode(value)
+	default:
+		err = fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// integers replaces the json.Number values within value with int64, so
+// encoders write them as integers. Config holds no other kind of number.
+func integers(
+	value any,
+) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, elem := range v {
+			v[key] = integers(elem)
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = integers(elem)
+		}
+	case json.Number:
+		n, _ := v.Int64()
+
+		return n
+	}
+
+	return value
+}
+
+// FormatOf returns the format of the file at path from its extension:
+// .yaml or .yml, .json, or .toml.
+func FormatOf(
+	path string,
+) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("unknown config format of %q", path)
+	}
+}
+
+// LoadFile reads and decodes the configuration file at path, in the format
+// its extension names.
+func LoadFile(
+	path string,
+) (*Config, error) {
+	format, err := FormatOf(path)
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+
+	c, err := Decode(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("error loading config %s: %w", path, err)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,377 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/config"
+)
+
+type CodecPublicTestSuite struct {
+	suite.Suite
+}
+
+func (s *CodecPublicTestSuite) TestDecode() {
+	tests := []struct {
+		name         string
+		data         string
+		format       config.Format
+		validateFunc func(c *config.Config, err error)
+	}{
+		{
+			name: "decodes yaml",
+			data: strings.Join([]string{
+				"version: 1",
+				"listen:",
+				"  port: 4333",
+				"ready_timeout: 3s",
+				"auth:",
+				"  users:",
+				"    - user: app",
+				"      password: password",
+			}, "\n"),
+			format: config.FormatYAML,
+			validateFunc: func(c *config.Config, err error) {
+				s.Require().NoError(err)
+				s.Equal(4333, c.Listen.Port)
+				s.Equal("3s", durationString(c.ReadyTimeout))
+				s.Equal("app", c.Auth.Users[0].User)
+			},
+		},
+		{
+			name:   "decodes json",
+			data:   `{"version": 1, "jetstream": {"enabled": true}}`,
+			format: config.FormatJSON,
+			validateFunc: func(c *config.Config, err error) {
+				s.Require().NoError(err)
+				s.True(c.JetStream.Enabled)
+			},
+		},
+		{
+			name:   "decodes toml",
+			data:   "version = 1\n\n[tls]\ncert_file = \"a.pem\"\ntimeout = \"2s\"\n",
+			format: config.FormatTOML,
+			validateFunc: func(c *config.Config, err error) {
+				s.Require().NoError(err)
+				s.Equal("a.pem", c.TLS.CertFile)
+				s.Equal("2s", durationString(c.TLS.Timeout))
+			},
+		},
+		{
+			name:   "returns error when yaml has unknown field",
+			data:   "version: 1\ncolour: blue\n",
+			format: config.FormatYAML,
+			validateFunc: func(c *config.Config, err error) {
+				s.Nil(c)
+				s.ErrorContains(err, "error decoding yaml config: ")
+				s.ErrorContains(err, `unknown field "colour"`)
+			},
+		},
+		{
+			name:   "returns error when json has unknown field",
+			data:   `{"version": 1, "colour": "blue"}`,
+			format: config.FormatJSON,
+			validateFunc: func(c *config.Config, err error) {
+				s.Nil(c)
+				s.ErrorContains(err, `json: unknown field "colour"`)
+			},
+		},
+		{
+			name:   "returns error when toml has unknown field",
+			data:   "version = 1\ncolour = \"blue\"\n",
+			format: config.FormatTOML,
+			validateFunc: func(c *config.Config, err error) {
+				s.Nil(c)
+				s.EqualError(
+					err,
+					`error decoding toml config: json: unknown field "colour"`,
+				)
+			},
+		},
+		{
+			name:   "returns error when toml is invalid",
+			data:   "version = \n",
+			format: config.FormatTOML,
+			validateFunc: func(c *config.Config, err error) {
+				s.Nil(c)
+				s.ErrorContains(err, "error decoding toml config: ")
+			},
+		},
+		{
+			name:   "returns error when yaml is invalid",
+			data:   "version: [\n",
+			format: config.FormatYAML,
+			validateFunc: func(c *config.Config, err error) {
+				s.Nil(c)
+				s.ErrorContains(err, "error decoding yaml config: yaml: ")
+			},
+		},
+		{
+			name:   "returns error when yaml value has no json equivalent",
+			data:   "version: .inf\n",
+			format: config.FormatYAML,
+			validateFunc: func(c *config.Config, err error) {
+				s.Nil(c)
+				s.ErrorContains(err, "error decoding yaml config: json: ")
+			},
+		},
+		{
+			name:   "returns error when duration is invalid",
+			data:   "version: 1\nready_timeout: soon\n",
+			format: config.FormatYAML,
+			validateFunc: func(c *config.Config, err error) {
+				s.Nil(c)
+				s.ErrorContains(err, "invalid duration")
+			},
+		},
+		{
+			name:   "returns error when version is missing",
+			data:   "",
+			format: config.FormatYAML,
+			validateFunc: func(c *config.Config, err error) {
+				s.Nil(c)
+				s.EqualError(err, "error decoding yaml config: missing version")
+			},
+		},
+		{
+			name:   "returns error when version is unsupported",
+			data:   `{"version": 2}`,
+			format: config.FormatJSON,
+			validateFunc: func(c *config.Config, err error) {
+				s.Nil(c)
+				s.EqualError(
+					err,
+					"error decoding json config: unsupported version 2",
+				)
+			},
+		},
+		{
+			name:   "returns error when format is unsupported",
+			data:   "version: 1",
+			format: config.Format("ini"),
+			validateFunc: func(c *config.Config, err error) {
+				s.Nil(c)
+				s.EqualError(
+					err,
+					`error decoding ini config: unsupported format "ini"`,
+				)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(config.Decode([]byte(tc.data), tc.format))
+		})
+	}
+}
+
+func (s *CodecPublicTestSuite) TestEncode() {
+	tests := []struct {
+		name         string
+		format       config.Format
+		validateFunc func(data []byte, err error)
+	}{
+		{
+			name:   "round trips yaml",
+			format: config.FormatYAML,
+			validateFunc: func(data []byte, err error) {
+				s.Require().NoError(err)
+				s.Contains(string(data), "ready_timeout: 3s\n")
+				s.roundTrip(data, config.FormatYAML)
+			},
+		},
+		{
+			name:   "round trips json",
+			format: config.FormatJSON,
+			validateFunc: func(data []byte, err error) {
+				s.Require().NoError(err)
+				s.Contains(string(data), "\n  \"ready_timeout\": \"3s\",\n")
+				s.roundTrip(data, config.FormatJSON)
+			},
+		},
+		{
+			name:   "round trips toml",
+			format: config.FormatTOML,
+			validateFunc: func(data []byte, err error) {
+				s.Require().NoError(err)
+				s.Contains(string(data), "ready_timeout = \"3s\"\n")
+				s.roundTrip(data, config.FormatTOML)
+			},
+		},
+		{
+			name:   "returns error when format is unsupported",
+			format: config.Format("ini"),
+			validateFunc: func(data []byte, err error) {
+				s.Nil(data)
+				s.EqualError(
+					err,
+					`error encoding ini config: unsupported format "ini"`,
+				)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			c := fullConfig()
+			c.Version = 0
+
+			tc.validateFunc(config.Encode(c, tc.format))
+			s.Zero(c.Version)
+		})
+	}
+}
+
+// roundTrip checks that data decodes back to fullConfig.
+func (s *CodecPublicTestSuite) roundTrip(
+	data []byte,
+	format config.Format,
+) {
+	c, err := config.Decode(data, format)
+	s.Require().NoError(err)
+	s.Equal(fullConfig(), c)
+}
+
+func (s *CodecPublicTestSuite) TestFormatOf() {
+	tests := []struct {
+		name         string
+		path         string
+		validateFunc func(format config.Format, err error)
+	}{
+		{
+			name: "reads yaml extension",
+			path: "nats.YML",
+			validateFunc: func(format config.Format, err error) {
+				s.Require().NoError(err)
+				s.Equal(config.FormatYAML, format)
+			},
+		},
+		{
+			name: "reads json extension",
+			path: "nats.json",
+			validateFunc: func(format config.Format, err error) {
+				s.Require().NoError(err)
+				s.Equal(config.FormatJSON, format)
+			},
+		},
+		{
+			name: "reads toml extension",
+			path: "nats.toml",
+			validateFunc: func(format config.Format, err error) {
+				s.Require().NoError(err)
+				s.Equal(config.FormatTOML, format)
+			},
+		},
+		{
+			name: "returns error when extension is unknown",
+			path: "nats.conf",
+			validateFunc: func(format config.Format, err error) {
+				s.Empty(format)
+				s.EqualError(err, `unknown config format of "nats.conf"`)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(config.FormatOf(tc.path))
+		})
+	}
+}
+
+func (s *CodecPublicTestSuite) TestLoadFile() {
+	tests := []struct {
+		name         string
+		file         string
+		content      string
+		validateFunc func(c *config.Config, err error)
+	}{
+		{
+			name:    "loads file",
+			file:    "nats.yaml",
+			content: "version: 1\nserver_name: embedded\n",
+			validateFunc: func(c *config.Config, err error) {
+				s.Require().NoError(err)
+				s.Equal("embedded", c.ServerName)
+			},
+		},
+		{
+			name:    "returns error when format is unknown",
+			file:    "nats.conf",
+			content: "port: 4222\n",
+			validateFunc: func(c *config.Config, err error) {
+				s.Nil(c)
+				s.ErrorContains(err, "error loading config: unknown config format")
+			},
+		},
+		{
+			name: "returns error when file is missing",
+			file: "nats.json",
+			validateFunc: func(c *config.Config, err error) {
+				s.Nil(c)
+				s.ErrorIs(err, os.ErrNotExist)
+			},
+		},
+		{
+			name:    "returns error with path when file is invalid",
+			file:    "nats.toml",
+			content: "version = 2\n",
+			validateFunc: func(c *config.Config, err error) {
+				s.Nil(c)
+				s.ErrorContains(err, "nats.toml: error decoding toml config: "+
+					"unsupported version 2")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			path := filepath.Join(s.T().TempDir(), tc.file)
+			if tc.content != "" {
+				s.Require().NoError(
+					os.WriteFile(path, []byte(tc.content), 0o600),
+				)
+			}
+
+			tc.validateFunc(config.LoadFile(path))
+		})
+	}
+}
+
+// durationString returns d written as a string.
+func durationString(
+	d config.Duration,
+) string {
+	text, _ := d.MarshalText()
+
+	return string(text)
+}
+
+func TestCodecPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(CodecPublicTestSuite))
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/config"
+)
+
+type DurationPublicTestSuite struct {
+	suite.Suite
+}
+
+func (s *DurationPublicTestSuite) TestMarshalText() {
+	tests := []struct {
+		name         string
+		d            config.Duration
+		validateFunc func(text []byte, err error)
+	}{
+		{
+			name: "writes duration as string",
+			d:    config.Duration(90 * time.Second),
+			validateFunc: func(text []byte, err error) {
+				s.Require().NoError(err)
+				s.Equal("1m30s", string(text))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(tc.d.MarshalText())
+		})
+	}
+}
+
+func (s *DurationPublicTestSuite) TestUnmarshalText() {
+	tests := []struct {
+		name         string
+		text         string
+		validateFunc func(d config.Duration, err error)
+	}{
+		{
+			name: "reads duration string",
+			text: "1m30s",
+			validateFunc: func(d config.Duration, err error) {
+				s.Require().NoError(err)
+				s.Equal(config.Duration(90*time.Second), d)
+			},
+		},
+		{
+			name: "returns error when duration is invalid",
+			text: "soon",
+			validateFunc: func(_ config.Duration, err error) {
+				s.ErrorContains(err, "invalid duration: time: invalid duration")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			var d config.Duration
+			err := d.UnmarshalText([]byte(tc.text))
+			tc.validateFunc(d, err)
+		})
+	}
+}
+
+func TestDurationPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(DurationPublicTestSuite))
+}
@@ -0,0 +1,341 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+// Options maps the configuration to server.Options. Settings left out of
+// the configuration keep the defaults of the NATS server, except
+// ReadyTimeout, which defaults to server.DefaultReadyTimeout.
+func (c *Config) Options() (*server.Options, error) {
+	natsOpts := &natsserver.Options{
+		ServerName:    c.ServerName,
+		SystemAccount: c.SystemAccount,
+	}
+	opts := &server.Options{
+		Options:      natsOpts,
+		ReadyTimeout: server.DefaultReadyTimeout,
+		DrainOnStop:  c.DrainOnStop,
+		AllowRestart: c.AllowRestart,
+	}
+	if c.ReadyTimeout != 0 {
+		opts.ReadyTimeout = time.Duration(c.ReadyTimeout)
+	}
+
+	if c.Listen != nil {
+		natsOpts.Host = c.Listen.Host
+		natsOpts.Port = c.Listen.Port
+	}
+
+	accounts := make(map[string]*natsserver.Account, len(c.Accounts))
+	for _, a := range c.Accounts {
+		acc := natsserver.NewAccount(a.Name)
+		accounts[a.Name] = acc
+		natsOpts.Accounts = append(natsOpts.Accounts, acc)
+	}
+
+	var errs []error
+	if c.Auth != nil {
+		errs = append(errs, applyAuth(natsOpts, c.Auth, accounts)...)
+	}
+
+	if c.JetStream != nil {
+		natsOpts.JetStream = c.JetStream.Enabled
+		natsOpts.StoreDir = c.JetStream.StoreDir
+		natsOpts.JetStreamMaxMemory = c.JetStream.MaxMemory
+		natsOpts.JetStreamMaxStore = c.JetStream.MaxStore
+	}
+
+	if c.TLS != nil {
+		opts.TLSFiles = &server.TLSFiles{
+			CertFile: c.TLS.CertFile,
+			KeyFile:  c.TLS.KeyFile,
+			CAFile:   c.TLS.CAFile,
+			Verify:   c.TLS.Verify,
+		}
+		natsOpts.TLSTimeout = time.Duration(c.TLS.Timeout).Seconds()
+	}
+
+	if c.Cluster != nil {
+		errs = append(errs, applyCluster(natsOpts, c.Cluster)...)
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+
+	return opts, nil
+}
+
+// applyAuth sets the authentication of natsOpts from auth, binding users
+// to accounts by name.
+func applyAuth(
+	natsOpts *natsserver.Options,
+	auth *Auth,
+	accounts map[string]*natsserver.Account,
+) []error {
+	var errs []error
+	account := func(user string, name string) *natsserver.Account {
+		if name == "" {
+			return nil
+		}
+
+		acc, ok := accounts[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf(
+				"user %q references unknown account %q",
+				user,
+				name,
+			))
+		}
+
+		return acc
+	}
+
+	natsOpts.Authorization = auth.Token
+	for _, u := range auth.Users {
+		natsOpts.Users = append(natsOpts.Users, &natsserver.User{
+			Username:    u.User,
+			Password:    u.Password,
+			Account:     account(u.User, u.Account),
+			Permissions: u.Permissions.natsPermissions(),
+		})
+	}
+	for _, u := range auth.Nkeys {
+		natsOpts.Nkeys = append(natsOpts.Nkeys, &natsserver.NkeyUser{
+			Nkey:        u.Nkey,
+			Account:     account(u.Nkey, u.Account),
+			Permissions: u.Permissions.natsPermissions(),
+		})
+	}
+
+	return errs
+}
+
+// applyCluster sets the cluster settings of natsOpts from cluster.
+func applyCluster(
+	natsOpts *natsserver.Options,
+	cluster *Cluster,
+) []error {
+	natsOpts.Cluster.Name = cluster.Name
+	natsOpts.Cluster.Host = cluster.Host
+	natsOpts.Cluster.Port = cluster.Port
+
+	var errs []error
+	for _, route := range cluster.Routes {
+		u, err := url.Parse(route)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid cluster route: %w", err))
+			continue
+		}
+		natsOpts.Routes = append(natsOpts.Routes, u)
+	}
+
+	return errs
+}
+
+// natsPermissions returns the permissions as the NATS server takes them.
+func (p *Permissions) natsPermissions() *natsserver.Permissions {
+	if p == nil {
+		return nil
+	}
+
+	perms := &natsserver.Permissions{
+		Publish:   p.Publish.natsPermission(),
+		Subscribe: p.Subscribe.natsPermission(),
+	}
+	if p.AllowResponses != nil {
+		perms.Response = &natsserver.ResponsePermission{
+			MaxMsgs: p.AllowResponses.MaxMsgs,
+			Expires: time.Duration(p.AllowResponses.Expires),
+		}
+	}
+
+	return perms
+}
+
+// natsPermission returns the permission as the NATS server takes it.
+func (p *SubjectPermission) natsPermission() *natsserver.SubjectPermission {
+	if p == nil {
+		return nil
+	}
+
+	return &natsserver.SubjectPermission{
+		Allow: p.Allow,
+		Deny:  p.Deny,
+	}
+}
+
+// FromOptions maps opts to a configuration, the reverse of Options.
+// Settings outside the schema are left out. TLS is only written out when
+// given as Options.TLSFiles, since a tls.Config cannot be turned back into
+// files.
+func FromOptions(
+	opts *server.Options,
+) (*Config, error) {
+	natsOpts := opts.Options
+	if natsOpts == nil {
+		natsOpts = &natsserver.Options{}
+	}
+	if natsOpts.TLSConfig != nil && opts.TLSFiles == nil {
+		return nil, errors.New(
+			"tls config cannot be written out: use server.TLSFiles",
+		)
+	}
+
+	c := &Config{
+		Version:       Version,
+		ServerName:    natsOpts.ServerName,
+		ReadyTimeout:  Duration(opts.ReadyTimeout),
+		DrainOnStop:   opts.DrainOnStop,
+		AllowRestart:  opts.AllowRestart,
+		SystemAccount: natsOpts.SystemAccount,
+	}
+
+	if natsOpts.Host != "" || natsOpts.Port != 0 {
+		c.Listen = &Listen{Host: natsOpts.Host, Port: natsOpts.Port}
+	}
+
+	for _, acc := range natsOpts.Accounts {
+		c.Accounts = append(c.Accounts, Account{Name: acc.Name})
+	}
+
+	if natsOpts.Authorization != "" || len(natsOpts.Users) > 0 ||
+		len(natsOpts.Nkeys) > 0 {
+		c.Auth = authFromOptions(natsOpts)
+	}
+
+	if natsOpts.JetStream || natsOpts.StoreDir != "" ||
+		natsOpts.JetStreamMaxMemory != 0 || natsOpts.JetStreamMaxStore != 0 {
+		c.JetStream = &JetStream{
+			Enabled:   natsOpts.JetStream,
+			StoreDir:  natsOpts.StoreDir,
+			MaxMemory: natsOpts.JetStreamMaxMemory,
+			MaxStore:  natsOpts.JetStreamMaxStore,
+		}
+	}
+
+	if opts.TLSFiles != nil {
+		c.TLS = &TLS{
+			CertFile: opts.TLSFiles.CertFile,
+			KeyFile:  opts.TLSFiles.KeyFile,
+			CAFile:   opts.TLSFiles.CAFile,
+			Verify:   opts.TLSFiles.Verify,
+			Timeout: Duration(
+				natsOpts.TLSTimeout * float64(time.Second),
+			),
+		}
+	}
+
+	if natsOpts.Cluster.Name != "" || natsOpts.Cluster.Host != "" ||
+		natsOpts.Cluster.Port != 0 || len(natsOpts.Routes) > 0 {
+		c.Cluster = &Cluster{
+			Name: natsOpts.Cluster.Name,
+			Host: natsOpts.Cluster.Host,
+			Port: natsOpts.Cluster.Port,
+		}
+		for _, route := range natsOpts.Routes {
+			c.Cluster.Routes = append(c.Cluster.Routes, route.String())
+		}
+	}
+
+	return c, nil
+}
+
+// authFromOptions returns the authentication settings of natsOpts.
+func authFromOptions(
+	natsOpts *natsserver.Options,
+) *Auth {
+	auth := &Auth{Token: natsOpts.Authorization}
+	for _, u := range natsOpts.Users {
+		auth.Users = append(auth.Users, User{
+			User:        u.Username,
+			Password:    u.Password,
+			Account:     accountName(u.Account),
+			Permissions: permissionsFromOptions(u.Permissions),
+		})
+	}
+	for _, u := range natsOpts.Nkeys {
+		auth.Nkeys = append(auth.Nkeys, NkeyUser{
+			Nkey:        u.Nkey,
+			Account:     accountName(u.Account),
+			Permissions: permissionsFromOptions(u.Permissions),
+		})
+	}
+
+	return auth
+}
+
+// accountName returns the name of acc, or "" for no account.
+func accountName(
+	acc *natsserver.Account,
+) string {
+	if acc == nil {
+		return ""
+	}
+
+	return acc.Name
+}
+
+// permissionsFromOptions returns perms as the configuration holds them.
+func permissionsFromOptions(
+	perms *natsserver.Permissions,
+) *Permissions {
+	if perms == nil {
+		return nil
+	}
+
+	p := &Permissions{
+		Publish:   subjectPermissionFromOptions(perms.Publish),
+		Subscribe: subjectPermissionFromOptions(perms.Subscribe),
+	}
+	if perms.Response != nil {
+		p.AllowResponses = &ResponsePermission{
+			MaxMsgs: perms.Response.MaxMsgs,
+			Expires: Duration(perms.Response.Expires),
+		}
+	}
+
+	return p
+}
+
+// subjectPermissionFromOptions returns perm as the configuration holds it.
+func subjectPermissionFromOptions(
+	perm *natsserver.SubjectPermission,
+) *SubjectPermission {
+	if perm == nil {
+		return nil
+	}
+
+	return &SubjectPermission{
+		Allow: perm.Allow,
+		Deny:  perm.Deny,
+	}
+}
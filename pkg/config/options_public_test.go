@@ -0,0 +1,295 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package config_test
+
+import (
+	"crypto/tls"
+	"net/url"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/config"
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+const nkeyUser = "UDXU4RCSJNZOIQHZNWXHXORDPRTGNJAHAHFRGZNEEJCPQTT2M7NLCNF4"
+
+type OptionsPublicTestSuite struct {
+	suite.Suite
+}
+
+// fullConfig returns a configuration setting every field of the schema.
+func fullConfig() *config.Config {
+	perms := &config.Permissions{
+		Publish: &config.SubjectPermission{
+			Allow: []string{"orders.>"},
+			Deny:  []string{"orders.secret"},
+		},
+		Subscribe: &config.SubjectPermission{Allow: []string{"_INBOX.>"}},
+		AllowResponses: &config.ResponsePermission{
+			MaxMsgs: 1,
+			Expires: config.Duration(time.Minute),
+		},
+	}
+
+	return &config.Config{
+		Version:      config.Version,
+		ServerName:   "embedded",
+		Listen:       &config.Listen{Host: "127.0.0.1", Port: 4333},
+		ReadyTimeout: config.Duration(3 * time.Second),
+		DrainOnStop:  true,
+		AllowRestart: true,
+		Auth: &config.Auth{
+			Token: "s3cr3t",
+			Users: []config.User{
+				{
+					User:        "app",
+					Password:    "password",
+					Account:     "APP",
+					Permissions: perms,
+				},
+				{User: "admin", Password: "admin"},
+			},
+			Nkeys: []config.NkeyUser{
+				{
+					Nkey:    nkeyUser,
+					Account: "APP",
+					Permissions: &config.Permissions{
+						Subscribe: &config.SubjectPermission{
+							Allow: []string{"orders.>"},
+						},
+					},
+				},
+			},
+		},
+		Accounts:      []config.Account{{Name: "APP"}, {Name: "SYS"}},
+		SystemAccount: "SYS",
+		JetStream: &config.JetStream{
+			Enabled:   true,
+			StoreDir:  "/var/lib/nats",
+			MaxMemory: 1 << 20,
+			MaxStore:  1 << 30,
+		},
+		TLS: &config.TLS{
+			CertFile: "server.pem",
+			KeyFile:  "server-key.pem",
+			CAFile:   "ca.pem",
+			Verify:   true,
+			Timeout:  config.Duration(2 * time.Second),
+		},
+		Cluster: &config.Cluster{
+			Name:   "east",
+			Host:   "0.0.0.0",
+			Port:   6222,
+			Routes: []string{"nats-route://10.0.0.2:6222"},
+		},
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestOptions() {
+	tests := []struct {
+		name         string
+		config       *config.Config
+		validateFunc func(opts *server.Options, err error)
+	}{
+		{
+			name:   "maps every field",
+			config: fullConfig(),
+			validateFunc: func(opts *server.Options, err error) {
+				s.Require().NoError(err)
+				s.Equal("embedded", opts.ServerName)
+				s.Equal("127.0.0.1", opts.Host)
+				s.Equal(4333, opts.Port)
+				s.Equal(3*time.Second, opts.ReadyTimeout)
+				s.True(opts.DrainOnStop)
+				s.True(opts.AllowRestart)
+
+				s.Equal("s3cr3t", opts.Authorization)
+				s.Require().Len(opts.Accounts, 2)
+				s.Equal("SYS", opts.SystemAccount)
+
+				s.Require().Len(opts.Users, 2)
+				app := opts.Users[0]
+				s.Equal("app", app.Username)
+				s.Equal("password", app.Password)
+				s.Same(opts.Accounts[0], app.Account)
+				s.Equal([]string{"orders.>"}, app.Permissions.Publish.Allow)
+				s.Equal([]string{"orders.secret"}, app.Permissions.Publish.Deny)
+				s.Equal([]string{"_INBOX.>"}, app.Permissions.Subscribe.Allow)
+				s.Equal(&natsserver.ResponsePermission{
+					MaxMsgs: 1,
+					Expires: time.Minute,
+				}, app.Permissions.Response)
+				s.Nil(opts.Users[1].Account)
+				s.Nil(opts.Users[1].Permissions)
+
+				s.Require().Len(opts.Nkeys, 1)
+				s.Equal(nkeyUser, opts.Nkeys[0].Nkey)
+				s.Same(opts.Accounts[0], opts.Nkeys[0].Account)
+				s.Nil(opts.Nkeys[0].Permissions.Publish)
+
+				s.True(opts.JetStream)
+				s.Equal("/var/lib/nats", opts.StoreDir)
+				s.Equal(int64(1<<20), opts.JetStreamMaxMemory)
+				s.Equal(int64(1<<30), opts.JetStreamMaxStore)
+
+				s.Equal(&server.TLSFiles{
+					CertFile: "server.pem",
+					KeyFile:  "server-key.pem",
+					CAFile:   "ca.pem",
+					Verify:   true,
+				}, opts.TLSFiles)
+				s.InDelta(2.0, opts.TLSTimeout, 0)
+
+				s.Equal("east", opts.Cluster.Name)
+				s.Equal("0.0.0.0", opts.Cluster.Host)
+				s.Equal(6222, opts.Cluster.Port)
+				s.Require().Len(opts.Routes, 1)
+				s.Equal("nats-route://10.0.0.2:6222", opts.Routes[0].String())
+			},
+		},
+		{
+			name:   "uses default ready timeout",
+			config: &config.Config{Version: config.Version},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Require().NoError(err)
+				s.Equal(server.DefaultReadyTimeout, opts.ReadyTimeout)
+				s.Nil(opts.TLSFiles)
+				s.Empty(opts.Users)
+			},
+		},
+		{
+			name: "returns every error",
+			config: &config.Config{
+				Version: config.Version,
+				Auth: &config.Auth{
+					Users: []config.User{
+						{User: "app", Password: "password", Account: "APP"},
+					},
+					Nkeys: []config.NkeyUser{{Nkey: nkeyUser, Account: "OPS"}},
+				},
+				Cluster: &config.Cluster{Routes: []string{"nats://%zz"}},
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Nil(opts)
+				s.ErrorContains(err, "invalid config: ")
+				s.ErrorContains(err, `user "app" references unknown account "APP"`)
+				s.ErrorContains(err, "references unknown account \"OPS\"")
+				s.ErrorContains(err, "invalid cluster route: ")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(tc.config.Options())
+		})
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestFromOptions() {
+	tests := []struct {
+		name         string
+		opts         func() *server.Options
+		validateFunc func(c *config.Config, err error)
+	}{
+		{
+			name: "reverses options",
+			opts: func() *server.Options {
+				opts, err := fullConfig().Options()
+				s.Require().NoError(err)
+
+				return opts
+			},
+			validateFunc: func(c *config.Config, err error) {
+				s.Require().NoError(err)
+				s.Equal(fullConfig(), c)
+			},
+		},
+		{
+			name: "leaves out unset sections",
+			opts: func() *server.Options {
+				return &server.Options{ReadyTimeout: time.Second}
+			},
+			validateFunc: func(c *config.Config, err error) {
+				s.Require().NoError(err)
+				s.Equal(&config.Config{
+					Version:      config.Version,
+					ReadyTimeout: config.Duration(time.Second),
+				}, c)
+			},
+		},
+		{
+			name: "writes sections set by a single field",
+			opts: func() *server.Options {
+				natsOpts := &natsserver.Options{
+					Port:              4222,
+					Nkeys:             []*natsserver.NkeyUser{{Nkey: nkeyUser}},
+					JetStreamMaxStore: 1 << 30,
+				}
+				natsOpts.Routes = []*url.URL{{Scheme: "nats", Host: "a:6222"}}
+
+				return &server.Options{Options: natsOpts}
+			},
+			validateFunc: func(c *config.Config, err error) {
+				s.Require().NoError(err)
+				s.Equal(&config.Listen{Port: 4222}, c.Listen)
+				s.Equal(
+					&config.Auth{Nkeys: []config.NkeyUser{{Nkey: nkeyUser}}},
+					c.Auth,
+				)
+				s.Equal(&config.JetStream{MaxStore: 1 << 30}, c.JetStream)
+				s.Equal(
+					&config.Cluster{Routes: []string{"nats://a:6222"}},
+					c.Cluster,
+				)
+			},
+		},
+		{
+			name: "returns error when tls is not given as files",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{TLSConfig: &tls.Config{}},
+				}
+			},
+			validateFunc: func(c *config.Config, err error) {
+				s.Nil(c)
+				s.EqualError(
+					err,
+					"tls config cannot be written out: use server.TLSFiles",
+				)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(config.FromOptions(tc.opts()))
+		})
+	}
+}
+
+func TestOptionsPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(OptionsPublicTestSuite))
+}
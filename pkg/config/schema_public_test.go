@@ -0,0 +1,122 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package config_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/config"
+)
+
+type SchemaPublicTestSuite struct {
+	suite.Suite
+}
+
+func (s *SchemaPublicTestSuite) TestSchema() {
+	tests := []struct {
+		name         string
+		validateFunc func(schema []byte)
+	}{
+		{
+			name: "describes every config field",
+			validateFunc: func(schema []byte) {
+				var doc map[string]any
+				s.Require().NoError(json.Unmarshal(schema, &doc))
+
+				s.Equal(
+					jsonFields(reflect.TypeFor[config.Config]()),
+					schemaFields(doc, doc),
+				)
+			},
+		},
+		{
+			name: "returns a copy",
+			validateFunc: func(schema []byte) {
+				schema[0] = 'x'
+				s.Equal(byte('{'), config.Schema()[0])
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(config.Schema())
+		})
+	}
+}
+
+// jsonFields returns the JSON field names of t, with the fields of nested
+// structs, as a tree.
+func jsonFields(
+	t reflect.Type,
+) map[string]any {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := map[string]any{}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		fields[name] = jsonFields(field.Type)
+	}
+
+	return fields
+}
+
+// schemaFields returns the property names of the schema node, with the
+// properties of nested objects, as a tree, following references into root.
+func schemaFields(
+	root map[string]any,
+	node map[string]any,
+) map[string]any {
+	if ref, ok := node["$ref"].(string); ok {
+		defs := root["$defs"].(map[string]any)
+		node = defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+	}
+	if items, ok := node["items"].(map[string]any); ok {
+		node = items
+	}
+
+	props, ok := node["properties"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	fields := map[string]any{}
+	for name, prop := range props {
+		fields[name] = schemaFields(root, prop.(map[string]any))
+	}
+
+	return fields
+}
+
+func TestSchemaPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(SchemaPublicTestSuite))
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package config
+
+import "time"
+
+// Version is the version of the configuration schema this package reads
+// and writes.
+const Version = 1
+
+// Format is an encoding of a Config.
+type Format string
+
+// Supported formats.
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+	FormatTOML Format = "toml"
+)
+
+// Config is the configuration schema of an embedded NATS server, mapped to
+// server.Options by Options and back by FromOptions. Field names are those
+// of the JSON tags in every format.
+type Config struct {
+	// Version is the schema version, which must be Version.
+	Version int `json:"version"`
+
+	ServerName string  `json:"server_name,omitempty"`
+	Listen     *Listen `json:"listen,omitempty"`
+
+	// ReadyTimeout defaults to server.DefaultReadyTimeout.
+	ReadyTimeout Duration `json:"ready_timeout,omitempty"`
+	DrainOnStop  bool     `json:"drain_on_stop,omitempty"`
+	AllowRestart bool     `json:"allow_restart,omitempty"`
+
+	Auth          *Auth     `json:"auth,omitempty"`
+	Accounts      []Account `json:"accounts,omitempty"`
+	SystemAccount string    `json:"system_account,omitempty"`
+
+	JetStream *JetStream `json:"jetstream,omitempty"`
+	TLS       *TLS       `json:"tls,omitempty"`
+	Cluster   *Cluster   `json:"cluster,omitempty"`
+}
+
+// Listen is where the server listens for clients.
+type Listen struct {
+	Host string `json:"host,omitempty"`
+	// Port is picked at random when -1.
+	Port int `json:"port,omitempty"`
+}
+
+// Auth is how clients authenticate.
+type Auth struct {
+	Token string     `json:"token,omitempty"`
+	Users []User     `json:"users,omitempty"`
+	Nkeys []NkeyUser `json:"nkeys,omitempty"`
+}
+
+// User is a client authenticating with a username and password.
+type User struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+	// Account names one of Config.Accounts; empty is the global account.
+	Account     string       `json:"account,omitempty"`
+	Permissions *Permissions `json:"permissions,omitempty"`
+}
+
+// NkeyUser is a client authenticating with an nkey.
+type NkeyUser struct {
+	Nkey string `json:"nkey"`
+	// Account names one of Config.Accounts; empty is the global account.
+	Account     string       `json:"account,omitempty"`
+	Permissions *Permissions `json:"permissions,omitempty"`
+}
+
+// Permissions are the subjects a user may publish and subscribe to.
+type Permissions struct {
+	Publish   *SubjectPermission `json:"publish,omitempty"`
+	Subscribe *SubjectPermission `json:"subscribe,omitempty"`
+	// AllowResponses lets the user publish replies to requests it
+	// received.
+	AllowResponses *ResponsePermission `json:"allow_responses,omitempty"`
+}
+
+// SubjectPermission lists allowed and denied subjects, which may use
+// wildcards.
+type SubjectPermission struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// ResponsePermission bounds the replies a user may publish per request.
+type ResponsePermission struct {
+	MaxMsgs int      `json:"max_msgs,omitempty"`
+	Expires Duration `json:"expires,omitempty"`
+}
+
+// Account is an account users can be bound to.
+type Account struct {
+	Name string `json:"name"`
+}
+
+// JetStream configures JetStream.
+type JetStream struct {
+	Enabled  bool   `json:"enabled,omitempty"`
+	StoreDir string `json:"store_dir,omitempty"`
+	// MaxMemory and MaxStore are storage limits in bytes.
+	MaxMemory int64 `json:"max_memory,omitempty"`
+	MaxStore  int64 `json:"max_store,omitempty"`
+}
+
+// TLS secures client connections with PEM files.
+type TLS struct {
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+	CAFile   string `json:"ca_file,omitempty"`
+	// Verify requires clients to present a verified certificate.
+	Verify  bool     `json:"verify,omitempty"`
+	Timeout Duration `json:"timeout,omitempty"`
+}
+
+// Cluster configures clustering with other servers.
+type Cluster struct {
+	Name string `json:"name,omitempty"`
+	Host string `json:"host,omitempty"`
+	Port int    `json:"port,omitempty"`
+	// Routes are URLs of other servers in the cluster, such as
+	// "nats-route://10.0.0.2:6222".
+	Routes []string `json:"routes,omitempty"`
+}
+
+// Duration is a time.Duration written as a string such as "5s".
+type Duration time.Duration
@@ -0,0 +1,237 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"fmt"
+
+	"github.com/nats-io/jwt/v2"
+)
+
+// AccountJWT returns the current JWT of the account name of the running
+// server's operator.
+func (s *Server) AccountJWT(
+	name string,
+) (string, error) {
+	r, err := s.operatorRun()
+	if err != nil {
+		return "", fmt.Errorf("error getting account %q: %w", name, err)
+	}
+
+	r.operator.mu.Lock()
+	defer r.operator.mu.Unlock()
+
+	acc := r.operator.accounts[name]
+	if acc == nil {
+		return "", fmt.Errorf(
+			"error getting account %q: %w",
+			name,
+			ErrUnknownAccount,
+		)
+	}
+
+	return acc.token, nil
+}
+
+// AddAccountJWT creates the account name, signed by the operator, and
+// pushes its JWT to the running server, returning the account's public
+// key. The account has unlimited JetStream when the server runs with
+// JetStream. Once the server accepted it, it is kept with the operator's
+// other accounts, in Operator.Dir when set, for later starts.
+func (s *Server) AddAccountJWT(
+	name string,
+) (string, error) {
+	r, err := s.operatorRun()
+	if err == nil && !validAccountName(name) {
+		err = fmt.Errorf("invalid account name")
+	}
+	if err != nil {
+		return "", fmt.Errorf("error adding account %q: %w", name, err)
+	}
+
+	r.operator.mu.Lock()
+	defer r.operator.mu.Unlock()
+
+	if r.operator.accounts[name] != nil {
+		return "", fmt.Errorf(
+			"error adding account %q: %w",
+			name,
+			ErrAccountExists,
+		)
+	}
+
+	// The JWT is only saved once pushed, so an account the server refused
+	// is not loaded on later starts.
+	var token string
+	acc, err := r.operator.accountKey(name)
+	if err == nil {
+		token, err = r.operator.createAccount(
+			name,
+			acc,
+			s.options().JetStream,
+		)
+	}
+	if err == nil {
+		err = r.push(acc.publicKey(), token)
+	}
+	if err == nil {
+		err = r.operator.save(name, acc, token)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error adding account %q: %w", name, err)
+	}
+	r.operator.accounts[name] = acc
+
+	return acc.publicKey(), nil
+}
+
+// UpdateAccountJWT changes the claims of the account name with change,
+// signs them with the operator's key, and pushes the new JWT to the
+// running server. Clients of the account are held to the new claims
+// without reconnecting, and the clients of users revoked with
+// jwt.AccountClaims.Revoke are disconnected. The account's key cannot be
+// changed.
+func (s *Server) UpdateAccountJWT(
+	name string,
+	change func(claims *jwt.AccountClaims),
+) error {
+	r, err := s.operatorRun()
+	if err != nil {
+		return fmt.Errorf("error updating account %q: %w", name, err)
+	}
+
+	if err := r.update(name, change); err != nil {
+		return fmt.Errorf("error updating account %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// RevokeAccountJWT revokes the account name on the running server by
+// pushing a JWT allowing it no connections, which disconnects its clients
+// and refuses new ones. UpdateAccountJWT restoring the limits reinstates
+// it. The system account cannot be revoked.
+func (s *Server) RevokeAccountJWT(
+	name string,
+) error {
+	r, err := s.operatorRun()
+	if err == nil && name == SystemAccountName {
+		err = fmt.Errorf("cannot revoke the system account")
+	}
+	if err == nil {
+		err = r.update(name, func(claims *jwt.AccountClaims) {
+			claims.Limits.Conn = 0
+			claims.Limits.LeafNodeConn = 0
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("error revoking account %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// PushAccountJWT pushes token, an account JWT signed elsewhere with the
+// operator's key, to the running server. When the account is one of the
+// operator's, token replaces its JWT; other accounts are only known to the
+// account resolver.
+func (s *Server) PushAccountJWT(
+	token string,
+) error {
+	r, err := s.operatorRun()
+	if err != nil {
+		return fmt.Errorf("error pushing account jwt: %w", err)
+	}
+
+	claims, err := jwt.DecodeAccountClaims(token)
+	if err == nil {
+		err = r.operator.check(claims)
+	}
+	if err != nil {
+		return fmt.Errorf("error pushing account jwt: %w", err)
+	}
+
+	// The push is a request to the server, made before taking the lock so
+	// it does not hold up the operator's other accounts.
+	if err := r.push(claims.Subject, token); err != nil {
+		return err
+	}
+
+	r.operator.mu.Lock()
+	defer r.operator.mu.Unlock()
+
+	for name, acc := range r.operator.accounts {
+		if acc.publicKey() == claims.Subject {
+			return r.operator.save(name, acc, token)
+		}
+	}
+
+	return nil
+}
+
+// operatorRun returns the operator mode of the running server.
+func (s *Server) operatorRun() (*operatorRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state != StateRunning {
+		return nil, ErrNotRunning
+	}
+
+	if s.run.operator == nil {
+		return nil, ErrNotOperatorMode
+	}
+
+	return s.run.operator, nil
+}
+
+// update changes the claims of the account name with change, then signs,
+// pushes, and saves them.
+func (r *operatorRun) update(
+	name string,
+	change func(claims *jwt.AccountClaims),
+) error {
+	r.operator.mu.Lock()
+	defer r.operator.mu.Unlock()
+
+	acc := r.operator.accounts[name]
+	if acc == nil {
+		return ErrUnknownAccount
+	}
+
+	// The JWTs of the operator's accounts are always valid.
+	claims, _ := jwt.DecodeAccountClaims(acc.token)
+	change(claims)
+	claims.Subject = acc.publicKey()
+	if err := r.operator.check(claims); err != nil {
+		return err
+	}
+
+	token, err := r.operator.sign(claims)
+	if err != nil {
+		return err
+	}
+	if err := r.push(claims.Subject, token); err != nil {
+		return err
+	}
+
+	return r.operator.save(name, acc, token)
+}
@@ -0,0 +1,351 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+)
+
+// newAuthCallout returns the auth callout service for a run of the
+// server with opts, with a new issuer key and password, or nil when opts
+// has no AuthCallout.
+func (s *Server) newAuthCallout(
+	opts *Options,
+) (*authCallout, error) {
+	if opts.AuthCallout == nil {
+		return nil, nil
+	}
+
+	issuer, err := nkeys.CreateAccount()
+	if err != nil {
+		return nil, fmt.Errorf("error creating auth callout issuer key: %w", err)
+	}
+
+	return &authCallout{
+		server:   s,
+		account:  opts.AuthCallout.account(),
+		password: rand.Text(),
+		issuer:   issuer,
+	}, nil
+}
+
+// account returns the account the auth callout service runs in, or ""
+// for no service.
+func (c *AuthCallout) account() string {
+	if c == nil {
+		return ""
+	}
+
+	return cmp.Or(c.Account, DefaultAuthCalloutAccount)
+}
+
+// configure adds the service's account, user, and the auth callout
+// settings naming them to natsOpts.
+func (a *authCallout) configure(
+	natsOpts *natsserver.Options,
+) {
+	if a == nil {
+		return
+	}
+
+	i := slices.IndexFunc(
+		natsOpts.Accounts,
+		func(acc *natsserver.Account) bool {
+			return acc.Name == a.account
+		},
+	)
+	var acc *natsserver.Account
+	if i < 0 {
+		acc = natsserver.NewAccount(a.account)
+		// The accounts slice may be shared with the options natsOpts was
+		// cloned from.
+		natsOpts.Accounts = append(slices.Clip(natsOpts.Accounts), acc)
+	} else {
+		acc = natsOpts.Accounts[i]
+	}
+
+	natsOpts.Users = append(natsOpts.Users, &natsserver.User{
+		Username: authCalloutUser,
+		Password: a.password,
+		Account:  acc,
+	})
+
+	// The issuer key is an account key, which always has a public key.
+	issuer, _ := a.issuer.PublicKey()
+	natsOpts.AuthCallout = &natsserver.AuthCallout{
+		Issuer:    issuer,
+		Account:   a.account,
+		AuthUsers: []string{authCalloutUser},
+	}
+}
+
+// start connects the service to natsServer in process and subscribes it
+// to the NATS server's authorization requests.
+func (a *authCallout) start(
+	natsServer NATSServerInstance,
+) error {
+	if a == nil {
+		return nil
+	}
+
+	conn, err := nats.Connect(
+		"",
+		nats.InProcessServer(natsServer),
+		nats.UserInfo(authCalloutUser, a.password),
+		nats.Name(authCalloutUser),
+		nats.NoReconnect(),
+	)
+	if err != nil {
+		return fmt.Errorf("error connecting auth callout service: %w", err)
+	}
+
+	_, err = conn.Subscribe(natsserver.AuthCalloutSubject, a.respond)
+	if err == nil {
+		// Clients are only sent to the service once the NATS server has
+		// its subscription.
+		err = conn.Flush()
+	}
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error subscribing auth callout service: %w", err)
+	}
+
+	a.conn = conn
+
+	return nil
+}
+
+// stop closes the service's connection.
+func (a *authCallout) stop() {
+	if a == nil || a.conn == nil {
+		return
+	}
+
+	a.conn.Close()
+}
+
+// respond answers an authorization request of the NATS server.
+func (a *authCallout) respond(
+	msg *nats.Msg,
+) {
+	response, err := a.response(msg.Data)
+	if err == nil {
+		err = msg.Respond([]byte(response))
+	}
+	if err != nil {
+		a.server.logger.Warn(
+			"error answering authorization request",
+			"error", err,
+		)
+	}
+}
+
+// response returns the signed response to the authorization request
+// data, carrying either the user the client is bound to or why it is
+// refused.
+func (a *authCallout) response(
+	data []byte,
+) (string, error) {
+	req, err := jwt.DecodeAuthorizationRequestClaims(string(data))
+	if err != nil {
+		return "", err
+	}
+
+	res := jwt.NewAuthorizationResponseClaims(req.UserNkey)
+	res.Audience = req.Server.ID
+
+	user, err := a.authorize(req)
+	if err != nil {
+		res.Error = err.Error()
+	} else if res.Jwt, err = user.Encode(a.issuer); err != nil {
+		return "", err
+	}
+
+	return res.Encode(a.issuer)
+}
+
+// authorize calls the Handler of the server's options for the client of
+// req, returning the user it is bound to. The Handler is given until the
+// NATS server stops waiting for the response.
+func (a *authCallout) authorize(
+	req *jwt.AuthorizationRequestClaims,
+) (*jwt.UserClaims, error) {
+	ctx, cancel := context.WithDeadline(
+		context.Background(),
+		time.Unix(req.Expires, 0),
+	)
+	defer cancel()
+
+	result, err := a.server.options().AuthCallout.Handler.Authenticate(
+		ctx,
+		calloutRequest(req),
+	)
+	switch {
+	case err != nil:
+		return nil, err
+	case result == nil:
+		return nil, errors.New("authenticator returned no result")
+	}
+
+	user := jwt.NewUserClaims(req.UserNkey)
+	user.Audience = cmp.Or(result.Account, natsserver.DEFAULT_GLOBAL_ACCOUNT)
+	user.Name = result.User
+	if !result.Expires.IsZero() {
+		user.Expires = result.Expires.Unix()
+	}
+	user.Permissions = jwtPermissions(result.Permissions)
+
+	return user, nil
+}
+
+// calloutRequest returns the AuthRequest for the client of an
+// authorization request.
+func calloutRequest(
+	req *jwt.AuthorizationRequestClaims,
+) *AuthRequest {
+	client, opts := req.ClientInformation, req.ConnectOptions
+
+	kind := natsserver.CLIENT
+	if client.Kind == "Leafnode" {
+		kind = natsserver.LEAF
+	}
+
+	return &AuthRequest{
+		ClientID:   client.ID,
+		Kind:       kind,
+		RemoteAddr: &net.TCPAddr{IP: net.ParseIP(client.Host)},
+		Name:       opts.Name,
+		Lang:       opts.Lang,
+		Version:    opts.Version,
+		Username:   opts.Username,
+		Password:   opts.Password,
+		Token:      opts.Token,
+		TLS:        calloutTLS(req.TLS),
+	}
+}
+
+// calloutTLS returns the connection state holding the certificates of an
+// authorization request's client, or nil without TLS. The NATS server
+// sends the verified chains, or the peer certificates when there are
+// none.
+func calloutTLS(
+	clientTLS *jwt.ClientTLS,
+) *tls.ConnectionState {
+	if clientTLS == nil {
+		return nil
+	}
+
+	state := &tls.ConnectionState{
+		PeerCertificates: parseCertificates(clientTLS.Certs),
+	}
+	for _, chain := range clientTLS.VerifiedChains {
+		state.VerifiedChains = append(
+			state.VerifiedChains,
+			parseCertificates(chain),
+		)
+	}
+	if len(state.VerifiedChains) > 0 {
+		state.PeerCertificates = state.VerifiedChains[0]
+	}
+
+	return state
+}
+
+// parseCertificates returns the PEM-encoded certificates pems, skipping
+// any that cannot be parsed.
+func parseCertificates(
+	pems []string,
+) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for _, p := range pems {
+		block, _ := pem.Decode([]byte(p))
+		if block == nil {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		certs = append(certs, cert)
+	}
+
+	return certs
+}
+
+// jwtPermissions returns the user JWT permissions for perms, which allow
+// everything when nil.
+func jwtPermissions(
+	perms *natsserver.Permissions,
+) jwt.Permissions {
+	if perms == nil {
+		return jwt.Permissions{}
+	}
+
+	jwtPerms := jwt.Permissions{
+		Pub: jwtPermission(perms.Publish),
+		Sub: jwtPermission(perms.Subscribe),
+	}
+	if perms.Response != nil {
+		jwtPerms.Resp = &jwt.ResponsePermission{
+			MaxMsgs: perms.Response.MaxMsgs,
+			Expires: perms.Response.Expires,
+		}
+	}
+
+	return jwtPerms
+}
+
+// jwtPermission returns the user JWT permission for perm. A user JWT
+// cannot tell an empty allow list, which allows nothing, from none, so
+// one is expressed by denying every subject.
+func jwtPermission(
+	perm *natsserver.SubjectPermission,
+) jwt.Permission {
+	if perm == nil {
+		return jwt.Permission{}
+	}
+
+	jwtPerm := jwt.Permission{
+		Allow: slices.Clone(perm.Allow),
+		Deny:  slices.Clone(perm.Deny),
+	}
+	if perm.Allow != nil && len(perm.Allow) == 0 {
+		jwtPerm.Deny = append(jwtPerm.Deny, ">")
+	}
+
+	return jwtPerm
+}
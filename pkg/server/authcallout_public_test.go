@@ -0,0 +1,855 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+type AuthCalloutPublicTestSuite struct {
+	suite.Suite
+
+	mockCtrl       *gomock.Controller
+	mockNATSServer *mocks.MockNATSServerInstance
+	srv            *server.Server
+	started        []*natsserver.Options
+	logs           *syncBuffer
+	fake           *fakeNATS
+	terminate      func()
+	newNATSServer  func(*natsserver.Options) (server.NATSServerInstance, error)
+	authenticate   server.AuthenticatorFunc
+}
+
+func (s *AuthCalloutPublicTestSuite) SetupSuite() {
+	s.newNATSServer = server.NewNATSServer
+}
+
+func (s *AuthCalloutPublicTestSuite) TearDownSuite() {
+	server.NewNATSServer = s.newNATSServer
+}
+
+func (s *AuthCalloutPublicTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockNATSServer = mocks.NewMockNATSServerInstance(s.mockCtrl)
+	s.terminate = expectWaitForShutdown(s.mockNATSServer)
+	s.started = nil
+	s.logs = &syncBuffer{}
+	s.fake = newFakeNATS()
+	s.authenticate = nil
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(s.logs, nil)),
+		&server.Options{
+			Options:      &natsserver.Options{},
+			ReadyTimeout: 5 * time.Second,
+			AuthCallout: &server.AuthCallout{
+				Handler: server.AuthenticatorFunc(func(
+					ctx context.Context,
+					req *server.AuthRequest,
+				) (*server.AuthResult, error) {
+					return s.authenticate(ctx, req)
+				}),
+			},
+		},
+	)
+
+	server.NewNATSServer = func(
+		opts *natsserver.Options,
+	) (server.NATSServerInstance, error) {
+		s.started = append(s.started, opts)
+		return s.mockNATSServer, nil
+	}
+}
+
+func (s *AuthCalloutPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *AuthCalloutPublicTestSuite) TearDownSubTest() {
+	// The service's connection is closed last, so nothing is left writing
+	// to the fake server.
+	_ = s.fake.server.Close()
+	s.terminate()
+}
+
+func (s *AuthCalloutPublicTestSuite) TearDownTest() {
+	_ = s.fake.server.Close()
+	s.terminate()
+	s.mockCtrl.Finish()
+}
+
+// expectServiceStart expects the server to start on the mock NATS server,
+// with the auth callout service connecting to the fake server.
+func (s *AuthCalloutPublicTestSuite) expectServiceStart() {
+	expectStart(s.mockNATSServer)
+	s.mockNATSServer.EXPECT().InProcessConn().Return(s.fake.client, nil)
+}
+
+// start starts the server, with the fake server taking the auth callout
+// service's subscription.
+func (s *AuthCalloutPublicTestSuite) start() {
+	s.expectServiceStart()
+	handshake := s.fake.serve(true)
+	s.Require().NoError(s.srv.Start())
+	s.Require().NoError(<-handshake)
+}
+
+func (s *AuthCalloutPublicTestSuite) TestStart() {
+	tests := []struct {
+		name         string
+		opts         func(opts *server.Options)
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name: "configures auth callout in default account",
+			opts: func(*server.Options) {},
+			mockSetup: func() {
+				s.expectServiceStart()
+				s.fake.serve(true)
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Require().Len(s.started, 1)
+				started := s.started[0]
+
+				s.Require().NotNil(started.AuthCallout)
+				s.True(nkeys.IsValidPublicAccountKey(
+					started.AuthCallout.Issuer,
+				))
+				s.Equal("AUTH", started.AuthCallout.Account)
+				s.Equal(
+					[]string{"auth-callout"},
+					started.AuthCallout.AuthUsers,
+				)
+
+				s.Require().Len(started.Accounts, 1)
+				s.Equal("AUTH", started.Accounts[0].Name)
+				s.Require().Len(started.Users, 1)
+				s.Equal("auth-callout", started.Users[0].Username)
+				s.NotEmpty(started.Users[0].Password)
+				s.Same(started.Accounts[0], started.Users[0].Account)
+				s.Nil(s.srv.Opts.Accounts)
+			},
+		},
+		{
+			name: "configures auth callout in existing account",
+			opts: func(opts *server.Options) {
+				opts.Accounts = []*natsserver.Account{
+					natsserver.NewAccount("APP"),
+					natsserver.NewAccount("CALLOUT"),
+				}
+				opts.AuthCallout.Account = "CALLOUT"
+			},
+			mockSetup: func() {
+				s.expectServiceStart()
+				s.fake.serve(true)
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				started := s.started[0]
+
+				s.Equal("CALLOUT", started.AuthCallout.Account)
+				s.Len(started.Accounts, 2)
+				s.Len(s.srv.Opts.Accounts, 2)
+				s.Require().Len(started.Users, 1)
+				s.Same(started.Accounts[1], started.Users[0].Account)
+				s.Empty(s.srv.Opts.Users)
+			},
+		},
+		{
+			name: "fails when service cannot connect",
+			opts: func(*server.Options) {},
+			mockSetup: func() {
+				s.mockNATSServer.EXPECT().Start().AnyTimes()
+				s.mockNATSServer.EXPECT().
+					ReadyForConnections(gomock.Any()).
+					Return(true)
+				s.mockNATSServer.EXPECT().SetLogger(gomock.Any(), true, true)
+				s.mockNATSServer.EXPECT().
+					InProcessConn().
+					Return(nil, errors.New("server shutting down"))
+				s.mockNATSServer.EXPECT().Shutdown()
+			},
+			validateFunc: func(err error) {
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhaseReady, startErr.Phase)
+				s.ErrorContains(err, "error connecting auth callout service")
+				s.ErrorContains(err, "server shutting down")
+				s.Equal(server.StateFailed, s.srv.State())
+			},
+		},
+		{
+			name: "fails when service cannot subscribe",
+			opts: func(*server.Options) {},
+			mockSetup: func() {
+				s.expectServiceStart()
+				s.mockNATSServer.EXPECT().Shutdown()
+				s.fake.serve(false)
+			},
+			validateFunc: func(err error) {
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhaseReady, startErr.Phase)
+				s.ErrorContains(
+					err,
+					"error subscribing auth callout service",
+				)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.authenticate = func(
+				context.Context,
+				*server.AuthRequest,
+			) (*server.AuthResult, error) {
+				return nil, nil
+			}
+			tc.opts(s.srv.Opts)
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.Start())
+		})
+	}
+}
+
+func (s *AuthCalloutPublicTestSuite) TestRespond() {
+	cert, certPEM := certificate("alice")
+	perms := &natsserver.Permissions{
+		Publish: &natsserver.SubjectPermission{
+			Allow: []string{"app.>"},
+			Deny:  []string{"app.admin"},
+		},
+		Subscribe: &natsserver.SubjectPermission{Allow: []string{}},
+		Response:  &natsserver.ResponsePermission{MaxMsgs: 1},
+	}
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+	grant := func(
+		context.Context,
+		*server.AuthRequest,
+	) (*server.AuthResult, error) {
+		return &server.AuthResult{}, nil
+	}
+
+	tests := []struct {
+		name         string
+		authenticate server.AuthenticatorFunc
+		request      func(req *jwt.AuthorizationRequestClaims)
+		data         string
+		reply        string
+		validateFunc func(res *jwt.AuthorizationResponseClaims)
+	}{
+		{
+			name: "binds client to account and permissions",
+			authenticate: func(
+				ctx context.Context,
+				req *server.AuthRequest,
+			) (*server.AuthResult, error) {
+				_, bounded := ctx.Deadline()
+				s.True(bounded)
+				s.Equal(&server.AuthRequest{
+					ClientID: 7,
+					Kind:     natsserver.CLIENT,
+					RemoteAddr: &net.TCPAddr{
+						IP: net.ParseIP("127.0.0.1"),
+					},
+					Name:     "orders",
+					Lang:     "go",
+					Version:  "1.0.0",
+					Username: "alice",
+					Password: "secret",
+					Token:    "token",
+				}, req)
+
+				return &server.AuthResult{
+					Account:     "APP",
+					Permissions: perms,
+					User:        "alice@example.com",
+					Expires:     expires,
+				}, nil
+			},
+			request: func(*jwt.AuthorizationRequestClaims) {},
+			reply:   "_INBOX.reply",
+			validateFunc: func(res *jwt.AuthorizationResponseClaims) {
+				s.Require().NotNil(res)
+				s.Empty(res.Error)
+				s.Equal("SERVER", res.Audience)
+				s.Equal(s.started[0].AuthCallout.Issuer, res.Issuer)
+
+				user, err := jwt.DecodeUserClaims(res.Jwt)
+				s.Require().NoError(err)
+				s.Equal(res.Subject, user.Subject)
+				s.Equal(res.Issuer, user.Issuer)
+				s.Equal("APP", user.Audience)
+				s.Equal("alice@example.com", user.Name)
+				s.Equal(expires.Unix(), user.Expires)
+				s.Equal(jwt.Permission{
+					Allow: jwt.StringList{"app.>"},
+					Deny:  jwt.StringList{"app.admin"},
+				}, user.Pub)
+				s.Equal(jwt.Permission{Deny: jwt.StringList{">"}}, user.Sub)
+				s.Equal(&jwt.ResponsePermission{MaxMsgs: 1}, user.Resp)
+			},
+		},
+		{
+			name:         "binds client to global account",
+			authenticate: grant,
+			request:      func(*jwt.AuthorizationRequestClaims) {},
+			reply:        "_INBOX.reply",
+			validateFunc: func(res *jwt.AuthorizationResponseClaims) {
+				s.Require().NotNil(res)
+				user, err := jwt.DecodeUserClaims(res.Jwt)
+				s.Require().NoError(err)
+				s.Equal("$G", user.Audience)
+				s.Empty(user.Name)
+				s.Zero(user.Expires)
+				s.Equal(jwt.Permissions{}, user.Permissions)
+			},
+		},
+		{
+			name: "passes peer certificates of leafnode",
+			authenticate: func(
+				_ context.Context,
+				req *server.AuthRequest,
+			) (*server.AuthResult, error) {
+				s.Equal(natsserver.LEAF, req.Kind)
+				s.Require().NotNil(req.TLS)
+				s.Equal([]*x509.Certificate{cert}, req.TLS.PeerCertificates)
+				s.Empty(req.TLS.VerifiedChains)
+
+				return &server.AuthResult{}, nil
+			},
+			request: func(req *jwt.AuthorizationRequestClaims) {
+				req.ClientInformation.Kind = "Leafnode"
+				req.TLS = &jwt.ClientTLS{
+					Certs: jwt.StringList{
+						"not pem",
+						string(pem.EncodeToMemory(&pem.Block{
+							Type:  "CERTIFICATE",
+							Bytes: []byte("not der"),
+						})),
+						certPEM,
+					},
+				}
+			},
+			reply: "_INBOX.reply",
+			validateFunc: func(res *jwt.AuthorizationResponseClaims) {
+				s.Require().NotNil(res)
+				s.Empty(res.Error)
+			},
+		},
+		{
+			name: "passes verified chains",
+			authenticate: func(
+				_ context.Context,
+				req *server.AuthRequest,
+			) (*server.AuthResult, error) {
+				s.Require().NotNil(req.TLS)
+				s.Equal(
+					[][]*x509.Certificate{{cert}},
+					req.TLS.VerifiedChains,
+				)
+				s.Equal([]*x509.Certificate{cert}, req.TLS.PeerCertificates)
+
+				return &server.AuthResult{}, nil
+			},
+			request: func(req *jwt.AuthorizationRequestClaims) {
+				req.TLS = &jwt.ClientTLS{
+					VerifiedChains: []jwt.StringList{{certPEM}},
+				}
+			},
+			reply: "_INBOX.reply",
+			validateFunc: func(res *jwt.AuthorizationResponseClaims) {
+				s.Require().NotNil(res)
+				s.Empty(res.Error)
+			},
+		},
+		{
+			name: "refuses client handler refuses",
+			authenticate: func(
+				context.Context,
+				*server.AuthRequest,
+			) (*server.AuthResult, error) {
+				return nil, server.ErrInvalidCredentials
+			},
+			request: func(*jwt.AuthorizationRequestClaims) {},
+			reply:   "_INBOX.reply",
+			validateFunc: func(res *jwt.AuthorizationResponseClaims) {
+				s.Require().NotNil(res)
+				s.Equal("invalid credentials", res.Error)
+				s.Empty(res.Jwt)
+			},
+		},
+		{
+			name: "refuses client without result",
+			authenticate: func(
+				context.Context,
+				*server.AuthRequest,
+			) (*server.AuthResult, error) {
+				return nil, nil
+			},
+			request: func(*jwt.AuthorizationRequestClaims) {},
+			reply:   "_INBOX.reply",
+			validateFunc: func(res *jwt.AuthorizationResponseClaims) {
+				s.Require().NotNil(res)
+				s.Equal("authenticator returned no result", res.Error)
+			},
+		},
+		{
+			name:         "logs undecodable request",
+			authenticate: grant,
+			data:         "not a jwt",
+			reply:        "_INBOX.reply",
+			validateFunc: func(res *jwt.AuthorizationResponseClaims) {
+				s.Nil(res)
+			},
+		},
+		{
+			name:         "logs request for invalid user",
+			authenticate: grant,
+			request: func(req *jwt.AuthorizationRequestClaims) {
+				req.UserNkey = "not a user"
+			},
+			reply: "_INBOX.reply",
+			validateFunc: func(res *jwt.AuthorizationResponseClaims) {
+				s.Nil(res)
+			},
+		},
+		{
+			name:         "logs request without reply subject",
+			authenticate: grant,
+			request:      func(*jwt.AuthorizationRequestClaims) {},
+			validateFunc: func(res *jwt.AuthorizationResponseClaims) {
+				s.Nil(res)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.authenticate = tc.authenticate
+			s.start()
+
+			data := tc.data
+			if data == "" {
+				data = s.request(tc.request)
+			}
+			s.Require().NoError(s.fake.publish(tc.reply, data))
+
+			var res *jwt.AuthorizationResponseClaims
+			if s.logs.wait("error answering authorization request") {
+				tc.validateFunc(res)
+				return
+			}
+
+			token, err := s.fake.response()
+			s.Require().NoError(err)
+			res, err = jwt.DecodeAuthorizationResponseClaims(token)
+			s.Require().NoError(err)
+			tc.validateFunc(res)
+		})
+	}
+}
+
+func (s *AuthCalloutPublicTestSuite) TestReload() {
+	tests := []struct {
+		name         string
+		change       func(opts *server.Options)
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name: "keeps service and swaps handler",
+			change: func(opts *server.Options) {
+				opts.AuthCallout = &server.AuthCallout{
+					Handler: server.AuthenticatorFunc(func(
+						context.Context,
+						*server.AuthRequest,
+					) (*server.AuthResult, error) {
+						return nil, errors.New("swapped")
+					}),
+				}
+				opts.Debug = true
+			},
+			mockSetup: func() {
+				s.mockNATSServer.EXPECT().
+					ReloadOptions(gomock.Any()).
+					DoAndReturn(func(opts *natsserver.Options) error {
+						s.True(opts.Debug)
+						s.Equal(s.started[0].AuthCallout, opts.AuthCallout)
+						s.Require().Len(opts.Users, 1)
+						user := s.started[0].Users[0]
+						s.Equal(user.Username, opts.Users[0].Username)
+						s.Equal(user.Password, opts.Users[0].Password)
+						s.Require().Len(opts.Accounts, 1)
+						s.Same(opts.Accounts[0], opts.Users[0].Account)
+
+						return nil
+					})
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Require().NoError(s.fake.publish(
+					"_INBOX.reply",
+					s.request(func(*jwt.AuthorizationRequestClaims) {}),
+				))
+				token, err := s.fake.response()
+				s.Require().NoError(err)
+				res, err := jwt.DecodeAuthorizationResponseClaims(token)
+				s.Require().NoError(err)
+				s.Equal("swapped", res.Error)
+			},
+		},
+		{
+			name: "refuses to move service to another account",
+			change: func(opts *server.Options) {
+				opts.AuthCallout = &server.AuthCallout{
+					Handler: opts.AuthCallout.Handler,
+					Account: "OTHER",
+				}
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				var reloadErr *server.ReloadError
+				s.Require().ErrorAs(err, &reloadErr)
+				s.Equal([]string{"AuthCallout"}, reloadErr.Fields)
+			},
+		},
+		{
+			name: "refuses to remove service",
+			change: func(opts *server.Options) {
+				opts.AuthCallout = nil
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrNotReloadable)
+				s.ErrorContains(err, "AuthCallout")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.start()
+
+			newOpts := *s.srv.Opts
+			newOpts.Options = s.srv.Opts.Options.Clone()
+			tc.change(&newOpts)
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.Reload(&newOpts))
+		})
+	}
+}
+
+func (s *AuthCalloutPublicTestSuite) TestServes() {
+	server.NewNATSServer = s.newNATSServer
+	app := natsserver.NewAccount("APP")
+	opts := s.srv.Opts
+	opts.Host = "127.0.0.1"
+	opts.Port = freePort(s.T())
+	opts.NoSigs = true
+	opts.Accounts = []*natsserver.Account{app}
+	opts.Users = []*natsserver.User{
+		{Username: "legacy", Password: "legacy", Account: app},
+	}
+	opts.AuthCallout.Handler = server.StaticAuthenticator{
+		"alice": {
+			Password: "secret",
+			Account:  "APP",
+			Permissions: &natsserver.Permissions{
+				Publish: &natsserver.SubjectPermission{
+					Deny: []string{"admin"},
+				},
+			},
+		},
+	}
+	s.Require().NoError(s.srv.Start())
+	defer s.srv.Stop()
+	url := fmt.Sprintf("nats://127.0.0.1:%d", opts.Port)
+
+	_, err := nats.Connect(url, nats.UserInfo("alice", "guess"))
+	s.ErrorIs(err, nats.ErrAuthorization)
+
+	violations := make(chan error, 1)
+	nc, err := nats.Connect(
+		url,
+		nats.UserInfo("alice", "secret"),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, e error) {
+			violations <- e
+		}),
+	)
+	s.Require().NoError(err)
+	defer nc.Close()
+
+	s.Require().NoError(nc.Publish("admin", nil))
+	select {
+	case err := <-violations:
+		s.ErrorContains(err, "permissions violation")
+	case <-time.After(5 * time.Second):
+		s.Fail("permissions not applied")
+	}
+
+	// The service authenticates clients with no users configured.
+	s.Require().NoError(s.srv.RemoveUser("legacy"))
+	other, err := nats.Connect(url, nats.UserInfo("alice", "secret"))
+	s.Require().NoError(err)
+	other.Close()
+}
+
+// request returns an authorization request for the client alice, signed
+// as the NATS server signs them, changed by change.
+func (s *AuthCalloutPublicTestSuite) request(
+	change func(req *jwt.AuthorizationRequestClaims),
+) string {
+	serverKey, err := nkeys.CreateServer()
+	s.Require().NoError(err)
+	userKey, err := nkeys.CreateUser()
+	s.Require().NoError(err)
+	user, err := userKey.PublicKey()
+	s.Require().NoError(err)
+
+	req := jwt.NewAuthorizationRequestClaims(
+		s.started[0].AuthCallout.Issuer,
+	)
+	req.Audience = natsserver.AuthRequestSubject
+	req.Expires = time.Now().Add(time.Minute).Unix()
+	req.UserNkey = user
+	req.Server = jwt.ServerID{Name: "test", ID: "SERVER"}
+	req.ClientInformation = jwt.ClientInformation{
+		ID:   7,
+		Host: "127.0.0.1",
+		Kind: "Client",
+	}
+	req.ConnectOptions = jwt.ConnectOptions{
+		Name:     "orders",
+		Lang:     "go",
+		Version:  "1.0.0",
+		Username: "alice",
+		Password: "secret",
+		Token:    "token",
+	}
+	change(req)
+
+	token, err := req.Encode(serverKey)
+	s.Require().NoError(err)
+
+	return token
+}
+
+// fakeNATS is the server end of an in-process connection, speaking just
+// enough of the NATS protocol for the auth callout service to connect,
+// subscribe, and answer requests.
+type fakeNATS struct {
+	client net.Conn
+	server net.Conn
+	reader *bufio.Reader
+}
+
+func newFakeNATS() *fakeNATS {
+	client, server := net.Pipe()
+
+	return &fakeNATS{
+		client: client,
+		server: server,
+		reader: bufio.NewReader(server),
+	}
+}
+
+// serve accepts the service's connection and its subscription in the
+// background, closing the connection instead of confirming the
+// subscription unless subscribe is set. The returned channel receives
+// the result.
+func (f *fakeNATS) serve(
+	subscribe bool,
+) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- f.handshake(subscribe)
+	}()
+
+	return done
+}
+
+func (f *fakeNATS) handshake(
+	subscribe bool,
+) error {
+	_, err := io.WriteString(
+		f.server,
+		`INFO {"server_id":"SERVER","proto":1,"headers":true,`+
+			`"max_payload":1048576}`+"\r\n",
+	)
+	if err != nil {
+		return err
+	}
+
+	// CONNECT and PING, then SUB and PING.
+	for i := range 4 {
+		if _, err := f.reader.ReadString('\n'); err != nil {
+			return err
+		}
+		if i == 1 || (i == 3 && subscribe) {
+			if _, err := io.WriteString(f.server, "PONG\r\n"); err != nil {
+				return err
+			}
+		}
+	}
+	if !subscribe {
+		return f.server.Close()
+	}
+
+	return nil
+}
+
+// publish sends the service an authorization request, with the reply
+// subject reply, when not empty.
+func (f *fakeNATS) publish(
+	reply string,
+	data string,
+) error {
+	_, err := fmt.Fprintf(
+		f.server,
+		"MSG %s 1 %s %d\r\n%s\r\n",
+		natsserver.AuthCalloutSubject,
+		reply,
+		len(data),
+		data,
+	)
+
+	return err
+}
+
+// response returns the data of the next message the service publishes.
+func (f *fakeNATS) response() (string, error) {
+	line, err := f.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	var subject string
+	var size int
+	if _, err := fmt.Sscanf(line, "PUB %s %d", &subject, &size); err != nil {
+		return "", err
+	}
+
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(f.reader, data); err != nil {
+		return "", err
+	}
+
+	return string(data[:size]), nil
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use, written by the
+// logger of the auth callout service's goroutine. It is hand-written
+// because io.Writer is a standard library interface.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(
+	p []byte,
+) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+// wait reports whether msg is written within a moment.
+func (b *syncBuffer) wait(
+	msg string,
+) bool {
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		b.mu.Lock()
+		found := strings.Contains(b.buf.String(), msg)
+		b.mu.Unlock()
+		if found {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return false
+}
+
+// certificate returns a self-signed certificate for cn, and its PEM
+// encoding.
+func certificate(
+	cn string,
+) (*x509.Certificate, string) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, _ := x509.CreateCertificate(
+		rand.Reader,
+		template,
+		template,
+		&key.PublicKey,
+		key,
+	)
+	cert, _ := x509.ParseCertificate(der)
+
+	return cert, string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: der,
+	}))
+}
+
+func TestAuthCalloutPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthCalloutPublicTestSuite))
+}
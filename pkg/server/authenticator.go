@@ -0,0 +1,155 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+)
+
+// Authenticate calls f.
+func (f AuthenticatorFunc) Authenticate(
+	ctx context.Context,
+	req *AuthRequest,
+) (*AuthResult, error) {
+	return f(ctx, req)
+}
+
+// Authenticate binds the client to its user's account and permissions
+// when its password matches, returning ErrInvalidCredentials otherwise.
+func (a StaticAuthenticator) Authenticate(
+	_ context.Context,
+	req *AuthRequest,
+) (*AuthResult, error) {
+	user, ok := a[req.Username]
+	if !ok || !comparePassword(user.Password, req.Password) {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &AuthResult{
+		Account:     user.Account,
+		Permissions: user.Permissions,
+	}, nil
+}
+
+// newClientAuth returns the custom client authentication for a server
+// started with natsOpts, or nil when opts has neither an Authenticator
+// nor MutualTLS.
+func (s *Server) newClientAuth(
+	opts *Options,
+	natsOpts *natsserver.Options,
+) *clientAuth {
+	if opts.authenticator() == nil {
+		return nil
+	}
+
+	auth := &clientAuth{server: s}
+	natsOpts.CustomClientAuthentication = auth
+
+	return auth
+}
+
+// bind gives auth the NATS server created with natsOpts, once the NATS
+// server has filled in its defaults, and the run's JetStream provisioning,
+// whose user it authenticates.
+func (a *clientAuth) bind(
+	natsServer NATSServerInstance,
+	natsOpts *natsserver.Options,
+	jetStream *jetStreamRun,
+) {
+	if a == nil {
+		return
+	}
+
+	a.natsServer = natsServer
+	a.timeout = time.Duration(natsOpts.AuthTimeout * float64(time.Second))
+	a.jetStream = jetStream
+}
+
+// Check authenticates c with the Authenticator or MutualTLS of the
+// server's options, unless it is the run's user provisioning JetStream
+// assets, binding it to the account and permissions returned.
+func (a *clientAuth) Check(
+	c natsserver.ClientAuthentication,
+) bool {
+	opts := c.GetOpts()
+	req := &AuthRequest{
+		ClientID:   c.GetID(),
+		Kind:       c.Kind(),
+		RemoteAddr: c.RemoteAddress(),
+		Name:       opts.Name,
+		Lang:       opts.Lang,
+		Version:    opts.Version,
+		Username:   opts.Username,
+		Password:   opts.Password,
+		Token:      opts.Token,
+		TLS:        c.GetTLSConnectionState(),
+	}
+
+	ctx := context.Background()
+	if a.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.timeout)
+		defer cancel()
+	}
+
+	logger := a.server.logger.With("client", req.ClientID)
+	// The user provisioning JetStream assets is the run's own.
+	result := a.jetStream.authenticate(req)
+	var err error
+	if result == nil {
+		result, err = a.server.options().authenticator().Authenticate(ctx, req)
+	}
+	switch {
+	case errors.Is(err, ErrInvalidCredentials):
+		return false
+	case err != nil:
+		logger.Warn("error authenticating client", "error", err)
+		return false
+	case result == nil:
+		logger.Warn("authenticator returned no result")
+		return false
+	}
+
+	user := &natsserver.User{
+		Username:           result.User,
+		Permissions:        result.Permissions,
+		ConnectionDeadline: result.Expires,
+	}
+	if result.Account != "" {
+		acc, err := a.natsServer.LookupAccount(result.Account)
+		if err != nil {
+			logger.Warn(
+				"error binding client to account",
+				"account", result.Account,
+				"error", err,
+			)
+			return false
+		}
+		user.Account = acc
+	}
+	c.RegisterUser(user)
+
+	return true
+}
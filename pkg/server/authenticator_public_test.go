@@ -0,0 +1,496 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+type AuthenticatorPublicTestSuite struct {
+	suite.Suite
+
+	mockCtrl       *gomock.Controller
+	mockNATSServer *mocks.MockNATSServerInstance
+	mockClient     *mocks.MockClientAuthentication
+	srv            *server.Server
+	started        []*natsserver.Options
+	logs           *bytes.Buffer
+	terminate      func()
+	newNATSServer  func(*natsserver.Options) (server.NATSServerInstance, error)
+	authenticate   server.AuthenticatorFunc
+}
+
+func (s *AuthenticatorPublicTestSuite) SetupSuite() {
+	s.newNATSServer = server.NewNATSServer
+}
+
+func (s *AuthenticatorPublicTestSuite) TearDownSuite() {
+	server.NewNATSServer = s.newNATSServer
+}
+
+func (s *AuthenticatorPublicTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockNATSServer = mocks.NewMockNATSServerInstance(s.mockCtrl)
+	s.mockClient = mocks.NewMockClientAuthentication(s.mockCtrl)
+	s.terminate = expectWaitForShutdown(s.mockNATSServer)
+	s.started = nil
+	s.logs = &bytes.Buffer{}
+	s.authenticate = nil
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(s.logs, nil)),
+		&server.Options{
+			Options:      &natsserver.Options{AuthTimeout: 1},
+			ReadyTimeout: 5 * time.Second,
+			Authenticator: server.AuthenticatorFunc(func(
+				ctx context.Context,
+				req *server.AuthRequest,
+			) (*server.AuthResult, error) {
+				return s.authenticate(ctx, req)
+			}),
+		},
+	)
+
+	server.NewNATSServer = func(
+		opts *natsserver.Options,
+	) (server.NATSServerInstance, error) {
+		s.started = append(s.started, opts)
+		return s.mockNATSServer, nil
+	}
+}
+
+func (s *AuthenticatorPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *AuthenticatorPublicTestSuite) TearDownSubTest() {
+	s.terminate()
+}
+
+func (s *AuthenticatorPublicTestSuite) TearDownTest() {
+	s.terminate()
+	s.mockCtrl.Finish()
+}
+
+// expectClient expects the NATS server to describe a client connecting
+// with opts.
+func (s *AuthenticatorPublicTestSuite) expectClient(
+	opts *natsserver.ClientOpts,
+) {
+	s.mockClient.EXPECT().GetOpts().Return(opts)
+	s.mockClient.EXPECT().GetID().Return(uint64(7))
+	s.mockClient.EXPECT().Kind().Return(natsserver.CLIENT)
+	s.mockClient.EXPECT().RemoteAddress().Return(&net.TCPAddr{
+		IP:   net.IPv4(127, 0, 0, 1),
+		Port: 50000,
+	})
+	s.mockClient.EXPECT().GetTLSConnectionState().Return(nil)
+}
+
+// check starts the server and checks the client with its custom client
+// authentication.
+func (s *AuthenticatorPublicTestSuite) check() bool {
+	expectStart(s.mockNATSServer)
+	s.Require().NoError(s.srv.Start())
+	s.Require().Len(s.started, 1)
+	s.Require().NotNil(s.started[0].CustomClientAuthentication)
+
+	return s.started[0].CustomClientAuthentication.Check(s.mockClient)
+}
+
+func (s *AuthenticatorPublicTestSuite) TestAuthenticate() {
+	want := &server.AuthResult{Account: "APP"}
+	f := server.AuthenticatorFunc(func(
+		_ context.Context,
+		req *server.AuthRequest,
+	) (*server.AuthResult, error) {
+		s.Equal("alice", req.Username)
+		return want, nil
+	})
+
+	got, err := f.Authenticate(
+		context.Background(),
+		&server.AuthRequest{Username: "alice"},
+	)
+
+	s.NoError(err)
+	s.Same(want, got)
+}
+
+func (s *AuthenticatorPublicTestSuite) TestStaticAuthenticate() {
+	hash, err := server.HashPassword("hashed", 4)
+	s.Require().NoError(err)
+	perms := &natsserver.Permissions{
+		Publish: &natsserver.SubjectPermission{Allow: []string{"app.>"}},
+	}
+	authenticator := server.StaticAuthenticator{
+		"alice": {Password: "secret", Account: "APP", Permissions: perms},
+		"bob":   {Password: hash},
+	}
+
+	tests := []struct {
+		name         string
+		req          *server.AuthRequest
+		validateFunc func(result *server.AuthResult, err error)
+	}{
+		{
+			name: "binds user with plain password",
+			req:  &server.AuthRequest{Username: "alice", Password: "secret"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Require().NoError(err)
+				s.Equal(&server.AuthResult{
+					Account:     "APP",
+					Permissions: perms,
+				}, result)
+			},
+		},
+		{
+			name: "binds user with hashed password",
+			req:  &server.AuthRequest{Username: "bob", Password: "hashed"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Require().NoError(err)
+				s.Equal(&server.AuthResult{}, result)
+			},
+		},
+		{
+			name: "refuses wrong password",
+			req:  &server.AuthRequest{Username: "alice", Password: "guess"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Nil(result)
+				s.ErrorIs(err, server.ErrInvalidCredentials)
+			},
+		},
+		{
+			name: "refuses wrong hashed password",
+			req:  &server.AuthRequest{Username: "bob", Password: "guess"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Nil(result)
+				s.ErrorIs(err, server.ErrInvalidCredentials)
+			},
+		},
+		{
+			name: "refuses unknown user",
+			req:  &server.AuthRequest{Username: "carol", Password: "secret"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Nil(result)
+				s.ErrorIs(err, server.ErrInvalidCredentials)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(
+				authenticator.Authenticate(context.Background(), tc.req),
+			)
+		})
+	}
+}
+
+func (s *AuthenticatorPublicTestSuite) TestCheck() {
+	perms := &natsserver.Permissions{
+		Subscribe: &natsserver.SubjectPermission{Deny: []string{"admin.>"}},
+	}
+	expires := time.Now().Add(time.Hour)
+	clientOpts := &natsserver.ClientOpts{
+		Name:     "orders",
+		Lang:     "go",
+		Version:  "1.0.0",
+		Username: "alice",
+		Password: "secret",
+		Token:    "token",
+	}
+
+	tests := []struct {
+		name         string
+		authTimeout  float64
+		authenticate server.AuthenticatorFunc
+		mockSetup    func()
+		validateFunc func(ok bool)
+	}{
+		{
+			name:        "binds client to account and permissions",
+			authTimeout: 1,
+			authenticate: func(
+				ctx context.Context,
+				req *server.AuthRequest,
+			) (*server.AuthResult, error) {
+				_, bounded := ctx.Deadline()
+				s.True(bounded)
+				s.Equal(&server.AuthRequest{
+					ClientID: 7,
+					Kind:     natsserver.CLIENT,
+					RemoteAddr: &net.TCPAddr{
+						IP:   net.IPv4(127, 0, 0, 1),
+						Port: 50000,
+					},
+					Name:     "orders",
+					Lang:     "go",
+					Version:  "1.0.0",
+					Username: "alice",
+					Password: "secret",
+					Token:    "token",
+				}, req)
+
+				return &server.AuthResult{
+					Account:     "APP",
+					Permissions: perms,
+					User:        "alice@example.com",
+					Expires:     expires,
+				}, nil
+			},
+			mockSetup: func() {
+				acc := natsserver.NewAccount("APP")
+				s.mockNATSServer.EXPECT().LookupAccount("APP").Return(acc, nil)
+				s.mockClient.EXPECT().RegisterUser(&natsserver.User{
+					Username:           "alice@example.com",
+					Account:            acc,
+					Permissions:        perms,
+					ConnectionDeadline: expires,
+				})
+			},
+			validateFunc: func(ok bool) {
+				s.True(ok)
+				s.NotContains(s.logs.String(), "level=WARN")
+			},
+		},
+		{
+			name: "binds client to global account without auth timeout",
+			authenticate: func(
+				ctx context.Context,
+				_ *server.AuthRequest,
+			) (*server.AuthResult, error) {
+				_, bounded := ctx.Deadline()
+				s.False(bounded)
+
+				return &server.AuthResult{}, nil
+			},
+			mockSetup: func() {
+				s.mockClient.EXPECT().RegisterUser(&natsserver.User{})
+			},
+			validateFunc: func(ok bool) {
+				s.True(ok)
+			},
+		},
+		{
+			name: "refuses invalid credentials without logging",
+			authenticate: func(
+				context.Context,
+				*server.AuthRequest,
+			) (*server.AuthResult, error) {
+				return nil, fmt.Errorf("idp: %w", server.ErrInvalidCredentials)
+			},
+			mockSetup: func() {},
+			validateFunc: func(ok bool) {
+				s.False(ok)
+				s.NotContains(s.logs.String(), "level=WARN")
+			},
+		},
+		{
+			name: "refuses and logs authenticator errors",
+			authenticate: func(
+				context.Context,
+				*server.AuthRequest,
+			) (*server.AuthResult, error) {
+				return nil, errors.New("idp unavailable")
+			},
+			mockSetup: func() {},
+			validateFunc: func(ok bool) {
+				s.False(ok)
+				s.Contains(s.logs.String(), "error authenticating client")
+				s.Contains(s.logs.String(), "client=7")
+				s.Contains(s.logs.String(), "idp unavailable")
+			},
+		},
+		{
+			name: "refuses and logs missing result",
+			authenticate: func(
+				context.Context,
+				*server.AuthRequest,
+			) (*server.AuthResult, error) {
+				return nil, nil
+			},
+			mockSetup: func() {},
+			validateFunc: func(ok bool) {
+				s.False(ok)
+				s.Contains(s.logs.String(), "authenticator returned no result")
+			},
+		},
+		{
+			name: "refuses and logs unknown account",
+			authenticate: func(
+				context.Context,
+				*server.AuthRequest,
+			) (*server.AuthResult, error) {
+				return &server.AuthResult{Account: "NONE"}, nil
+			},
+			mockSetup: func() {
+				s.mockNATSServer.EXPECT().
+					LookupAccount("NONE").
+					Return(nil, natsserver.ErrMissingAccount)
+			},
+			validateFunc: func(ok bool) {
+				s.False(ok)
+				s.Contains(s.logs.String(), "error binding client to account")
+				s.Contains(s.logs.String(), "account=NONE")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.srv.Opts.AuthTimeout = tc.authTimeout
+			s.authenticate = tc.authenticate
+			s.expectClient(clientOpts)
+			tc.mockSetup()
+
+			tc.validateFunc(s.check())
+		})
+	}
+}
+
+func (s *AuthenticatorPublicTestSuite) TestReload() {
+	denied := server.AuthenticatorFunc(func(
+		context.Context,
+		*server.AuthRequest,
+	) (*server.AuthResult, error) {
+		return nil, server.ErrInvalidCredentials
+	})
+
+	tests := []struct {
+		name         string
+		before       bool
+		after        server.Authenticator
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name:   "swaps authenticator in place",
+			before: true,
+			after:  denied,
+			mockSetup: func() {
+				s.mockNATSServer.EXPECT().ReloadOptions(gomock.Any())
+				s.expectClient(&natsserver.ClientOpts{})
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				check := s.started[0].CustomClientAuthentication
+				s.False(check.Check(s.mockClient))
+			},
+		},
+		{
+			name:      "refuses to add authenticator",
+			after:     denied,
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				var reloadErr *server.ReloadError
+				s.Require().ErrorAs(err, &reloadErr)
+				s.Equal([]string{"Authenticator"}, reloadErr.Fields)
+			},
+		},
+		{
+			name:      "refuses to remove authenticator",
+			before:    true,
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrNotReloadable)
+				s.ErrorContains(err, "Authenticator")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			if !tc.before {
+				s.srv.Opts.Authenticator = nil
+			}
+			expectStart(s.mockNATSServer)
+			s.Require().NoError(s.srv.Start())
+
+			newOpts := *s.srv.Opts
+			newOpts.Options = s.srv.Opts.Options.Clone()
+			newOpts.Authenticator = tc.after
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.Reload(&newOpts))
+		})
+	}
+}
+
+func (s *AuthenticatorPublicTestSuite) TestServes() {
+	server.NewNATSServer = s.newNATSServer
+	s.srv.Opts.Host = "127.0.0.1"
+	s.srv.Opts.Port = freePort(s.T())
+	s.srv.Opts.NoSigs = true
+	s.srv.Opts.Accounts = []*natsserver.Account{natsserver.NewAccount("APP")}
+	s.srv.Opts.Authenticator = server.StaticAuthenticator{
+		"alice": {
+			Password: "secret",
+			Account:  "APP",
+			Permissions: &natsserver.Permissions{
+				Publish: &natsserver.SubjectPermission{Deny: []string{"admin"}},
+			},
+		},
+	}
+	s.Require().NoError(s.srv.Start())
+	defer s.srv.Stop()
+	url := fmt.Sprintf("nats://127.0.0.1:%d", s.srv.Opts.Port)
+
+	_, err := nats.Connect(url, nats.UserInfo("alice", "guess"))
+	s.ErrorIs(err, nats.ErrAuthorization)
+
+	violations := make(chan error, 1)
+	nc, err := nats.Connect(
+		url,
+		nats.UserInfo("alice", "secret"),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, e error) {
+			violations <- e
+		}),
+	)
+	s.Require().NoError(err)
+	defer nc.Close()
+
+	s.Require().NoError(nc.Publish("admin", nil))
+	select {
+	case err := <-violations:
+		s.ErrorContains(err, "permissions violation")
+	case <-time.After(5 * time.Second):
+		s.Fail("permissions not applied")
+	}
+}
+
+func TestAuthenticatorPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthenticatorPublicTestSuite))
+}
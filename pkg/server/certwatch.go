@@ -0,0 +1,268 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+)
+
+// ReloadCertificates reloads the running server with the TLS files of
+// Opts loaded again, as the certificate watcher does when they change.
+// Each certificate must match its key and be valid now; otherwise the
+// listeners keep the certificates they have and an error is returned. The
+// certificates checked are the ones applied, so files rotated meanwhile
+// wait for the next reload.
+func (s *Server) ReloadCertificates() error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	if s.State() != StateRunning {
+		return fmt.Errorf("error reloading certificates: %w", ErrNotRunning)
+	}
+
+	opts := s.options()
+	natsOpts, err := opts.natsOptions(context.Background())
+	if err != nil {
+		return fmt.Errorf("error reloading certificates: %w", err)
+	}
+
+	if err := opts.checkCertificates(natsOpts, time.Now()); err != nil {
+		return fmt.Errorf("error reloading certificates: %w", err)
+	}
+
+	return s.reloadWith(opts, natsOpts)
+}
+
+// checkCertificates checks that the certificates loaded from TLS files
+// into natsOpts are valid at now.
+func (o *Options) checkCertificates(
+	natsOpts *natsserver.Options,
+	now time.Time,
+) error {
+	for _, l := range o.tlsListeners(natsOpts) {
+		if l.files == nil {
+			continue
+		}
+
+		for _, leaf := range certificates(*l.config) {
+			switch {
+			case now.Before(leaf.NotBefore):
+				return fmt.Errorf(
+					"%s certificate not valid before %s",
+					l.name,
+					leaf.NotBefore.Format(time.RFC3339),
+				)
+			case now.After(leaf.NotAfter):
+				return fmt.Errorf(
+					"%s certificate expired at %s",
+					l.name,
+					leaf.NotAfter.Format(time.RFC3339),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// newCertWatcher returns the certificate watcher of a run with opts, with
+// the TLS files as they are now, or nil when opts have no CertWatcher.
+func (s *Server) newCertWatcher(
+	opts *Options,
+) *certWatcher {
+	cfg := opts.CertWatcher
+	if cfg == nil {
+		return nil
+	}
+
+	w := &certWatcher{
+		server:   s,
+		interval: cfg.Interval,
+		warning:  cfg.ExpiryWarning,
+		warned:   make(map[string]bool),
+		done:     make(chan struct{}),
+	}
+	if w.interval == 0 {
+		w.interval = DefaultCertWatchInterval
+	}
+	if w.warning == 0 {
+		w.warning = DefaultCertExpiryWarning
+	}
+	w.stamps = w.stampFiles(opts)
+
+	return w
+}
+
+// start runs the watcher until the run ends. It does nothing on a nil
+// watcher.
+func (w *certWatcher) start() {
+	if w == nil {
+		return
+	}
+
+	go w.watch()
+}
+
+// stop stops the watcher. It does nothing on a nil watcher.
+func (w *certWatcher) stop() {
+	if w == nil {
+		return
+	}
+
+	close(w.done)
+}
+
+// watch checks the TLS files every interval, reloading the server when
+// they changed, and warns of expiring certificates, until done is closed.
+func (w *certWatcher) watch() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.warnExpiry(time.Now())
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+
+		if w.changed() {
+			if err := w.server.ReloadCertificates(); err != nil {
+				w.server.logger.Error(
+					"error reloading tls certificates",
+					"error", err,
+				)
+			} else {
+				w.server.logger.Info("tls certificates reloaded")
+			}
+		}
+
+		w.warnExpiry(time.Now())
+	}
+}
+
+// changed reports whether any TLS file of the server's options changed
+// since it was last checked.
+func (w *certWatcher) changed() bool {
+	stamps := w.stampFiles(w.server.options())
+
+	changed := len(stamps) != len(w.stamps)
+	for path, stamp := range stamps {
+		old, ok := w.stamps[path]
+		if !ok || old.size != stamp.size || !old.modTime.Equal(stamp.modTime) {
+			changed = true
+		}
+	}
+	w.stamps = stamps
+
+	return changed
+}
+
+// stampFiles returns the stamps of the TLS files of opts, keyed by path.
+// Keys that are secret references are not files, so they are left out.
+func (w *certWatcher) stampFiles(
+	opts *Options,
+) map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	for _, l := range opts.tlsListeners(opts.Options) {
+		if l.files == nil {
+			continue
+		}
+
+		paths := []string{l.files.CertFile, l.files.CAFile}
+		if !isSecretRef(l.files.KeyFile) {
+			paths = append(paths, l.files.KeyFile)
+		}
+
+		for _, path := range paths {
+			if path == "" {
+				continue
+			}
+
+			var stamp fileStamp
+			if info, err := os.Stat(path); err == nil {
+				stamp = fileStamp{size: info.Size(), modTime: info.ModTime()}
+			}
+			stamps[path] = stamp
+		}
+	}
+
+	return stamps
+}
+
+// warnExpiry logs a warning for each certificate of the listeners of the
+// running NATS server expiring within the watcher's warning time, once
+// for each.
+func (w *certWatcher) warnExpiry(
+	now time.Time,
+) {
+	s := w.server
+	s.mu.Lock()
+	running := s.running
+	s.mu.Unlock()
+
+	for _, l := range s.options().tlsListeners(running) {
+		for _, leaf := range certificates(*l.config) {
+			key := l.name + "/" + leaf.SerialNumber.String()
+			if w.warned[key] || leaf.NotAfter.Sub(now) > w.warning {
+				continue
+			}
+			w.warned[key] = true
+
+			msg := "tls certificate expires soon"
+			if now.After(leaf.NotAfter) {
+				msg = "tls certificate expired"
+			}
+			s.logger.Warn(
+				msg,
+				"listener", l.name,
+				"subject", leaf.Subject.String(),
+				"expires", leaf.NotAfter,
+			)
+		}
+	}
+}
+
+// certificates returns the parsed certificates of config, leaving out
+// those not parsed when loaded.
+func certificates(
+	config *tls.Config,
+) []*x509.Certificate {
+	if config == nil {
+		return nil
+	}
+
+	var leaves []*x509.Certificate
+	for _, cert := range config.Certificates {
+		if cert.Leaf != nil {
+			leaves = append(leaves, cert.Leaf)
+		}
+	}
+
+	return leaves
+}
@@ -0,0 +1,614 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+type CertWatcherPublicTestSuite struct {
+	suite.Suite
+
+	srv   *server.Server
+	logs  *syncBuffer
+	ca    *testCA
+	files *server.TLSFiles
+	// ports are the ports of the listeners, by name.
+	ports map[string]int
+}
+
+func (s *CertWatcherPublicTestSuite) SetupTest() {
+	s.logs = &syncBuffer{}
+	s.ca = newTestCA(s.T())
+
+	dir := s.T().TempDir()
+	s.files = &server.TLSFiles{
+		CertFile: filepath.Join(dir, "server.pem"),
+		KeyFile:  filepath.Join(dir, "server-key.pem"),
+		CAFile:   filepath.Join(dir, "ca.pem"),
+	}
+	s.Require().NoError(os.WriteFile(s.files.CAFile, s.ca.pem, 0o600))
+	s.rotate(1, time.Now().Add(-time.Hour), time.Now().Add(90*24*time.Hour))
+	s.ports = map[string]int{
+		"client":    freePort(s.T()),
+		"cluster":   freePort(s.T()),
+		"leafnode":  freePort(s.T()),
+		"websocket": freePort(s.T()),
+	}
+
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(s.logs, nil)),
+		&server.Options{
+			Options: &natsserver.Options{
+				Host:   "127.0.0.1",
+				Port:   s.ports["client"],
+				NoSigs: true,
+				Cluster: natsserver.ClusterOpts{
+					Name: "certs",
+					Host: "127.0.0.1",
+					Port: s.ports["cluster"],
+				},
+				LeafNode: natsserver.LeafNodeOpts{
+					Host: "127.0.0.1",
+					Port: s.ports["leafnode"],
+				},
+				Websocket: natsserver.WebsocketOpts{
+					Host: "127.0.0.1",
+					Port: s.ports["websocket"],
+				},
+			},
+			ReadyTimeout:      5 * time.Second,
+			TLSFiles:          s.files,
+			ClusterTLSFiles:   s.files,
+			LeafNodeTLSFiles:  s.files,
+			WebsocketTLSFiles: s.files,
+			CertWatcher: &server.CertWatcher{
+				Interval: 10 * time.Millisecond,
+			},
+		},
+	)
+}
+
+func (s *CertWatcherPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *CertWatcherPublicTestSuite) TearDownSubTest() {
+	s.srv.Stop()
+}
+
+func (s *CertWatcherPublicTestSuite) TearDownTest() {
+	s.srv.Stop()
+}
+
+func (s *CertWatcherPublicTestSuite) TestWatch() {
+	tests := []struct {
+		name         string
+		setup        func(opts *server.Options)
+		validateFunc func()
+	}{
+		{
+			name:  "serves renewed certificates on every listener",
+			setup: func(*server.Options) {},
+			validateFunc: func() {
+				for listener := range s.ports {
+					s.Equal(int64(1), s.serial(listener), listener)
+				}
+
+				s.rotate(2, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+				for listener := range s.ports {
+					s.Eventually(func() bool {
+						return s.serial(listener) == 2
+					}, 5*time.Second, 10*time.Millisecond, listener)
+				}
+				s.True(s.logs.wait("tls certificates reloaded"))
+			},
+		},
+		{
+			name:  "keeps certificates not matching their key",
+			setup: func(*server.Options) {},
+			validateFunc: func() {
+				certPEM, keyPEM := s.ca.issue(s.T(), serverCertificate(
+					2,
+					time.Now().Add(-time.Hour),
+					time.Now().Add(time.Hour),
+				))
+				s.write(s.files.CertFile, certPEM)
+
+				s.True(s.logs.wait("error reloading tls certificates"))
+				s.True(s.logs.wait("private key does not match public key"))
+				s.Equal(int64(1), s.serial("client"))
+
+				s.write(s.files.KeyFile, keyPEM)
+				s.Eventually(func() bool {
+					return s.serial("client") == 2
+				}, 5*time.Second, 10*time.Millisecond)
+			},
+		},
+		{
+			name:  "keeps certificates when renewed ones expired",
+			setup: func(*server.Options) {},
+			validateFunc: func() {
+				s.rotate(
+					2,
+					time.Now().Add(-2*time.Hour),
+					time.Now().Add(-time.Hour),
+				)
+
+				s.True(s.logs.wait("client certificate expired at"))
+				s.Equal(int64(1), s.serial("client"))
+			},
+		},
+		{
+			name:  "keeps certificates when files are removed",
+			setup: func(*server.Options) {},
+			validateFunc: func() {
+				s.Require().NoError(os.Remove(s.files.KeyFile))
+
+				s.True(s.logs.wait("error loading tls files"))
+				s.Equal(int64(1), s.serial("client"))
+			},
+		},
+		{
+			name: "watches certificates with keys given as secret references",
+			setup: func(opts *server.Options) {
+				key, err := os.ReadFile(s.files.KeyFile)
+				s.Require().NoError(err)
+				s.T().Setenv("CERT_WATCHER_TEST_KEY", string(key))
+
+				opts.TLSFiles = &server.TLSFiles{
+					CertFile: s.files.CertFile,
+					KeyFile:  "env://CERT_WATCHER_TEST_KEY",
+				}
+				opts.ClusterTLSFiles = nil
+				opts.LeafNodeTLSFiles = nil
+				opts.WebsocketTLSFiles = nil
+				opts.Cluster = natsserver.ClusterOpts{}
+				opts.LeafNode = natsserver.LeafNodeOpts{}
+				opts.Websocket = natsserver.WebsocketOpts{}
+			},
+			validateFunc: func() {
+				certPEM, keyPEM := s.ca.issue(s.T(), serverCertificate(
+					2,
+					time.Now().Add(-time.Hour),
+					time.Now().Add(time.Hour),
+				))
+				s.T().Setenv("CERT_WATCHER_TEST_KEY", string(keyPEM))
+				s.write(s.files.CertFile, certPEM)
+
+				s.Eventually(func() bool {
+					return s.serial("client") == 2
+				}, 5*time.Second, 10*time.Millisecond)
+			},
+		},
+		{
+			name: "warns of certificates expiring soon once",
+			setup: func(*server.Options) {
+				s.rotate(1, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+			},
+			validateFunc: func() {
+				for _, listener := range []string{
+					"client",
+					"cluster",
+					"leafnode",
+					"websocket",
+				} {
+					s.True(s.logs.wait(
+						`msg="tls certificate expires soon" listener=` +
+							listener,
+					))
+				}
+
+				time.Sleep(50 * time.Millisecond)
+				s.Equal(4, s.logs.count("tls certificate expires soon"))
+			},
+		},
+		{
+			name: "warns of expired certificates",
+			setup: func(opts *server.Options) {
+				s.rotate(
+					1,
+					time.Now().Add(-2*time.Hour),
+					time.Now().Add(-time.Hour),
+				)
+				opts.CertWatcher.ExpiryWarning = time.Minute
+			},
+			validateFunc: func() {
+				s.True(s.logs.wait(
+					`msg="tls certificate expired" listener=client`,
+				))
+			},
+		},
+		{
+			name: "watches with default interval",
+			setup: func(opts *server.Options) {
+				opts.CertWatcher.Interval = 0
+			},
+			validateFunc: func() {
+				s.rotate(2, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+				time.Sleep(50 * time.Millisecond)
+				s.Equal(int64(1), s.serial("client"))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup(s.srv.Opts)
+			s.Require().NoError(s.srv.Start())
+
+			tc.validateFunc()
+		})
+	}
+}
+
+func (s *CertWatcherPublicTestSuite) TestReloadCertificates() {
+	tests := []struct {
+		name         string
+		setup        func()
+		validateFunc func(err error)
+	}{
+		{
+			name: "serves renewed certificates",
+			setup: func() {
+				s.Require().NoError(s.srv.Start())
+				s.rotate(2, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Equal(int64(2), s.serial("client"))
+			},
+		},
+		{
+			name: "returns error when certificates are not yet valid",
+			setup: func() {
+				s.Require().NoError(s.srv.Start())
+				s.rotate(2, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+			},
+			validateFunc: func(err error) {
+				s.ErrorContains(
+					err,
+					"error reloading certificates: "+
+						"client certificate not valid before",
+				)
+				s.Equal(int64(1), s.serial("client"))
+			},
+		},
+		{
+			name: "applies the certificates it checked",
+			setup: func() {
+				// The client key is read through a resolver, which
+				// replaces the files with an expired certificate when
+				// called a second time after the start.
+				var calls int
+				files := *s.files
+				files.KeyFile = "rotating://server-key"
+				s.srv.Opts.TLSFiles = &files
+				s.srv.Opts.SecretResolvers = map[string]server.SecretResolver{
+					"rotating": server.SecretResolverFunc(func(
+						context.Context,
+						*url.URL,
+					) (string, error) {
+						calls++
+						if calls == 3 {
+							s.rotate(
+								3,
+								time.Now().Add(-2*time.Hour),
+								time.Now().Add(-time.Hour),
+							)
+						}
+						key, err := os.ReadFile(s.files.KeyFile)
+
+						return string(key), err
+					}),
+				}
+				s.Require().NoError(s.srv.Start())
+				s.rotate(2, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Equal(int64(2), s.serial("client"))
+			},
+		},
+		{
+			name: "returns error when files cannot be loaded",
+			setup: func() {
+				s.Require().NoError(s.srv.Start())
+				s.write(s.files.CAFile, []byte("not a certificate"))
+			},
+			validateFunc: func(err error) {
+				s.ErrorContains(
+					err,
+					"error reloading certificates: error loading tls files",
+				)
+			},
+		},
+		{
+			name:  "returns error when not running",
+			setup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrNotRunning)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.srv.Opts.CertWatcher = nil
+			tc.setup()
+
+			tc.validateFunc(s.srv.ReloadCertificates())
+		})
+	}
+}
+
+func (s *CertWatcherPublicTestSuite) TestReload() {
+	s.Require().NoError(s.srv.Start())
+
+	newOpts := *s.srv.Opts
+	newOpts.Options = s.srv.Opts.Clone()
+	newOpts.CertWatcher = &server.CertWatcher{Interval: time.Minute}
+
+	var reloadErr *server.ReloadError
+	s.Require().ErrorAs(s.srv.Reload(&newOpts), &reloadErr)
+	s.Equal([]string{"CertWatcher"}, reloadErr.Fields)
+}
+
+// rotate writes a new server certificate and its key, valid from
+// notBefore to notAfter.
+func (s *CertWatcherPublicTestSuite) rotate(
+	serial int64,
+	notBefore time.Time,
+	notAfter time.Time,
+) {
+	certPEM, keyPEM := s.ca.issue(
+		s.T(),
+		serverCertificate(serial, notBefore, notAfter),
+	)
+	s.write(s.files.KeyFile, keyPEM)
+	s.write(s.files.CertFile, certPEM)
+}
+
+// write replaces the file at path with data, as a renewing agent does.
+func (s *CertWatcherPublicTestSuite) write(
+	path string,
+	data []byte,
+) {
+	tmp := path + ".tmp"
+	s.Require().NoError(os.WriteFile(tmp, data, 0o600))
+	s.Require().NoError(os.Rename(tmp, path))
+}
+
+// serial returns the serial number of the certificate the listener
+// serves, or -1 when it cannot be had. The client and leafnode listeners
+// send their INFO before the TLS handshake.
+func (s *CertWatcherPublicTestSuite) serial(
+	listener string,
+) int64 {
+	conn, err := net.DialTimeout(
+		"tcp",
+		fmt.Sprintf("127.0.0.1:%d", s.ports[listener]),
+		time.Second,
+	)
+	if err != nil {
+		return -1
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(time.Second))
+
+	if listener == "client" || listener == "leafnode" {
+		if _, err := bufio.NewReader(conn).ReadString('\n'); err != nil {
+			return -1
+		}
+	}
+
+	certPEM, keyPEM := s.ca.issue(s.T(), serverCertificate(
+		100,
+		time.Now().Add(-time.Hour),
+		time.Now().Add(time.Hour),
+	))
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	s.Require().NoError(err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(s.ca.cert)
+	tlsConn := tls.Client(conn, &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		ServerName:   "localhost",
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{cert},
+	})
+	if err := tlsConn.Handshake(); err != nil {
+		return -1
+	}
+
+	return tlsConn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+}
+
+// count returns how many times msg was written.
+func (b *syncBuffer) count(
+	msg string,
+) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return strings.Count(b.buf.String(), msg)
+}
+
+// testCA is a certificate authority issuing certificates for tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+// newTestCA returns a new self-signed certificate authority.
+func newTestCA(
+	t *testing.T,
+) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(
+		rand.Reader,
+		template,
+		template,
+		&key.PublicKey,
+		key,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue signs template with a new key, returning the certificate and the
+// key PEM-encoded.
+func (ca *testCA) issue(
+	t *testing.T,
+	template *x509.Certificate,
+) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.CreateCertificate(
+		rand.Reader,
+		template,
+		ca.cert,
+		&key.PublicKey,
+		ca.key,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+// intermediate returns a certificate authority for name signed by ca.
+func (ca *testCA) intermediate(
+	t *testing.T,
+	name string,
+) *testCA {
+	t.Helper()
+
+	certPEM, keyPEM := ca.issue(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	})
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCA{
+		cert: pair.Leaf,
+		key:  pair.PrivateKey.(*ecdsa.PrivateKey),
+		pem:  certPEM,
+	}
+}
+
+// serverCertificate returns the template of a certificate for localhost,
+// for servers and the clients of their routes.
+func serverCertificate(
+	serial int64,
+	notBefore time.Time,
+	notAfter time.Time,
+) *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth,
+			x509.ExtKeyUsageClientAuth,
+		},
+	}
+}
+
+func TestCertWatcherPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(CertWatcherPublicTestSuite))
+}
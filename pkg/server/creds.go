@@ -0,0 +1,158 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+)
+
+// NewCredentials issues credentials for a new user described by user,
+// signing its JWT with signer, the key of an account or one of its signing
+// keys.
+func NewCredentials(
+	signer nkeys.KeyPair,
+	user *UserJWT,
+) (*Credentials, error) {
+	switch {
+	case signer == nil:
+		return nil, fmt.Errorf("error issuing credentials: signer is nil")
+	case user == nil:
+		return nil, fmt.Errorf("error issuing credentials: user is nil")
+	}
+
+	issuer, err := signer.PublicKey()
+	if err != nil || !nkeys.IsValidPublicAccountKey(issuer) {
+		return nil, fmt.Errorf(
+			"error issuing credentials: signer is not an account key",
+		)
+	}
+
+	key, err := nkeys.CreateUser()
+	if err != nil {
+		return nil, fmt.Errorf("error issuing credentials: %w", err)
+	}
+	// User keys always have a public key and seed.
+	pub, _ := key.PublicKey()
+	seed, _ := key.Seed()
+
+	claims := jwt.NewUserClaims(pub)
+	claims.Name = user.Name
+	claims.IssuerAccount = user.IssuerAccount
+	claims.Permissions = jwtPermissions(user.Permissions)
+	if !user.Expires.IsZero() {
+		claims.Expires = user.Expires.Unix()
+	}
+
+	vr := jwt.CreateValidationResults()
+	claims.Validate(vr)
+	if vr.IsBlocking(false) {
+		return nil, fmt.Errorf(
+			"error issuing credentials: %w",
+			errors.Join(vr.Errors()...),
+		)
+	}
+
+	token, err := claims.Encode(signer)
+	if err != nil {
+		return nil, fmt.Errorf("error issuing credentials: %w", err)
+	}
+
+	return &Credentials{
+		PublicKey: pub,
+		JWT:       token,
+		Seed:      seed,
+	}, nil
+}
+
+// UserCredentials issues credentials for a new user of the account name
+// of the running server's operator, as NewCredentials does, signed with
+// the account key.
+func (s *Server) UserCredentials(
+	name string,
+	user *UserJWT,
+) (*Credentials, error) {
+	r, err := s.operatorRun()
+	if err != nil {
+		return nil, fmt.Errorf("error issuing credentials: %w", err)
+	}
+
+	return r.credentials(name, user)
+}
+
+// credentials issues credentials for a new user of the account name of
+// the run's operator, signed with the account key.
+func (r *operatorRun) credentials(
+	name string,
+	user *UserJWT,
+) (*Credentials, error) {
+	r.operator.mu.Lock()
+	acc := r.operator.accounts[name]
+	r.operator.mu.Unlock()
+	if acc == nil {
+		return nil, fmt.Errorf(
+			"error issuing credentials for account %q: %w",
+			name,
+			ErrUnknownAccount,
+		)
+	}
+
+	if user == nil {
+		return NewCredentials(acc.key, nil)
+	}
+
+	signed := *user
+	signed.IssuerAccount = ""
+
+	return NewCredentials(acc.key, &signed)
+}
+
+// Encode returns the credentials in the format of a .creds file, which
+// clients load with nats.UserCredentials.
+func (c *Credentials) Encode() ([]byte, error) {
+	data, err := jwt.FormatUserConfig(c.JWT, c.Seed)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding credentials: %w", err)
+	}
+
+	return data, nil
+}
+
+// WriteFile writes the credentials to the .creds file path, readable only
+// by its owner.
+func (c *Credentials) WriteFile(
+	path string,
+) error {
+	data, err := c.Encode()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("error writing credentials: %w", err)
+	}
+
+	return nil
+}
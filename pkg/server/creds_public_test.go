@@ -0,0 +1,379 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+type CredsPublicTestSuite struct {
+	suite.Suite
+
+	account nkeys.KeyPair
+	srv     *server.Server
+}
+
+func (s *CredsPublicTestSuite) SetupTest() {
+	var err error
+	s.account, err = nkeys.CreateAccount()
+	s.Require().NoError(err)
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)),
+		&server.Options{
+			Options: &natsserver.Options{
+				Host:   "127.0.0.1",
+				Port:   freePort(s.T()),
+				NoSigs: true,
+			},
+			ReadyTimeout: 5 * time.Second,
+			Operator:     &server.Operator{Accounts: []string{"APP"}},
+		},
+	)
+}
+
+func (s *CredsPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *CredsPublicTestSuite) TearDownSubTest() {
+	s.srv.Stop()
+}
+
+func (s *CredsPublicTestSuite) TestNewCredentials() {
+	expires := time.Now().Add(time.Hour)
+	account, err := nkeys.CreateAccount()
+	s.Require().NoError(err)
+	issuer := s.publicKey(account)
+
+	tests := []struct {
+		name         string
+		signer       func() nkeys.KeyPair
+		user         *server.UserJWT
+		validateFunc func(creds *server.Credentials, err error)
+	}{
+		{
+			name:   "issues user signed with account key",
+			signer: func() nkeys.KeyPair { return s.account },
+			user: &server.UserJWT{
+				Name: "alice",
+				Permissions: &natsserver.Permissions{
+					Publish: &natsserver.SubjectPermission{
+						Allow: []string{"orders.>"},
+					},
+					Subscribe: &natsserver.SubjectPermission{
+						Allow: []string{},
+					},
+					Response: &natsserver.ResponsePermission{
+						MaxMsgs: 1,
+						Expires: time.Minute,
+					},
+				},
+				Expires: expires,
+			},
+			validateFunc: func(creds *server.Credentials, err error) {
+				s.Require().NoError(err)
+				claims, err := jwt.DecodeUserClaims(creds.JWT)
+				s.Require().NoError(err)
+
+				s.Equal(creds.PublicKey, claims.Subject)
+				s.Equal(s.publicKey(s.account), claims.Issuer)
+				s.Equal("alice", claims.Name)
+				s.Equal(jwt.StringList{"orders.>"}, claims.Pub.Allow)
+				s.Equal(jwt.StringList{">"}, claims.Sub.Deny)
+				s.Equal(
+					&jwt.ResponsePermission{MaxMsgs: 1, Expires: time.Minute},
+					claims.Resp,
+				)
+				s.Equal(expires.Unix(), claims.Expires)
+
+				key, err := nkeys.FromSeed(creds.Seed)
+				s.Require().NoError(err)
+				s.Equal(creds.PublicKey, s.publicKey(key))
+			},
+		},
+		{
+			name: "issues user signed with signing key",
+			signer: func() nkeys.KeyPair {
+				signer, err := nkeys.CreateAccount()
+				s.Require().NoError(err)
+				return signer
+			},
+			user: &server.UserJWT{IssuerAccount: issuer},
+			validateFunc: func(creds *server.Credentials, err error) {
+				s.Require().NoError(err)
+				claims, err := jwt.DecodeUserClaims(creds.JWT)
+				s.Require().NoError(err)
+				s.Equal(issuer, claims.IssuerAccount)
+				s.Equal(jwt.Permissions{}, claims.Permissions)
+				s.Zero(claims.Expires)
+			},
+		},
+		{
+			name: "fails when signer is not an account key",
+			signer: func() nkeys.KeyPair {
+				operator, err := nkeys.CreateOperator()
+				s.Require().NoError(err)
+				return operator
+			},
+			user: &server.UserJWT{},
+			validateFunc: func(_ *server.Credentials, err error) {
+				s.EqualError(
+					err,
+					"error issuing credentials: signer is not an account key",
+				)
+			},
+		},
+		{
+			name: "fails when signer has no seed",
+			signer: func() nkeys.KeyPair {
+				signer, err := nkeys.FromPublicKey(issuer)
+				s.Require().NoError(err)
+				return signer
+			},
+			user: &server.UserJWT{},
+			validateFunc: func(_ *server.Credentials, err error) {
+				s.ErrorIs(err, nkeys.ErrCannotSign)
+				s.ErrorContains(err, "error issuing credentials: ")
+			},
+		},
+		{
+			name:   "fails for nil user",
+			signer: func() nkeys.KeyPair { return s.account },
+			validateFunc: func(creds *server.Credentials, err error) {
+				s.Nil(creds)
+				s.EqualError(err, "error issuing credentials: user is nil")
+			},
+		},
+		{
+			name:   "fails for nil signer",
+			signer: func() nkeys.KeyPair { return nil },
+			user:   &server.UserJWT{},
+			validateFunc: func(creds *server.Credentials, err error) {
+				s.Nil(creds)
+				s.EqualError(err, "error issuing credentials: signer is nil")
+			},
+		},
+		{
+			name:   "fails for invalid permissions",
+			signer: func() nkeys.KeyPair { return s.account },
+			user: &server.UserJWT{
+				Permissions: &natsserver.Permissions{
+					Publish: &natsserver.SubjectPermission{
+						Allow: []string{"orders..new"},
+					},
+				},
+			},
+			validateFunc: func(_ *server.Credentials, err error) {
+				s.ErrorContains(err, "error issuing credentials: ")
+				s.ErrorContains(err, "cannot contain consecutive")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(server.NewCredentials(tc.signer(), tc.user))
+		})
+	}
+}
+
+func (s *CredsPublicTestSuite) TestWriteFile() {
+	tests := []struct {
+		name         string
+		creds        func() *server.Credentials
+		path         func(dir string) string
+		validateFunc func(path string, err error)
+	}{
+		{
+			name: "writes creds file",
+			creds: func() *server.Credentials {
+				creds, err := server.NewCredentials(
+					s.account,
+					&server.UserJWT{Name: "alice"},
+				)
+				s.Require().NoError(err)
+				return creds
+			},
+			path: func(dir string) string {
+				return filepath.Join(dir, "alice.creds")
+			},
+			validateFunc: func(path string, err error) {
+				s.Require().NoError(err)
+				info, err := os.Stat(path)
+				s.Require().NoError(err)
+				s.Equal(os.FileMode(0o600), info.Mode().Perm())
+
+				data, err := os.ReadFile(path)
+				s.Require().NoError(err)
+				token, err := jwt.ParseDecoratedJWT(data)
+				s.Require().NoError(err)
+				claims, err := jwt.DecodeUserClaims(token)
+				s.Require().NoError(err)
+				s.Equal("alice", claims.Name)
+				_, err = jwt.ParseDecoratedUserNKey(data)
+				s.NoError(err)
+			},
+		},
+		{
+			name: "fails for invalid credentials",
+			creds: func() *server.Credentials {
+				return &server.Credentials{JWT: "junk"}
+			},
+			path: func(dir string) string {
+				return filepath.Join(dir, "junk.creds")
+			},
+			validateFunc: func(path string, err error) {
+				s.ErrorContains(err, "error encoding credentials: ")
+				s.NoFileExists(path)
+			},
+		},
+		{
+			name: "fails when file cannot be written",
+			creds: func() *server.Credentials {
+				creds, err := server.NewCredentials(
+					s.account,
+					&server.UserJWT{},
+				)
+				s.Require().NoError(err)
+				return creds
+			},
+			path: func(dir string) string {
+				return filepath.Join(dir, "missing", "user.creds")
+			},
+			validateFunc: func(_ string, err error) {
+				s.ErrorContains(err, "error writing credentials: ")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			path := tc.path(s.T().TempDir())
+
+			tc.validateFunc(path, tc.creds().WriteFile(path))
+		})
+	}
+}
+
+func (s *CredsPublicTestSuite) TestUserCredentials() {
+	tests := []struct {
+		name         string
+		setup        func()
+		account      string
+		user         *server.UserJWT
+		validateFunc func(creds *server.Credentials, err error)
+	}{
+		{
+			name: "issues user clients connect with",
+			setup: func() {
+				s.Require().NoError(s.srv.Start())
+			},
+			account: "APP",
+			user:    &server.UserJWT{Name: "alice", IssuerAccount: "ignored"},
+			validateFunc: func(creds *server.Credentials, err error) {
+				s.Require().NoError(err)
+				claims, err := jwt.DecodeUserClaims(creds.JWT)
+				s.Require().NoError(err)
+				s.Empty(claims.IssuerAccount)
+				s.Equal("alice", claims.Name)
+
+				path := filepath.Join(s.T().TempDir(), "alice.creds")
+				s.Require().NoError(creds.WriteFile(path))
+				nc, err := nats.Connect(
+					fmt.Sprintf("nats://127.0.0.1:%d", s.srv.Opts.Port),
+					nats.UserCredentials(path),
+				)
+				s.Require().NoError(err)
+				nc.Close()
+			},
+		},
+		{
+			name: "fails for unknown account",
+			setup: func() {
+				s.Require().NoError(s.srv.Start())
+			},
+			account: "ORDERS",
+			user:    &server.UserJWT{},
+			validateFunc: func(_ *server.Credentials, err error) {
+				s.ErrorIs(err, server.ErrUnknownAccount)
+				s.ErrorContains(
+					err,
+					`error issuing credentials for account "ORDERS"`,
+				)
+			},
+		},
+		{
+			name: "fails for nil user",
+			setup: func() {
+				s.Require().NoError(s.srv.Start())
+			},
+			account: "APP",
+			validateFunc: func(_ *server.Credentials, err error) {
+				s.EqualError(err, "error issuing credentials: user is nil")
+			},
+		},
+		{
+			name:    "fails when not running",
+			setup:   func() {},
+			account: "APP",
+			user:    &server.UserJWT{},
+			validateFunc: func(_ *server.Credentials, err error) {
+				s.ErrorIs(err, server.ErrNotRunning)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+
+			tc.validateFunc(s.srv.UserCredentials(tc.account, tc.user))
+		})
+	}
+}
+
+// publicKey returns the public key of key.
+func (s *CredsPublicTestSuite) publicKey(
+	key nkeys.KeyPair,
+) string {
+	pub, err := key.PublicKey()
+	s.Require().NoError(err)
+
+	return pub
+}
+
+func TestCredsPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(CredsPublicTestSuite))
+}
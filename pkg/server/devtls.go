@@ -0,0 +1,264 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+)
+
+// devTLSHosts are the hosts the server certificate of development TLS is
+// valid for when DevTLS names none.
+var devTLSHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+// DevCertificates returns the certificates of development TLS of the
+// server's most recent run, or ErrNoDevTLS when it did not run with
+// Options.DevTLS. The certificate authority stays the same across
+// restarts, so clients trusting it keep verifying the server.
+func (s *Server) DevCertificates() (*DevCertificates, error) {
+	s.mu.Lock()
+	devTLS := s.run.devTLS
+	s.mu.Unlock()
+
+	if devTLS == nil {
+		return nil, ErrNoDevTLS
+	}
+
+	return devTLS.certs, nil
+}
+
+// ClientTLSConfig returns a TLS configuration for clients, as given to
+// nats.Secure, verifying the server against the certificate authority and
+// presenting the client certificate.
+func (c *DevCertificates) ClientTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		RootCAs:      c.CAPool,
+		Certificates: []tls.Certificate{c.ClientCertificate},
+	}
+}
+
+// newDevTLSRun returns the development TLS of a run with opts, issuing
+// its certificates from the certificate authority of the Server, which is
+// generated the first time. It returns nil when opts have no DevTLS.
+func (s *Server) newDevTLSRun(
+	opts *Options,
+) (*devTLSRun, error) {
+	cfg := opts.DevTLS
+	if cfg == nil {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	ca := s.devCA
+	s.mu.Unlock()
+
+	if ca == nil {
+		var err error
+		if ca, err = newDevCA(); err != nil {
+			return nil, err
+		}
+
+		s.mu.Lock()
+		s.devCA = ca
+		s.mu.Unlock()
+	}
+
+	hosts := cfg.Hosts
+	if len(hosts) == 0 {
+		hosts = devTLSHosts
+	}
+	server := devCertificate("nats-server", x509.ExtKeyUsageServerAuth)
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			server.IPAddresses = append(server.IPAddresses, ip)
+		} else {
+			server.DNSNames = append(server.DNSNames, host)
+		}
+	}
+	serverCert, _, _, err := ca.issue(server)
+	if err != nil {
+		return nil, err
+	}
+
+	clientName := cfg.ClientName
+	if clientName == "" {
+		clientName = DefaultDevTLSClientName
+	}
+	client := devCertificate(clientName, x509.ExtKeyUsageClientAuth)
+	clientCert, clientCertPEM, clientKeyPEM, err := ca.issue(client)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    pool,
+	}
+	if cfg.Verify {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return &devTLSRun{
+		config: config,
+		verify: cfg.Verify,
+		certs: &DevCertificates{
+			CAPool:            pool,
+			CAPEM:             ca.pem,
+			ClientCertificate: clientCert,
+			ClientCertPEM:     clientCertPEM,
+			ClientKeyPEM:      clientKeyPEM,
+		},
+	}, nil
+}
+
+// configure secures the client connections of natsOpts with the run's
+// server certificate. It does nothing on a nil run.
+func (r *devTLSRun) configure(
+	natsOpts *natsserver.Options,
+) {
+	if r == nil {
+		return
+	}
+
+	natsOpts.TLS = true
+	natsOpts.TLSConfig = r.config
+	natsOpts.TLSVerify = r.verify
+}
+
+// newDevCA returns a new self-signed certificate authority.
+func newDevCA() (*devCA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error creating development CA key: %w", err)
+	}
+
+	template := devCertificate("nats-server development CA")
+	template.NotAfter = template.NotBefore.Add(devCAValidity)
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	der, err := x509.CreateCertificate(
+		rand.Reader,
+		template,
+		template,
+		&key.PublicKey,
+		key,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating development CA: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("error creating development CA: %w", err)
+	}
+
+	return &devCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}, nil
+}
+
+// issue signs template with a new key, returning the certificate with its
+// key, and both PEM-encoded.
+func (ca *devCA) issue(
+	template *x509.Certificate,
+) (tls.Certificate, []byte, []byte, error) {
+	name := template.Subject.CommonName
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, nil, nil, fmt.Errorf(
+			"error creating key for certificate %q: %w",
+			name,
+			err,
+		)
+	}
+
+	der, err := x509.CreateCertificate(
+		rand.Reader,
+		template,
+		ca.cert,
+		&key.PublicKey,
+		ca.key,
+	)
+	if err != nil {
+		return tls.Certificate{}, nil, nil, fmt.Errorf(
+			"error issuing certificate %q: %w",
+			name,
+			err,
+		)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return tls.Certificate{}, nil, nil, fmt.Errorf(
+			"error encoding key for certificate %q: %w",
+			name,
+			err,
+		)
+	}
+
+	cert := tls.Certificate{
+		Certificate: [][]byte{der, ca.cert.Raw},
+		PrivateKey:  key,
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	return cert, certPEM, keyPEM, nil
+}
+
+// devCertificate returns the template of a certificate for name, valid
+// from a minute ago, allowing for clock skew, for devTLSValidity.
+func devCertificate(
+	name string,
+	usage ...x509.ExtKeyUsage,
+) *x509.Certificate {
+	// Reading from crypto/rand cannot fail.
+	serial, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	notBefore := time.Now().Add(-time.Minute)
+
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.Add(devTLSValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  usage,
+	}
+}
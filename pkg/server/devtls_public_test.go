@@ -0,0 +1,328 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+type DevTLSPublicTestSuite struct {
+	suite.Suite
+
+	srv *server.Server
+}
+
+func (s *DevTLSPublicTestSuite) SetupTest() {
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)),
+		&server.Options{
+			Options: &natsserver.Options{
+				Host:   "127.0.0.1",
+				Port:   freePort(s.T()),
+				NoSigs: true,
+			},
+			ReadyTimeout: 5 * time.Second,
+			DevTLS:       &server.DevTLS{},
+		},
+	)
+}
+
+func (s *DevTLSPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *DevTLSPublicTestSuite) TearDownSubTest() {
+	s.srv.Stop()
+}
+
+func (s *DevTLSPublicTestSuite) TearDownTest() {
+	s.srv.Stop()
+}
+
+func (s *DevTLSPublicTestSuite) TestStart() {
+	tests := []struct {
+		name         string
+		setup        func(cfg *server.DevTLS)
+		validateFunc func(certs *server.DevCertificates)
+	}{
+		{
+			name:  "secures client connections for the loopback hosts",
+			setup: func(*server.DevTLS) {},
+			validateFunc: func(certs *server.DevCertificates) {
+				nc, err := s.connect(nats.Secure(certs.ClientTLSConfig()))
+				s.Require().NoError(err)
+				defer nc.Close()
+
+				state, err := nc.TLSConnectionState()
+				s.Require().NoError(err)
+				cert := state.PeerCertificates[0]
+				s.Equal([]string{"localhost"}, cert.DNSNames)
+				s.Len(cert.IPAddresses, 2)
+				s.True(cert.NotAfter.After(time.Now().Add(24 * time.Hour)))
+
+				_, err = s.connect(nats.Secure(&tls.Config{
+					MinVersion: tls.VersionTLS12,
+				}))
+				s.ErrorContains(err, "certificate signed by unknown authority")
+
+				_, err = s.connect()
+				s.Error(err)
+			},
+		},
+		{
+			name: "issues certificates for hosts and client name",
+			setup: func(cfg *server.DevTLS) {
+				cfg.Hosts = []string{"nats.test", "10.0.0.1"}
+				cfg.ClientName = "ci"
+			},
+			validateFunc: func(certs *server.DevCertificates) {
+				config := certs.ClientTLSConfig()
+				config.ServerName = "nats.test"
+				nc, err := s.connect(nats.Secure(config))
+				s.Require().NoError(err)
+				defer nc.Close()
+
+				state, err := nc.TLSConnectionState()
+				s.Require().NoError(err)
+				cert := state.PeerCertificates[0]
+				s.Equal([]string{"nats.test"}, cert.DNSNames)
+				s.Require().Len(cert.IPAddresses, 1)
+				s.Equal("10.0.0.1", cert.IPAddresses[0].String())
+
+				client := s.parsePEM(certs.ClientCertPEM)
+				s.Equal("ci", client.Subject.CommonName)
+				_, err = client.Verify(x509.VerifyOptions{
+					Roots: certs.CAPool,
+					KeyUsages: []x509.ExtKeyUsage{
+						x509.ExtKeyUsageClientAuth,
+					},
+				})
+				s.NoError(err)
+
+				_, err = s.connect(nats.Secure(certs.ClientTLSConfig()))
+				s.ErrorContains(err, "127.0.0.1")
+			},
+		},
+		{
+			name:  "encodes certificates as pem",
+			setup: func(*server.DevTLS) {},
+			validateFunc: func(certs *server.DevCertificates) {
+				ca := s.parsePEM(certs.CAPEM)
+				s.True(ca.IsCA)
+
+				pair, err := tls.X509KeyPair(
+					certs.ClientCertPEM,
+					certs.ClientKeyPEM,
+				)
+				s.Require().NoError(err)
+				s.Equal(
+					certs.ClientCertificate.Certificate[0],
+					pair.Certificate[0],
+				)
+				s.Equal(
+					x509.ExtKeyUsageClientAuth,
+					s.parsePEM(certs.ClientCertPEM).ExtKeyUsage[0],
+				)
+			},
+		},
+		{
+			name: "requires client certificates when verifying",
+			setup: func(cfg *server.DevTLS) {
+				cfg.Verify = true
+			},
+			validateFunc: func(certs *server.DevCertificates) {
+				nc, err := s.connect(nats.Secure(certs.ClientTLSConfig()))
+				s.Require().NoError(err)
+				nc.Close()
+
+				_, err = s.connect(nats.Secure(&tls.Config{
+					MinVersion: tls.VersionTLS12,
+					RootCAs:    certs.CAPool,
+				}))
+				s.Error(err)
+			},
+		},
+		{
+			name:  "keeps the certificate authority across restarts",
+			setup: func(*server.DevTLS) {},
+			validateFunc: func(certs *server.DevCertificates) {
+				s.Require().NoError(s.srv.Restart())
+
+				restarted, err := s.srv.DevCertificates()
+				s.Require().NoError(err)
+				s.Equal(certs.CAPEM, restarted.CAPEM)
+				s.NotEqual(certs.ClientCertPEM, restarted.ClientCertPEM)
+
+				nc, err := s.connect(nats.Secure(certs.ClientTLSConfig()))
+				s.Require().NoError(err)
+				nc.Close()
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup(s.srv.Opts.DevTLS)
+			s.Require().NoError(s.srv.Start())
+
+			certs, err := s.srv.DevCertificates()
+			s.Require().NoError(err)
+
+			tc.validateFunc(certs)
+		})
+	}
+}
+
+func (s *DevTLSPublicTestSuite) TestDevCertificates() {
+	tests := []struct {
+		name         string
+		setup        func()
+		validateFunc func(certs *server.DevCertificates, err error)
+	}{
+		{
+			name:  "returns error before start",
+			setup: func() {},
+			validateFunc: func(certs *server.DevCertificates, err error) {
+				s.ErrorIs(err, server.ErrNoDevTLS)
+				s.Nil(certs)
+			},
+		},
+		{
+			name: "returns error without dev tls",
+			setup: func() {
+				s.srv.Opts.DevTLS = nil
+				s.Require().NoError(s.srv.Start())
+			},
+			validateFunc: func(certs *server.DevCertificates, err error) {
+				s.ErrorIs(err, server.ErrNoDevTLS)
+				s.Nil(certs)
+			},
+		},
+		{
+			name: "returns certificates of the stopped server",
+			setup: func() {
+				s.Require().NoError(s.srv.Start())
+				s.srv.Stop()
+			},
+			validateFunc: func(certs *server.DevCertificates, err error) {
+				s.Require().NoError(err)
+				s.NotEmpty(certs.CAPEM)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+
+			tc.validateFunc(s.srv.DevCertificates())
+		})
+	}
+}
+
+func (s *DevTLSPublicTestSuite) TestReload() {
+	tests := []struct {
+		name         string
+		change       func(opts *server.Options)
+		validateFunc func(certs *server.DevCertificates, err error)
+	}{
+		{
+			name: "keeps the certificates",
+			change: func(opts *server.Options) {
+				opts.MaxPayload = 1 << 20
+			},
+			validateFunc: func(certs *server.DevCertificates, err error) {
+				s.Require().NoError(err)
+
+				nc, err := s.connect(nats.Secure(certs.ClientTLSConfig()))
+				s.Require().NoError(err)
+				nc.Close()
+			},
+		},
+		{
+			name: "refuses dev tls change",
+			change: func(opts *server.Options) {
+				opts.DevTLS = &server.DevTLS{Verify: true}
+			},
+			validateFunc: func(_ *server.DevCertificates, err error) {
+				var reloadErr *server.ReloadError
+				s.Require().ErrorAs(err, &reloadErr)
+				s.Equal([]string{"DevTLS"}, reloadErr.Fields)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.Require().NoError(s.srv.Start())
+			certs, err := s.srv.DevCertificates()
+			s.Require().NoError(err)
+
+			newOpts := *s.srv.Opts
+			newOpts.Options = s.srv.Opts.Clone()
+			tc.change(&newOpts)
+
+			tc.validateFunc(certs, s.srv.Reload(&newOpts))
+		})
+	}
+}
+
+// connect connects to the server with opts.
+func (s *DevTLSPublicTestSuite) connect(
+	opts ...nats.Option,
+) (*nats.Conn, error) {
+	opts = append(opts, nats.NoReconnect(), nats.Timeout(time.Second))
+
+	return nats.Connect(
+		fmt.Sprintf("nats://127.0.0.1:%d", s.srv.Opts.Port),
+		opts...,
+	)
+}
+
+// parsePEM parses the PEM-encoded certificate data.
+func (s *DevTLSPublicTestSuite) parsePEM(
+	data []byte,
+) *x509.Certificate {
+	block, _ := pem.Decode(data)
+	s.Require().NotNil(block)
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	s.Require().NoError(err)
+
+	return cert
+}
+
+func TestDevTLSPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(DevTLSPublicTestSuite))
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// drainProgressInterval is how often Drain logs the number of clients still
+// connected.
+var drainProgressInterval = time.Second
+
+// Drain puts the embedded NATS server into lame-duck mode and shuts it down
+// once it has drained. The server stops accepting clients, shuts JetStream
+// down so in-flight acknowledgements are persisted, then closes the
+// remaining clients spread over LameDuckDuration. Progress is logged until
+// the server is down.
+//
+// When ctx ends first the server is shut down immediately, in the
+// background, and the returned error wraps both ErrDrainCanceled and the
+// context's error. Draining a server that is not running does nothing,
+// except while it is starting or already stopping, which returns a
+// *TransitionError.
+func (s *Server) Drain(
+	ctx context.Context,
+) error {
+	natsServer, err := s.beginStop()
+	if natsServer == nil {
+		return err
+	}
+
+	stoppingErr := s.runHooks(ctx, HookStopping, s.options().Hooks.OnStopping)
+
+	s.logger.Info(
+		"draining nats server",
+		"clients", natsServer.NumClients(),
+	)
+
+	// LameDuckShutdown returns without shutting down when there is no
+	// client listener to close, so Shutdown follows it unconditionally.
+	stopped := s.shutdown(ctx, func() {
+		natsServer.LameDuckShutdown()
+		natsServer.Shutdown()
+	})
+
+	ticker := time.NewTicker(drainProgressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case stoppedErr := <-stopped:
+			s.logger.Info("nats server drained successfully")
+			return errors.Join(stoppingErr, stoppedErr)
+		case <-ticker.C:
+			s.logger.Info(
+				"waiting for nats server to drain",
+				"clients", natsServer.NumClients(),
+			)
+		case <-ctx.Done():
+			s.logger.Warn(
+				"nats server drain canceled, shutting down",
+				"clients", natsServer.NumClients(),
+			)
+			go natsServer.Shutdown()
+			return errors.Join(
+				stoppingErr,
+				fmt.Errorf("%w: %w", ErrDrainCanceled, ctx.Err()),
+			)
+		}
+	}
+}
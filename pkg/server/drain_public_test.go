@@ -0,0 +1,186 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+type DrainPublicTestSuite struct {
+	suite.Suite
+
+	mockCtrl       *gomock.Controller
+	mockNATSServer *mocks.MockNATSServerInstance
+	srv            *server.Server
+	terminate      func()
+	newNATSServer  func(*natsserver.Options) (server.NATSServerInstance, error)
+	restoreDrain   func()
+}
+
+func (s *DrainPublicTestSuite) SetupSuite() {
+	s.newNATSServer = server.NewNATSServer
+	s.restoreDrain = server.SetDrainProgressInterval(5 * time.Millisecond)
+}
+
+func (s *DrainPublicTestSuite) TearDownSuite() {
+	server.NewNATSServer = s.newNATSServer
+	s.restoreDrain()
+}
+
+func (s *DrainPublicTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockNATSServer = mocks.NewMockNATSServerInstance(s.mockCtrl)
+	s.terminate = expectWaitForShutdown(s.mockNATSServer)
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		&server.Options{
+			Options:      &natsserver.Options{},
+			ReadyTimeout: 5 * time.Second,
+		},
+	)
+
+	server.NewNATSServer = func(
+		_ *natsserver.Options,
+	) (server.NATSServerInstance, error) {
+		return s.mockNATSServer, nil
+	}
+}
+
+func (s *DrainPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *DrainPublicTestSuite) TearDownSubTest() {
+	s.terminate()
+}
+
+func (s *DrainPublicTestSuite) TearDownTest() {
+	s.terminate()
+	s.mockCtrl.Finish()
+}
+
+func (s *DrainPublicTestSuite) startServer() {
+	s.mockNATSServer.EXPECT().Start().AnyTimes()
+	s.mockNATSServer.EXPECT().ReadyForConnections(gomock.Any()).Return(true)
+	s.mockNATSServer.EXPECT().SetLogger(gomock.Any(), true, true)
+	s.Require().NoError(s.srv.Start())
+}
+
+func (s *DrainPublicTestSuite) TestDrain() {
+	tests := []struct {
+		name         string
+		start        bool
+		timeout      time.Duration
+		mockSetup    func(release <-chan struct{})
+		validateFunc func(err error)
+	}{
+		{
+			name:    "drains and shuts down running server",
+			start:   true,
+			timeout: time.Second,
+			mockSetup: func(_ <-chan struct{}) {
+				s.mockNATSServer.EXPECT().NumClients().Return(0).AnyTimes()
+				s.mockNATSServer.EXPECT().LameDuckShutdown().Times(1)
+				s.mockNATSServer.EXPECT().Shutdown().Times(1)
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name:    "reports progress while clients remain",
+			start:   true,
+			timeout: time.Second,
+			mockSetup: func(_ <-chan struct{}) {
+				// One call announces the drain; the rest are progress.
+				s.mockNATSServer.EXPECT().NumClients().Return(3).MinTimes(3)
+				s.mockNATSServer.EXPECT().
+					LameDuckShutdown().
+					Do(func() { time.Sleep(50 * time.Millisecond) }).
+					Times(1)
+				s.mockNATSServer.EXPECT().Shutdown().Times(1)
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name:    "shuts down immediately when deadline expires",
+			start:   true,
+			timeout: 20 * time.Millisecond,
+			mockSetup: func(release <-chan struct{}) {
+				s.mockNATSServer.EXPECT().NumClients().Return(3).AnyTimes()
+				s.mockNATSServer.EXPECT().
+					LameDuckShutdown().
+					Do(func() { <-release }).
+					Times(1)
+				// Called in the background by the forced shutdown, and
+				// again once the released lame-duck goroutine finishes.
+				s.mockNATSServer.EXPECT().Shutdown().AnyTimes()
+			},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrDrainCanceled)
+				s.ErrorIs(err, context.DeadlineExceeded)
+			},
+		},
+		{
+			name:      "returns nil when server never started",
+			timeout:   time.Second,
+			mockSetup: func(_ <-chan struct{}) {},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			if tc.start {
+				s.startServer()
+			}
+
+			release := make(chan struct{})
+			defer close(release)
+
+			tc.mockSetup(release)
+
+			ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+
+			tc.validateFunc(s.srv.Drain(ctx))
+		})
+	}
+}
+
+func TestDrainPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(DrainPublicTestSuite))
+}
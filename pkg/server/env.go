@@ -0,0 +1,414 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+)
+
+// envSettings lists the options ApplyEnv reads, in the order it reads them.
+var envSettings = []envSetting{
+	{
+		suffix: "HOST",
+		field:  "Host",
+		set:    envField(envString, func(o *Options) *string { return &o.Host }),
+	},
+	{
+		suffix: "PORT",
+		field:  "Port",
+		set:    envField(strconv.Atoi, func(o *Options) *int { return &o.Port }),
+	},
+	{
+		suffix: "SERVER_NAME",
+		field:  "ServerName",
+		set: envField(
+			envString,
+			func(o *Options) *string { return &o.ServerName },
+		),
+	},
+	{
+		suffix: "DEBUG",
+		field:  "Debug",
+		set: envField(
+			strconv.ParseBool,
+			func(o *Options) *bool { return &o.Debug },
+		),
+	},
+	{
+		suffix: "TRACE",
+		field:  "Trace",
+		set: envField(
+			strconv.ParseBool,
+			func(o *Options) *bool { return &o.Trace },
+		),
+	},
+	{
+		suffix: "READY_TIMEOUT",
+		field:  "ReadyTimeout",
+		set: envField(
+			envDuration,
+			func(o *Options) *time.Duration { return &o.ReadyTimeout },
+		),
+	},
+	{
+		suffix: "DRAIN_ON_STOP",
+		field:  "DrainOnStop",
+		set: envField(
+			strconv.ParseBool,
+			func(o *Options) *bool { return &o.DrainOnStop },
+		),
+	},
+	{
+		suffix: "ALLOW_RESTART",
+		field:  "AllowRestart",
+		set: envField(
+			strconv.ParseBool,
+			func(o *Options) *bool { return &o.AllowRestart },
+		),
+	},
+	{
+		suffix: "USER",
+		field:  "Username",
+		set: envField(
+			envString,
+			func(o *Options) *string { return &o.Username },
+		),
+	},
+	{
+		suffix: "PASSWORD",
+		field:  "Password",
+		secret: true,
+		set: envField(
+			envString,
+			func(o *Options) *string { return &o.Password },
+		),
+	},
+	{
+		suffix: "TOKEN",
+		field:  "Authorization",
+		secret: true,
+		set: envField(
+			envString,
+			func(o *Options) *string { return &o.Authorization },
+		),
+	},
+	{
+		suffix: "JETSTREAM",
+		field:  "JetStream",
+		set: envField(
+			strconv.ParseBool,
+			func(o *Options) *bool { return &o.JetStream },
+		),
+	},
+	{
+		suffix: "JETSTREAM_STORE_DIR",
+		field:  "StoreDir",
+		set: envField(
+			envString,
+			func(o *Options) *string { return &o.StoreDir },
+		),
+	},
+	{
+		suffix: "JETSTREAM_MAX_MEMORY",
+		field:  "JetStreamMaxMemory",
+		set: envField(
+			envSize,
+			func(o *Options) *int64 { return &o.JetStreamMaxMemory },
+		),
+	},
+	{
+		suffix: "JETSTREAM_MAX_STORE",
+		field:  "JetStreamMaxStore",
+		set: envField(
+			envSize,
+			func(o *Options) *int64 { return &o.JetStreamMaxStore },
+		),
+	},
+	{
+		suffix: "MAX_CONNECTIONS",
+		field:  "MaxConn",
+		set:    envField(strconv.Atoi, func(o *Options) *int { return &o.MaxConn }),
+	},
+	{
+		suffix: "MAX_PAYLOAD",
+		field:  "MaxPayload",
+		set: envField(
+			envSize32,
+			func(o *Options) *int32 { return &o.MaxPayload },
+		),
+	},
+	{
+		suffix: "MAX_PENDING",
+		field:  "MaxPending",
+		set: envField(
+			envSize,
+			func(o *Options) *int64 { return &o.MaxPending },
+		),
+	},
+	{
+		suffix: "WRITE_DEADLINE",
+		field:  "WriteDeadline",
+		set: envField(
+			envDuration,
+			func(o *Options) *time.Duration { return &o.WriteDeadline },
+		),
+	},
+	{
+		suffix: "PING_INTERVAL",
+		field:  "PingInterval",
+		set: envField(
+			envDuration,
+			func(o *Options) *time.Duration { return &o.PingInterval },
+		),
+	},
+	{
+		suffix: "HTTP_PORT",
+		field:  "HTTPPort",
+		set: envField(
+			strconv.Atoi,
+			func(o *Options) *int { return &o.HTTPPort },
+		),
+	},
+	{
+		suffix: "CLUSTER_NAME",
+		field:  "Cluster.Name",
+		set: envField(
+			envString,
+			func(o *Options) *string { return &o.Cluster.Name },
+		),
+	},
+	{
+		suffix: "CLUSTER_HOST",
+		field:  "Cluster.Host",
+		set: envField(
+			envString,
+			func(o *Options) *string { return &o.Cluster.Host },
+		),
+	},
+	{
+		suffix: "CLUSTER_PORT",
+		field:  "Cluster.Port",
+		set: envField(
+			strconv.Atoi,
+			func(o *Options) *int { return &o.Cluster.Port },
+		),
+	},
+	{
+		suffix: "CLUSTER_ROUTES",
+		field:  "Routes",
+		set: envField(
+			envURLs,
+			func(o *Options) *[]*url.URL { return &o.Routes },
+		),
+	},
+}
+
+// sizeUnits are the multipliers of the size suffixes envSize accepts,
+// following the NATS server configuration format: K, M, G, and T are
+// powers of 1000; KB, MB, GB, and TB, and their KiB forms, of 1024.
+var sizeUnits = map[string]int64{
+	"":  1,
+	"K": 1000, "KB": 1 << 10, "KI": 1 << 10, "KIB": 1 << 10,
+	"M": 1000 * 1000, "MB": 1 << 20, "MI": 1 << 20, "MIB": 1 << 20,
+	"G": 1000 * 1000 * 1000, "GB": 1 << 30, "GI": 1 << 30, "GIB": 1 << 30,
+	"T": 1000 * 1000 * 1000 * 1000, "TB": 1 << 40, "TI": 1 << 40,
+	"TIB": 1 << 40,
+}
+
+// ApplyEnv overrides options with environment variables named by prefix
+// followed by the option, such as NATS_EMBED_PORT for prefix "NATS_EMBED_".
+// An empty prefix stands for DefaultEnvPrefix. Variables that are unset or
+// empty leave their option alone.
+//
+// Durations are written as "10s", or as a number of seconds; sizes as a
+// number of bytes with an optional unit, such as "512MB"; and lists
+// separated by commas. ApplyEnv returns the options it set, with the values
+// of credentials redacted, so they can be logged. When any variable cannot
+// be parsed, it returns every such problem and leaves opts unchanged.
+func (o *Options) ApplyEnv(
+	prefix string,
+) ([]EnvVar, error) {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+
+	overlay := *o
+	if o.Options == nil {
+		overlay.Options = &natsserver.Options{}
+	} else {
+		overlay.Options = o.Options.Clone()
+	}
+
+	var applied []EnvVar
+	var errs []error
+	for _, setting := range envSettings {
+		name := prefix + setting.suffix
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		if err := setting.set(&overlay, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+
+		if setting.secret {
+			value = redacted
+		}
+		applied = append(applied, EnvVar{
+			Name:  name,
+			Field: setting.field,
+			Value: value,
+		})
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf(
+			"error applying environment: %w",
+			errors.Join(errs...),
+		)
+	}
+
+	*o = overlay
+
+	return applied, nil
+}
+
+// envField returns a function setting the field returned by field to the
+// value parsed by parse.
+func envField[T any](
+	parse func(string) (T, error),
+	field func(*Options) *T,
+) func(*Options, string) error {
+	return func(
+		opts *Options,
+		value string,
+	) error {
+		v, err := parse(value)
+		if err != nil {
+			return err
+		}
+
+		*field(opts) = v
+
+		return nil
+	}
+}
+
+// envString returns value as is.
+func envString(
+	value string,
+) (string, error) {
+	return value, nil
+}
+
+// envDuration parses value as a duration such as "10s", or a number of
+// seconds.
+func envDuration(
+	value string,
+) (time.Duration, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	return time.ParseDuration(value)
+}
+
+// envSize parses value as a number of bytes with an optional unit, such as
+// "512MB".
+func envSize(
+	value string,
+) (int64, error) {
+	digits := strings.TrimRightFunc(value, func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	unit := strings.ToUpper(strings.TrimSpace(value[len(digits):]))
+
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", value, unit)
+	}
+
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	if n > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("invalid size %q: too large", value)
+	}
+
+	return n * multiplier, nil
+}
+
+// envSize32 parses value as envSize does, for sizes held in an int32.
+func envSize32(
+	value string,
+) (int32, error) {
+	n, err := envSize(value)
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid size %q: too large", value)
+	}
+
+	return int32(n), nil
+}
+
+// envURLs parses value as a list of URLs separated by commas.
+func envURLs(
+	value string,
+) ([]*url.URL, error) {
+	var urls []*url.URL
+	for _, item := range envList(value) {
+		u, err := url.Parse(item)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, u)
+	}
+
+	return urls, nil
+}
+
+// envList splits value at commas, dropping blank items.
+func envList(
+	value string,
+) []string {
+	var items []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
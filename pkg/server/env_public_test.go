@@ -0,0 +1,272 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+type EnvPublicTestSuite struct {
+	suite.Suite
+}
+
+func (s *EnvPublicTestSuite) TestApplyEnv() {
+	tests := []struct {
+		name         string
+		prefix       string
+		env          map[string]string
+		opts         func() *server.Options
+		validateFunc func(
+			opts *server.Options,
+			applied []server.EnvVar,
+			err error,
+		)
+	}{
+		{
+			name: "applies every variable",
+			env: map[string]string{
+				"NATS_EMBED_HOST":                 "0.0.0.0",
+				"NATS_EMBED_PORT":                 "4333",
+				"NATS_EMBED_SERVER_NAME":          "orders",
+				"NATS_EMBED_DEBUG":                "true",
+				"NATS_EMBED_TRACE":                "1",
+				"NATS_EMBED_READY_TIMEOUT":        "10s",
+				"NATS_EMBED_DRAIN_ON_STOP":        "true",
+				"NATS_EMBED_ALLOW_RESTART":        "true",
+				"NATS_EMBED_USER":                 "app",
+				"NATS_EMBED_PASSWORD":             "s3cr3t",
+				"NATS_EMBED_TOKEN":                "t0k3n",
+				"NATS_EMBED_JETSTREAM":            "true",
+				"NATS_EMBED_JETSTREAM_STORE_DIR":  "/data",
+				"NATS_EMBED_JETSTREAM_MAX_MEMORY": "512MB",
+				"NATS_EMBED_JETSTREAM_MAX_STORE":  "2GiB",
+				"NATS_EMBED_MAX_CONNECTIONS":      "100",
+				"NATS_EMBED_MAX_PAYLOAD":          "8mb",
+				"NATS_EMBED_MAX_PENDING":          "64K",
+				"NATS_EMBED_WRITE_DEADLINE":       "3",
+				"NATS_EMBED_PING_INTERVAL":        "1m",
+				"NATS_EMBED_HTTP_PORT":            "8222",
+				"NATS_EMBED_CLUSTER_NAME":         "east",
+				"NATS_EMBED_CLUSTER_HOST":         "10.0.0.1",
+				"NATS_EMBED_CLUSTER_PORT":         "6222",
+				"NATS_EMBED_CLUSTER_ROUTES": "nats://10.0.0.2:6222, " +
+					",nats://10.0.0.3:6222",
+			},
+			opts: server.DefaultOptions,
+			validateFunc: func(
+				opts *server.Options,
+				applied []server.EnvVar,
+				err error,
+			) {
+				s.Require().NoError(err)
+				s.Equal("0.0.0.0", opts.Host)
+				s.Equal(4333, opts.Port)
+				s.Equal("orders", opts.ServerName)
+				s.True(opts.Debug)
+				s.True(opts.Trace)
+				s.Equal(10*time.Second, opts.ReadyTimeout)
+				s.True(opts.DrainOnStop)
+				s.True(opts.AllowRestart)
+				s.Equal("app", opts.Username)
+				s.Equal("s3cr3t", opts.Password)
+				s.Equal("t0k3n", opts.Authorization)
+				s.True(opts.JetStream)
+				s.Equal("/data", opts.StoreDir)
+				s.Equal(int64(512<<20), opts.JetStreamMaxMemory)
+				s.Equal(int64(2<<30), opts.JetStreamMaxStore)
+				s.Equal(100, opts.MaxConn)
+				s.Equal(int32(8<<20), opts.MaxPayload)
+				s.Equal(int64(64000), opts.MaxPending)
+				s.Equal(3*time.Second, opts.WriteDeadline)
+				s.Equal(time.Minute, opts.PingInterval)
+				s.Equal(8222, opts.HTTPPort)
+				s.Equal("east", opts.Cluster.Name)
+				s.Equal("10.0.0.1", opts.Cluster.Host)
+				s.Equal(6222, opts.Cluster.Port)
+				s.Require().Len(opts.Routes, 2)
+				s.Equal("nats://10.0.0.3:6222", opts.Routes[1].String())
+
+				s.Len(applied, 25)
+				s.Contains(applied, server.EnvVar{
+					Name:  "NATS_EMBED_PORT",
+					Field: "Port",
+					Value: "4333",
+				})
+				s.Contains(applied, server.EnvVar{
+					Name:  "NATS_EMBED_CLUSTER_PORT",
+					Field: "Cluster.Port",
+					Value: "6222",
+				})
+			},
+		},
+		{
+			name: "redacts credentials",
+			env: map[string]string{
+				"NATS_EMBED_PASSWORD": "s3cr3t",
+				"NATS_EMBED_TOKEN":    "t0k3n",
+			},
+			opts: server.DefaultOptions,
+			validateFunc: func(
+				_ *server.Options,
+				applied []server.EnvVar,
+				err error,
+			) {
+				s.Require().NoError(err)
+				s.Equal([]server.EnvVar{
+					{
+						Name:  "NATS_EMBED_PASSWORD",
+						Field: "Password",
+						Value: "[REDACTED]",
+					},
+					{
+						Name:  "NATS_EMBED_TOKEN",
+						Field: "Authorization",
+						Value: "[REDACTED]",
+					},
+				}, applied)
+			},
+		},
+		{
+			name:   "uses prefix",
+			prefix: "ORDERS_",
+			env: map[string]string{
+				"ORDERS_PORT":     "4444",
+				"NATS_EMBED_PORT": "4333",
+			},
+			opts: server.DefaultOptions,
+			validateFunc: func(
+				opts *server.Options,
+				applied []server.EnvVar,
+				err error,
+			) {
+				s.Require().NoError(err)
+				s.Equal(4444, opts.Port)
+				s.Equal([]server.EnvVar{
+					{Name: "ORDERS_PORT", Field: "Port", Value: "4444"},
+				}, applied)
+			},
+		},
+		{
+			name: "ignores empty variables",
+			env:  map[string]string{"NATS_EMBED_HOST": ""},
+			opts: server.DefaultOptions,
+			validateFunc: func(
+				opts *server.Options,
+				applied []server.EnvVar,
+				err error,
+			) {
+				s.Require().NoError(err)
+				s.Equal("127.0.0.1", opts.Host)
+				s.Empty(applied)
+			},
+		},
+		{
+			name: "creates nats options when missing",
+			env:  map[string]string{"NATS_EMBED_PORT": "4333"},
+			opts: func() *server.Options {
+				return &server.Options{}
+			},
+			validateFunc: func(
+				opts *server.Options,
+				_ []server.EnvVar,
+				err error,
+			) {
+				s.Require().NoError(err)
+				s.Equal(4333, opts.Port)
+			},
+		},
+		{
+			name: "returns every error and leaves options unchanged",
+			env: map[string]string{
+				"NATS_EMBED_HOST":                 "0.0.0.0",
+				"NATS_EMBED_PORT":                 "http",
+				"NATS_EMBED_DEBUG":                "maybe",
+				"NATS_EMBED_READY_TIMEOUT":        "soon",
+				"NATS_EMBED_JETSTREAM_MAX_MEMORY": "512XB",
+				"NATS_EMBED_JETSTREAM_MAX_STORE":  "-1GB",
+				"NATS_EMBED_MAX_PENDING":          "10000000TB",
+				"NATS_EMBED_MAX_PAYLOAD":          "4GB",
+				"NATS_EMBED_CLUSTER_ROUTES":       "nats://%zz",
+			},
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{Host: "127.0.0.1"},
+				}
+			},
+			validateFunc: func(
+				opts *server.Options,
+				applied []server.EnvVar,
+				err error,
+			) {
+				s.Nil(applied)
+				s.Equal("127.0.0.1", opts.Host)
+				s.ErrorContains(err, "error applying environment: ")
+				s.ErrorContains(err, "NATS_EMBED_PORT: strconv.Atoi")
+				s.ErrorContains(err, "NATS_EMBED_DEBUG: strconv.ParseBool")
+				s.ErrorContains(err, "NATS_EMBED_READY_TIMEOUT: time: invalid")
+				s.ErrorContains(err, "NATS_EMBED_JETSTREAM_MAX_MEMORY: "+
+					`invalid size "512XB": unknown unit "XB"`)
+				s.ErrorContains(err, "NATS_EMBED_JETSTREAM_MAX_STORE: "+
+					`invalid size "-1GB"`)
+				s.ErrorContains(err, "NATS_EMBED_MAX_PENDING: "+
+					`invalid size "10000000TB": too large`)
+				s.ErrorContains(err, "NATS_EMBED_MAX_PAYLOAD: "+
+					`invalid size "4GB": too large`)
+				s.ErrorContains(err, "NATS_EMBED_CLUSTER_ROUTES: parse ")
+			},
+		},
+		{
+			name: "returns error when payload size is invalid",
+			env:  map[string]string{"NATS_EMBED_MAX_PAYLOAD": "1 parsec"},
+			opts: server.DefaultOptions,
+			validateFunc: func(
+				_ *server.Options,
+				_ []server.EnvVar,
+				err error,
+			) {
+				s.ErrorContains(err, "NATS_EMBED_MAX_PAYLOAD: "+
+					`invalid size "1 parsec": unknown unit "PARSEC"`)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			for name, value := range tc.env {
+				s.T().Setenv(name, value)
+			}
+
+			opts := tc.opts()
+			applied, err := opts.ApplyEnv(tc.prefix)
+			tc.validateFunc(opts, applied, err)
+		})
+	}
+}
+
+func TestEnvPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvPublicTestSuite))
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import "errors"
+
+var (
+	// ErrStartCanceled is returned by StartContext when its context ends
+	// before the server is ready for connections. The context's error is
+	// wrapped alongside it.
+	ErrStartCanceled = errors.New("server start canceled")
+
+	// ErrStopCanceled is returned by StopContext when its context ends
+	// before the server has shut down. The context's error is wrapped
+	// alongside it.
+	ErrStopCanceled = errors.New("server stop canceled")
+
+	// ErrDrainCanceled is returned by Drain when its context ends before
+	// the server has drained. The context's error is wrapped alongside it.
+	ErrDrainCanceled = errors.New("server drain canceled")
+
+	// ErrInvalidOptions is matched by every *ValidationError.
+	ErrInvalidOptions = errors.New("invalid options")
+
+	// ErrNotReady is the cause of a StartError when the NATS server is not
+	// ready for connections within Options.ReadyTimeout.
+	ErrNotReady = errors.New("server not ready for connections")
+
+	// ErrPortInUse is matched by a *ListenerError whose address is taken.
+	ErrPortInUse = errors.New("port already in use")
+
+	// ErrStoreDirLocked is the cause of a StartError when another server,
+	// in this process or another, runs with the same JetStream store
+	// directory.
+	ErrStoreDirLocked = errors.New("jetstream store directory locked")
+
+	// ErrAlreadyStarted is returned by Start and StartContext when the
+	// server is already starting, running, or draining. The
+	// *TransitionError is wrapped alongside it.
+	ErrAlreadyStarted = errors.New("server already started")
+
+	// ErrServerTerminated is the cause reported by Wait when the NATS
+	// server shut down without being stopped.
+	ErrServerTerminated = errors.New("nats server terminated unexpectedly")
+
+	// ErrNotRunning is returned by operations that need a running server.
+	ErrNotRunning = errors.New("server not running")
+
+	// ErrNotReloadable is matched by every *ReloadError, and by the
+	// errors of the NATS server for options it cannot reload.
+	ErrNotReloadable = errors.New("config reload not supported")
+
+	// ErrNilOptions is returned by Reload when given no options.
+	ErrNilOptions = errors.New("options are nil")
+
+	// ErrInvalidTransition is matched by every *TransitionError.
+	ErrInvalidTransition = errors.New("invalid server state transition")
+
+	// ErrUnknownSecretScheme is returned by Start and Reload for a secret
+	// reference whose scheme has no resolver.
+	ErrUnknownSecretScheme = errors.New("no resolver for secret scheme")
+
+	// ErrPlaintextPassword is returned by Start and Reload when
+	// Options.RequireHashedPasswords is set and a user's password is not
+	// bcrypt hashed.
+	ErrPlaintextPassword = errors.New("password not bcrypt hashed")
+
+	// ErrUnknownUser is returned by RemoveUser and UpdatePermissions when
+	// no user has the name given.
+	ErrUnknownUser = errors.New("unknown user")
+
+	// ErrUnknownAccount is returned by RemoveAccount, and the account JWT
+	// methods of operator mode, when no account has the name given.
+	ErrUnknownAccount = errors.New("unknown account")
+
+	// ErrLastUser is returned by RemoveUser and RemoveAccount when the
+	// removal would leave the server with no way to authenticate clients,
+	// which the NATS server takes as authentication being turned off.
+	ErrLastUser = errors.New("cannot remove the last user")
+
+	// ErrInvalidCredentials is returned by an Authenticator refusing a
+	// client for its credentials. The refusal is not logged, since the
+	// NATS server logs failed authentication itself.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+
+	// ErrNotOperatorMode is returned by the account JWT methods of a
+	// server not running with Options.Operator.
+	ErrNotOperatorMode = errors.New("server not in operator mode")
+
+	// ErrAccountExists is returned by AddAccountJWT when an account
+	// already has the name given.
+	ErrAccountExists = errors.New("account already exists")
+
+	// ErrNoDevTLS is returned by DevCertificates when the server has not
+	// run with Options.DevTLS.
+	ErrNoDevTLS = errors.New("server not using development tls")
+
+	// ErrNoJetStreamAssets is returned by ReconcileJetStream when the
+	// server has not run with Options.JetStreamAssets.
+	ErrNoJetStreamAssets = errors.New("server has no jetstream assets")
+
+	// ErrStoreAhead is returned when the JetStream store has applied a
+	// migration newer than the migrations of Options.JetStreamAssets, as
+	// when an older release runs on the store of a newer one.
+	ErrStoreAhead = errors.New("jetstream store ahead of migrations")
+
+	// ErrMigrationOutOfOrder is returned when a migration of
+	// Options.JetStreamAssets not yet applied has a version below one the
+	// JetStream store has applied, so it would run after migrations meant
+	// to follow it.
+	ErrMigrationOutOfOrder = errors.New("jetstream migration out of order")
+
+	// ErrMigrationRecorded is returned when a migration applied was
+	// recorded in the meantime, as when another server starting on the
+	// same store applied it too.
+	ErrMigrationRecorded = errors.New("jetstream migration already recorded")
+)
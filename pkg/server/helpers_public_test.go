@@ -0,0 +1,78 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"net"
+	"sync"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+// freePort returns a local port that was free a moment ago.
+func freePort(
+	t *testing.T,
+) int {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// expectStart expects m to be started and become ready, as the NATS
+// server of a start that succeeds.
+func expectStart(
+	m *mocks.MockNATSServerInstance,
+) {
+	m.EXPECT().Start().AnyTimes()
+	m.EXPECT().ReadyForConnections(gomock.Any()).Return(true)
+	m.EXPECT().SetLogger(gomock.Any(), true, true)
+}
+
+// expectNotReady expects m to be started and never become ready, as the
+// NATS server of a start that times out, which is then shut down.
+func expectNotReady(
+	m *mocks.MockNATSServerInstance,
+) {
+	m.EXPECT().Start().AnyTimes()
+	m.EXPECT().ReadyForConnections(gomock.Any()).Return(false)
+	m.EXPECT().Shutdown()
+}
+
+// expectWaitForShutdown lets m's WaitForShutdown be called any number of
+// times, blocking until the returned function is called, which stands in
+// for the NATS server shutting down.
+func expectWaitForShutdown(
+	m *mocks.MockNATSServerInstance,
+) func() {
+	shutdown := make(chan struct{})
+	m.EXPECT().WaitForShutdown().Do(func() { <-shutdown }).AnyTimes()
+
+	return sync.OnceFunc(func() { close(shutdown) })
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Error describes the failed hook.
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s hook %q failed: %v", e.Stage, e.Name, e.Err)
+}
+
+// Unwrap returns the hook's error.
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// runHooks runs hooks in order, returning their failures as *HookError
+// values joined together. Starting and ready hooks stop at the first
+// failure, while stopping and stopped hooks are all run regardless.
+func (s *Server) runHooks(
+	ctx context.Context,
+	stage HookStage,
+	hooks []Hook,
+) error {
+	var errs []error
+	for _, hook := range hooks {
+		err := s.runHook(ctx, stage, hook)
+		if err == nil {
+			continue
+		}
+
+		s.logger.Error(
+			"nats server hook failed",
+			"stage", stage,
+			"hook", hook.Name,
+			"error", err,
+		)
+
+		errs = append(errs, err)
+		if stage == HookStarting || stage == HookReady {
+			break
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// runHook runs hook, giving up once ctx ends or the hook's timeout elapses.
+// The hook runs in its own goroutine so that one ignoring its context
+// cannot hold up the lifecycle method running it.
+func (s *Server) runHook(
+	ctx context.Context,
+	stage HookStage,
+	hook Hook,
+) error {
+	if hook.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hook.Timeout)
+		defer cancel()
+	}
+
+	// The result channel is buffered so an abandoned hook can still
+	// deliver and exit.
+	result := make(chan error, 1)
+	go func() {
+		result <- hook.Fn(ctx, s)
+	}()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	return &HookError{
+		Stage: stage,
+		Name:  hook.Name,
+		Err:   err,
+	}
+}
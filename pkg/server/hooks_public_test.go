@@ -0,0 +1,364 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+type HooksPublicTestSuite struct {
+	suite.Suite
+
+	mockCtrl       *gomock.Controller
+	mockNATSServer *mocks.MockNATSServerInstance
+	srv            *server.Server
+	terminate      func()
+	newNATSServer  func(*natsserver.Options) (server.NATSServerInstance, error)
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (s *HooksPublicTestSuite) SetupSuite() {
+	s.newNATSServer = server.NewNATSServer
+}
+
+func (s *HooksPublicTestSuite) TearDownSuite() {
+	server.NewNATSServer = s.newNATSServer
+}
+
+func (s *HooksPublicTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockNATSServer = mocks.NewMockNATSServerInstance(s.mockCtrl)
+	s.terminate = expectWaitForShutdown(s.mockNATSServer)
+	s.calls = nil
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		&server.Options{
+			Options:      &natsserver.Options{},
+			ReadyTimeout: 5 * time.Second,
+		},
+	)
+
+	server.NewNATSServer = func(
+		_ *natsserver.Options,
+	) (server.NATSServerInstance, error) {
+		return s.mockNATSServer, nil
+	}
+}
+
+func (s *HooksPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *HooksPublicTestSuite) TearDownSubTest() {
+	s.terminate()
+}
+
+func (s *HooksPublicTestSuite) TearDownTest() {
+	s.terminate()
+	s.mockCtrl.Finish()
+}
+
+// hook returns a hook named name that records its call, along with the
+// server's state at the time, and returns err.
+func (s *HooksPublicTestSuite) hook(
+	name string,
+	err error,
+) server.Hook {
+	return server.Hook{
+		Name: name,
+		Fn: func(_ context.Context, srv *server.Server) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.calls = append(s.calls, name+":"+srv.State().String())
+
+			return err
+		},
+	}
+}
+
+// blockingHook returns a hook named name, with the given timeout, that
+// blocks until its context ends.
+func blockingHook(
+	name string,
+	timeout time.Duration,
+) server.Hook {
+	return server.Hook{
+		Name:    name,
+		Timeout: timeout,
+		Fn: func(ctx context.Context, _ *server.Server) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+}
+
+func (s *HooksPublicTestSuite) recorded() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls
+}
+
+func (s *HooksPublicTestSuite) TestStartHooks() {
+	errHook := errors.New("hook failed")
+
+	tests := []struct {
+		name         string
+		hooks        server.Hooks
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name: "runs starting then ready hooks in order",
+			hooks: server.Hooks{
+				OnStarting: []server.Hook{
+					s.hook("first", nil),
+					s.hook("second", nil),
+				},
+				OnReady: []server.Hook{s.hook("ready", nil)},
+			},
+			mockSetup: func() { expectStart(s.mockNATSServer) },
+			validateFunc: func(err error) {
+				s.NoError(err)
+				s.Equal(
+					[]string{
+						"first:starting",
+						"second:starting",
+						"ready:starting",
+					},
+					s.recorded(),
+				)
+				s.Equal(server.StateRunning, s.srv.State())
+			},
+		},
+		{
+			name: "aborts start when starting hook fails",
+			hooks: server.Hooks{
+				OnStarting: []server.Hook{
+					s.hook("first", errHook),
+					s.hook("second", nil),
+				},
+				OnReady: []server.Hook{s.hook("ready", nil)},
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				var hookErr *server.HookError
+				s.Require().ErrorAs(err, &hookErr)
+				s.Equal(server.HookStarting, hookErr.Stage)
+				s.Equal("first", hookErr.Name)
+				s.ErrorIs(err, errHook)
+				s.Equal([]string{"first:starting"}, s.recorded())
+				s.Equal(server.StateFailed, s.srv.State())
+			},
+		},
+		{
+			name: "rolls back start when ready hook fails",
+			hooks: server.Hooks{
+				OnReady: []server.Hook{
+					s.hook("first", errHook),
+					s.hook("second", nil),
+				},
+			},
+			mockSetup: func() {
+				expectStart(s.mockNATSServer)
+				s.mockNATSServer.EXPECT().Shutdown().Times(1)
+			},
+			validateFunc: func(err error) {
+				var hookErr *server.HookError
+				s.Require().ErrorAs(err, &hookErr)
+				s.Equal(server.HookReady, hookErr.Stage)
+				s.ErrorIs(err, errHook)
+				s.Equal([]string{"first:starting"}, s.recorded())
+				s.Equal(server.StateFailed, s.srv.State())
+			},
+		},
+		{
+			name: "fails hook that outlives its timeout",
+			hooks: server.Hooks{
+				OnStarting: []server.Hook{
+					blockingHook("slow", 10*time.Millisecond),
+				},
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				var hookErr *server.HookError
+				s.Require().ErrorAs(err, &hookErr)
+				s.Equal("slow", hookErr.Name)
+				s.ErrorIs(err, context.DeadlineExceeded)
+			},
+		},
+		{
+			name: "abandons hook that ignores its context",
+			hooks: server.Hooks{
+				OnStarting: []server.Hook{
+					{
+						Name:    "stuck",
+						Timeout: 10 * time.Millisecond,
+						Fn: func(_ context.Context, _ *server.Server) error {
+							time.Sleep(time.Second)
+							return nil
+						},
+					},
+				},
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, context.DeadlineExceeded)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.srv.Opts.Hooks = tc.hooks
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.Start())
+		})
+	}
+}
+
+func (s *HooksPublicTestSuite) TestStopHooks() {
+	errHook := errors.New("hook failed")
+
+	tests := []struct {
+		name         string
+		drain        bool
+		hooks        server.Hooks
+		validateFunc func(err error)
+	}{
+		{
+			name: "runs stopping then stopped hooks in order",
+			hooks: server.Hooks{
+				OnStopping: []server.Hook{s.hook("stopping", nil)},
+				OnStopped:  []server.Hook{s.hook("stopped", nil)},
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+				s.Equal(
+					[]string{"stopping:draining", "stopped:draining"},
+					s.recorded(),
+				)
+				s.Equal(server.StateStopped, s.srv.State())
+			},
+		},
+		{
+			name:  "runs hooks around drain",
+			drain: true,
+			hooks: server.Hooks{
+				OnStopping: []server.Hook{s.hook("stopping", nil)},
+				OnStopped:  []server.Hook{s.hook("stopped", nil)},
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+				s.Equal(
+					[]string{"stopping:draining", "stopped:draining"},
+					s.recorded(),
+				)
+			},
+		},
+		{
+			name: "runs every hook and returns their failures",
+			hooks: server.Hooks{
+				OnStopping: []server.Hook{
+					s.hook("first", errHook),
+					s.hook("second", nil),
+				},
+				OnStopped: []server.Hook{s.hook("stopped", errHook)},
+			},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, errHook)
+				s.ErrorContains(err, `stopping hook "first" failed`)
+				s.ErrorContains(err, `stopped hook "stopped" failed`)
+				s.Equal(
+					[]string{
+						"first:draining",
+						"second:draining",
+						"stopped:draining",
+					},
+					s.recorded(),
+				)
+				s.Equal(server.StateStopped, s.srv.State())
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			expectStart(s.mockNATSServer)
+			s.Require().NoError(s.srv.Start())
+
+			s.srv.Opts.Hooks = tc.hooks
+			s.mockNATSServer.EXPECT().Shutdown().Times(1)
+
+			var err error
+			if tc.drain {
+				s.mockNATSServer.EXPECT().NumClients().Return(0).AnyTimes()
+				s.mockNATSServer.EXPECT().LameDuckShutdown().Times(1)
+				err = s.srv.Drain(context.Background())
+			} else {
+				err = s.srv.StopContext(context.Background())
+			}
+
+			tc.validateFunc(err)
+		})
+	}
+}
+
+func (s *HooksPublicTestSuite) TestError() {
+	err := &server.HookError{
+		Stage: server.HookReady,
+		Name:  "provision",
+		Err:   errors.New("boom"),
+	}
+
+	s.EqualError(err, `ready hook "provision" failed: boom`)
+}
+
+func (s *HooksPublicTestSuite) TestUnwrap() {
+	cause := errors.New("boom")
+	err := &server.HookError{
+		Stage: server.HookStopped,
+		Name:  "flush",
+		Err:   cause,
+	}
+
+	s.Same(cause, errors.Unwrap(err))
+}
+
+func TestHooksPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(HooksPublicTestSuite))
+}
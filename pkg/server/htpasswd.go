@@ -0,0 +1,118 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Authenticate binds the client to Account and Permissions when its
+// password matches its hash in the file, returning ErrInvalidCredentials
+// otherwise, or an error when the file cannot be read.
+func (a *HtpasswdAuthenticator) Authenticate(
+	_ context.Context,
+	req *AuthRequest,
+) (*AuthResult, error) {
+	users, err := a.load()
+	if err != nil {
+		return nil, err
+	}
+
+	hash, ok := users[req.Username]
+	if !ok ||
+		bcrypt.CompareHashAndPassword(hash, []byte(req.Password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &AuthResult{
+		Account:     a.Account,
+		Permissions: a.Permissions,
+	}, nil
+}
+
+// load returns the users of the file, reading it again when its size or
+// modification time changed since it was last read.
+func (a *HtpasswdAuthenticator) load() (map[string][]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	info, err := os.Stat(a.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading htpasswd file: %w", err)
+	}
+	if a.users != nil && info.Size() == a.size &&
+		info.ModTime().Equal(a.modTime) {
+		return a.users, nil
+	}
+
+	data, err := os.ReadFile(a.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading htpasswd file: %w", err)
+	}
+
+	users, err := parseHtpasswd(data)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error reading htpasswd file %s: %w",
+			a.Path,
+			err,
+		)
+	}
+
+	a.users, a.size, a.modTime = users, info.Size(), info.ModTime()
+
+	return users, nil
+}
+
+// parseHtpasswd returns the password hashes of an htpasswd file, keyed by
+// user. Blank lines and lines starting with "#" are skipped.
+func parseHtpasswd(
+	data []byte,
+) (map[string][]byte, error) {
+	users := make(map[string][]byte)
+	for i, text := range strings.Split(string(data), "\n") {
+		line := i + 1
+		text = strings.TrimSpace(text)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		user, hash, ok := strings.Cut(text, ":")
+		switch {
+		case !ok || user == "":
+			return nil, fmt.Errorf("line %d: expected user:hash", line)
+		case !IsHashedPassword(hash):
+			return nil, fmt.Errorf(
+				"line %d: password of %q not bcrypt hashed",
+				line,
+				user,
+			)
+		}
+		users[user] = []byte(hash)
+	}
+
+	return users, nil
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+type HtpasswdPublicTestSuite struct {
+	suite.Suite
+
+	path  string
+	alice string
+	bob   string
+}
+
+func (s *HtpasswdPublicTestSuite) SetupSuite() {
+	alice, err := server.HashPassword("secret", 4)
+	s.Require().NoError(err)
+	bob, err := server.HashPassword("hunter2", 4)
+	s.Require().NoError(err)
+
+	// Apache's htpasswd writes bcrypt hashes with the $2y$ prefix.
+	s.alice = strings.Replace(alice, "$2a$", "$2y$", 1)
+	s.bob = bob
+}
+
+func (s *HtpasswdPublicTestSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "htpasswd")
+}
+
+func (s *HtpasswdPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *HtpasswdPublicTestSuite) write(
+	lines ...string,
+) {
+	data := strings.Join(lines, "\n") + "\n"
+	s.Require().NoError(os.WriteFile(s.path, []byte(data), 0o600))
+}
+
+func (s *HtpasswdPublicTestSuite) TestAuthenticate() {
+	perms := &natsserver.Permissions{
+		Publish: &natsserver.SubjectPermission{Allow: []string{"app.>"}},
+	}
+
+	tests := []struct {
+		name         string
+		setup        func()
+		req          *server.AuthRequest
+		validateFunc func(result *server.AuthResult, err error)
+	}{
+		{
+			name: "binds user with matching password",
+			setup: func() {
+				s.write(
+					"# users",
+					"",
+					"alice:"+s.alice,
+					"  bob:"+s.bob+"  ",
+				)
+			},
+			req: &server.AuthRequest{Username: "alice", Password: "secret"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Require().NoError(err)
+				s.Equal(&server.AuthResult{
+					Account:     "APP",
+					Permissions: perms,
+				}, result)
+			},
+		},
+		{
+			name:  "binds user on indented line",
+			setup: func() { s.write("alice:"+s.alice, "  bob:"+s.bob+"  ") },
+			req:   &server.AuthRequest{Username: "bob", Password: "hunter2"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Require().NoError(err)
+				s.NotNil(result)
+			},
+		},
+		{
+			name:  "refuses wrong password",
+			setup: func() { s.write("alice:" + s.alice) },
+			req:   &server.AuthRequest{Username: "alice", Password: "guess"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Nil(result)
+				s.ErrorIs(err, server.ErrInvalidCredentials)
+			},
+		},
+		{
+			name:  "refuses unknown user",
+			setup: func() { s.write("alice:" + s.alice) },
+			req:   &server.AuthRequest{Username: "carol", Password: "secret"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Nil(result)
+				s.ErrorIs(err, server.ErrInvalidCredentials)
+			},
+		},
+		{
+			name:  "returns error for missing file",
+			setup: func() {},
+			req:   &server.AuthRequest{Username: "alice", Password: "secret"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Nil(result)
+				s.ErrorIs(err, os.ErrNotExist)
+				s.ErrorContains(err, "error reading htpasswd file")
+			},
+		},
+		{
+			name:  "returns error for unreadable file",
+			setup: func() { s.Require().NoError(os.Mkdir(s.path, 0o700)) },
+			req:   &server.AuthRequest{Username: "alice", Password: "secret"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Nil(result)
+				s.ErrorContains(err, "error reading htpasswd file")
+			},
+		},
+		{
+			name:  "returns error for line without hash",
+			setup: func() { s.write("alice:"+s.alice, "bob") },
+			req:   &server.AuthRequest{Username: "alice", Password: "secret"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Nil(result)
+				s.ErrorContains(err, s.path+": line 2: expected user:hash")
+			},
+		},
+		{
+			name:  "returns error for line without user",
+			setup: func() { s.write(":" + s.alice) },
+			req:   &server.AuthRequest{Username: "alice", Password: "secret"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Nil(result)
+				s.ErrorContains(err, "line 1: expected user:hash")
+			},
+		},
+		{
+			name:  "returns error for password not bcrypt hashed",
+			setup: func() { s.write("alice:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=") },
+			req:   &server.AuthRequest{Username: "alice", Password: "secret"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Nil(result)
+				s.ErrorContains(
+					err,
+					`line 1: password of "alice" not bcrypt hashed`,
+				)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+			authenticator := &server.HtpasswdAuthenticator{
+				Path:        s.path,
+				Account:     "APP",
+				Permissions: perms,
+			}
+
+			tc.validateFunc(
+				authenticator.Authenticate(context.Background(), tc.req),
+			)
+		})
+	}
+}
+
+func (s *HtpasswdPublicTestSuite) TestAuthenticateReadsChanges() {
+	authenticator := &server.HtpasswdAuthenticator{Path: s.path}
+	alice := &server.AuthRequest{Username: "alice", Password: "secret"}
+	bob := &server.AuthRequest{Username: "bob", Password: "hunter2"}
+
+	s.write("alice:" + s.alice)
+	_, err := authenticator.Authenticate(context.Background(), alice)
+	s.Require().NoError(err)
+	_, err = authenticator.Authenticate(context.Background(), bob)
+	s.ErrorIs(err, server.ErrInvalidCredentials)
+
+	s.write("bob:" + s.bob)
+	_, err = authenticator.Authenticate(context.Background(), bob)
+	s.NoError(err)
+	_, err = authenticator.Authenticate(context.Background(), alice)
+	s.ErrorIs(err, server.ErrInvalidCredentials)
+}
+
+func TestHtpasswdPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(HtpasswdPublicTestSuite))
+}
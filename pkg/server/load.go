@@ -0,0 +1,256 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/conf"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+)
+
+// Error reports the position of the problem and what it is, in the format
+// the NATS server uses for its own configuration errors.
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Reason)
+}
+
+// LoadOptionsFile loads options from the NATS server configuration file at
+// path, following its includes and resolving its variables. Settings of
+// this package are read from an optional block named "embedded":
+//
+//	embedded {
+//	  ready_timeout: "10s"
+//	  drain_on_stop: true
+//	  allow_restart: true
+//	}
+//
+// Problems are reported together, each with its file and line.
+func LoadOptionsFile(
+	path string,
+) (*Options, error) {
+	natsOpts := &natsserver.Options{}
+	processErr := natsOpts.ProcessConfigFile(path)
+
+	// Parsed again for the embedded block, which the NATS server does not
+	// hand back. Any syntax error was reported by the first parse.
+	m, _ := conf.ParseFileWithChecks(path)
+
+	return loadOptions(natsOpts, processErr, m)
+}
+
+// LoadOptions loads options from NATS server configuration read from r, as
+// LoadOptionsFile does. Includes are relative to the working directory.
+func LoadOptions(
+	r io.Reader,
+) (*Options, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error loading options: %w", err)
+	}
+
+	natsOpts := &natsserver.Options{}
+	processErr := natsOpts.ProcessConfigString(string(data))
+	m, _ := conf.ParseWithChecks(string(data))
+
+	return loadOptions(natsOpts, processErr, m)
+}
+
+// loadOptions builds Options from natsOpts, processed from the parsed
+// configuration m with processErr as the result, and the embedded block
+// in m.
+func loadOptions(
+	natsOpts *natsserver.Options,
+	processErr error,
+	m map[string]any,
+) (*Options, error) {
+	block, _ := m[configBlock].(configToken)
+	errs, err := configErrors(processErr, block)
+	if err != nil {
+		return nil, fmt.Errorf("error loading options: %w", err)
+	}
+
+	opts := &Options{
+		Options:      natsOpts,
+		ReadyTimeout: DefaultReadyTimeout,
+	}
+	if block != nil {
+		errs = append(errs, opts.applyConfigBlock(block)...)
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("error loading options: %w", errors.Join(errs...))
+	}
+
+	return opts, nil
+}
+
+// configErrors returns the errors the NATS server reported processing a
+// configuration, leaving out warnings and its complaint about the embedded
+// block, which it does not know. Only the complaint at the position of
+// block, the top-level embedded block, is left out, so a field named
+// "embedded" elsewhere is still reported. An error other than the
+// processing errors, such as a syntax error, is returned as err.
+func configErrors(
+	processErr error,
+	block configToken,
+) ([]error, error) {
+	if processErr == nil {
+		return nil, nil
+	}
+
+	var reported interface{ Errors() []error }
+	if !errors.As(processErr, &reported) {
+		return nil, processErr
+	}
+
+	var unknownBlock string
+	if block != nil {
+		unknownBlock = newConfigError(
+			block,
+			fmt.Sprintf("unknown field %q", configBlock),
+		).Error()
+	}
+
+	var errs []error
+	for _, err := range reported.Errors() {
+		if err.Error() != unknownBlock {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs, nil
+}
+
+// applyConfigBlock applies the settings in the embedded block.
+func (o *Options) applyConfigBlock(
+	block configToken,
+) []error {
+	settings, ok := block.Value().(map[string]any)
+	if !ok {
+		return []error{newConfigError(block, configBlock+" must be a block")}
+	}
+
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(settings)) {
+		err := o.applyConfigSetting(key, settings[key].(configToken))
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// applyConfigSetting applies the setting key, with its value in tk.
+func (o *Options) applyConfigSetting(
+	key string,
+	tk configToken,
+) error {
+	var err error
+	switch strings.ToLower(key) {
+	case "ready_timeout":
+		o.ReadyTimeout, err = configDuration(tk)
+	case "drain_on_stop":
+		o.DrainOnStop, err = configBool(tk)
+	case "allow_restart":
+		o.AllowRestart, err = configBool(tk)
+	default:
+		return errors.Join(unknownConfigFields(configBlock+"."+key, tk)...)
+	}
+
+	if err != nil {
+		return newConfigError(tk, fmt.Sprintf("invalid %s: %v", key, err))
+	}
+
+	return nil
+}
+
+// unknownConfigFields returns an error for the unknown field at path, with
+// its value in tk, or for each field within it when it is a block, so a
+// misplaced block such as "embedded.jetstream" names the fields it holds.
+func unknownConfigFields(
+	path string,
+	tk configToken,
+) []error {
+	fields, ok := tk.Value().(map[string]any)
+	if !ok || len(fields) == 0 {
+		return []error{
+			newConfigError(tk, fmt.Sprintf("unknown field %q", path)),
+		}
+	}
+
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		errs = append(
+			errs,
+			unknownConfigFields(path+"."+key, fields[key].(configToken))...,
+		)
+	}
+
+	return errs
+}
+
+// configDuration returns tk's value as a duration, given as a string such
+// as "10s" or, as the NATS server allows, a number of seconds.
+func configDuration(
+	tk configToken,
+) (time.Duration, error) {
+	switch v := tk.Value().(type) {
+	case string:
+		return time.ParseDuration(v)
+	case int64:
+		return time.Duration(v) * time.Second, nil
+	default:
+		return 0, fmt.Errorf("expected a duration, got %T", v)
+	}
+}
+
+// configBool returns tk's value as a boolean.
+func configBool(
+	tk configToken,
+) (bool, error) {
+	v, ok := tk.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expected a boolean, got %T", tk.Value())
+	}
+
+	return v, nil
+}
+
+// newConfigError returns a *ConfigError for reason at tk's position.
+func newConfigError(
+	tk configToken,
+	reason string,
+) *ConfigError {
+	return &ConfigError{
+		File:   tk.SourceFile(),
+		Line:   tk.Line(),
+		Column: tk.Position(),
+		Reason: reason,
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+type LoadPublicTestSuite struct {
+	suite.Suite
+
+	dir string
+}
+
+func (s *LoadPublicTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+}
+
+func (s *LoadPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+// write writes content to the file name in the test directory, returning
+// its path.
+func (s *LoadPublicTestSuite) write(
+	name string,
+	content string,
+) string {
+	path := filepath.Join(s.dir, name)
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func (s *LoadPublicTestSuite) TestLoadOptionsFile() {
+	tests := []struct {
+		name         string
+		setup        func() string
+		validateFunc func(opts *server.Options, err error)
+	}{
+		{
+			name: "loads settings with includes variables and embedded block",
+			setup: func() string {
+				s.write("auth.conf", "authorization {\n  token: s3cr3t\n}\n")
+
+				return s.write("nats.conf", strings.Join([]string{
+					"PORT = 4333",
+					"server_name: embedded",
+					"host: 127.0.0.1",
+					"port: $PORT",
+					"include auth.conf",
+					"embedded {",
+					`  ready_timeout: "3s"`,
+					"  drain_on_stop: true",
+					"  allow_restart: true",
+					"}",
+				}, "\n"))
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Require().NoError(err)
+				s.Equal("embedded", opts.ServerName)
+				s.Equal("127.0.0.1", opts.Host)
+				s.Equal(4333, opts.Port)
+				s.Equal("s3cr3t", opts.Authorization)
+				s.Equal(3*time.Second, opts.ReadyTimeout)
+				s.True(opts.DrainOnStop)
+				s.True(opts.AllowRestart)
+			},
+		},
+		{
+			name: "uses default ready timeout without embedded block",
+			setup: func() string {
+				return s.write("nats.conf", "port: 4333\n")
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Require().NoError(err)
+				s.Equal(server.DefaultReadyTimeout, opts.ReadyTimeout)
+				s.False(opts.DrainOnStop)
+			},
+		},
+		{
+			name: "reads ready timeout given in seconds",
+			setup: func() string {
+				return s.write("nats.conf", "embedded {\n  ready_timeout: 7\n}\n")
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Require().NoError(err)
+				s.Equal(7*time.Second, opts.ReadyTimeout)
+			},
+		},
+		{
+			name: "ignores warnings",
+			setup: func() string {
+				return s.write("nats.conf", "")
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Require().NoError(err)
+				s.NotNil(opts.Options)
+			},
+		},
+		{
+			name: "returns syntax error",
+			setup: func() string {
+				return s.write("nats.conf", "port: 4222\nfoo {\n  bar:\n")
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Nil(opts)
+				s.ErrorContains(err, "error loading options: Parse error on line")
+			},
+		},
+		{
+			name: "returns error when file is missing",
+			setup: func() string {
+				return filepath.Join(s.dir, "missing.conf")
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Nil(opts)
+				s.ErrorIs(err, os.ErrNotExist)
+			},
+		},
+		{
+			name: "returns nats server errors with positions",
+			setup: func() string {
+				return s.write("nats.conf", "port: 4222\nserver_name: \"a b\"\n")
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Nil(opts)
+				s.ErrorContains(err, filepath.Join(s.dir, "nats.conf")+":2:")
+				s.ErrorContains(err, "server name cannot contain spaces")
+			},
+		},
+		{
+			name: "returns every embedded block error with positions",
+			setup: func() string {
+				return s.write("nats.conf", strings.Join([]string{
+					"embedded {",
+					`  ready_timeout: "soon"`,
+					`  drain_on_stop: "yes"`,
+					"  allow_restart: 1.5",
+					"  colour: blue",
+					"}",
+				}, "\n"))
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Nil(opts)
+
+				var configErr *server.ConfigError
+				s.Require().ErrorAs(err, &configErr)
+				s.Equal(filepath.Join(s.dir, "nats.conf"), configErr.File)
+
+				s.ErrorContains(err, "nats.conf:4:3: invalid allow_restart: "+
+					"expected a boolean, got float64")
+				s.ErrorContains(err, "nats.conf:5:3: unknown field "+
+					"\"embedded.colour\"")
+				s.ErrorContains(err, "nats.conf:3:3: invalid drain_on_stop: "+
+					"expected a boolean, got string")
+				s.ErrorContains(err, "nats.conf:2:3: invalid ready_timeout: "+
+					"time: invalid duration \"soon\"")
+			},
+		},
+		{
+			name: "returns unknown fields nested in embedded block",
+			setup: func() string {
+				return s.write("nats.conf", strings.Join([]string{
+					"embedded {",
+					"  jetstream {",
+					`    stor_dir: "/data"`,
+					"    max_mem: 1G",
+					"  }",
+					"}",
+				}, "\n"))
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Nil(opts)
+				s.ErrorContains(err, "nats.conf:4:5: unknown field "+
+					"\"embedded.jetstream.max_mem\"")
+				s.ErrorContains(err, "nats.conf:3:5: unknown field "+
+					"\"embedded.jetstream.stor_dir\"")
+			},
+		},
+		{
+			name: "returns unknown embedded fields outside embedded block",
+			setup: func() string {
+				return s.write("nats.conf", strings.Join([]string{
+					"jetstream {",
+					"  embedded: true",
+					"}",
+					"embedded {",
+					"  drain_on_stop: true",
+					"}",
+				}, "\n"))
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Nil(opts)
+				s.ErrorContains(err, "nats.conf:2:3: unknown field \"embedded\"")
+				s.NotContains(err.Error(), ":4:")
+			},
+		},
+		{
+			name: "rejects ready timeout of the wrong type",
+			setup: func() string {
+				return s.write("nats.conf", "embedded {\n  ready_timeout: true\n}\n")
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Nil(opts)
+				s.ErrorContains(err, "expected a duration, got bool")
+			},
+		},
+		{
+			name: "rejects embedded setting that is not a block",
+			setup: func() string {
+				return s.write("nats.conf", "embedded: 5\n")
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Nil(opts)
+				s.ErrorContains(err, "nats.conf:1:0: embedded must be a block")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(server.LoadOptionsFile(tc.setup()))
+		})
+	}
+}
+
+func (s *LoadPublicTestSuite) TestLoadOptions() {
+	tests := []struct {
+		name         string
+		reader       func() *strings.Reader
+		readErr      error
+		validateFunc func(opts *server.Options, err error)
+	}{
+		{
+			name: "loads settings and embedded block",
+			reader: func() *strings.Reader {
+				return strings.NewReader(
+					"port: 4333\nembedded {\n  ready_timeout: \"2s\"\n}\n",
+				)
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Require().NoError(err)
+				s.Equal(4333, opts.Port)
+				s.Equal(2*time.Second, opts.ReadyTimeout)
+			},
+		},
+		{
+			name: "returns errors",
+			reader: func() *strings.Reader {
+				return strings.NewReader("embedded {\n  colour: blue\n}\n")
+			},
+			validateFunc: func(opts *server.Options, err error) {
+				s.Nil(opts)
+				s.EqualError(
+					err,
+					`error loading options: :2:3: `+
+						`unknown field "embedded.colour"`,
+				)
+			},
+		},
+		{
+			name:    "returns error when reading fails",
+			readErr: errors.New("read failed"),
+			validateFunc: func(opts *server.Options, err error) {
+				s.Nil(opts)
+				s.EqualError(err, "error loading options: read failed")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			if tc.readErr != nil {
+				tc.validateFunc(server.LoadOptions(iotest.ErrReader(tc.readErr)))
+				return
+			}
+
+			tc.validateFunc(server.LoadOptions(tc.reader()))
+		})
+	}
+}
+
+func (s *LoadPublicTestSuite) TestError() {
+	err := &server.ConfigError{
+		File:   "nats.conf",
+		Line:   3,
+		Column: 2,
+		Reason: `unknown field "colour"`,
+	}
+
+	s.EqualError(err, `nats.conf:3:2: unknown field "colour"`)
+}
+
+func TestLoadPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(LoadPublicTestSuite))
+}
@@ -23,6 +23,7 @@ package server
 import (
 	"fmt"
 	"log/slog"
+	"sync"
 )
 
 // SlogWrapper wraps an slog.Logger to implement the NATS Logger interface.
@@ -33,6 +34,10 @@ import (
 // formatting needs and slog's structured logging.
 type SlogWrapper struct {
 	logger *slog.Logger
+
+	// mu guards lastFatal, the most recent message logged with Fatalf.
+	mu        sync.Mutex
+	lastFatal string
 }
 
 // Noticef logs a formatted notice message.
@@ -56,7 +61,13 @@ func (l *SlogWrapper) Fatalf(
 	format string,
 	v ...interface{},
 ) {
-	l.logger.Error(fmt.Sprintf(format, v...))
+	msg := fmt.Sprintf(format, v...)
+
+	l.mu.Lock()
+	l.lastFatal = msg
+	l.mu.Unlock()
+
+	l.logger.Error(msg)
 }
 
 // Errorf logs a formatted error message.
@@ -82,3 +93,12 @@ func (l *SlogWrapper) Tracef(
 ) {
 	l.logger.Debug(fmt.Sprintf(format, v...)) // Map Trace to Debug in slog
 }
+
+// lastFatalMessage returns the most recent message logged with Fatalf, or an
+// empty string when there has been none.
+func (l *SlogWrapper) lastFatalMessage() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.lastFatal
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// PlanJetStreamMigrations returns the migrations of
+// Options.JetStreamAssets the running server's store has applied and
+// those it has yet to apply, applying none. A server that is not running
+// returns ErrNotRunning, and one that has not run with JetStreamAssets
+// ErrNoJetStreamAssets.
+func (s *Server) PlanJetStreamMigrations(
+	ctx context.Context,
+) (*JetStreamMigrationPlan, error) {
+	// Reload configures how the run connects.
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	natsServer, r, err := s.runningJetStream()
+	if err != nil {
+		return nil, fmt.Errorf("error planning jetstream migrations: %w", err)
+	}
+
+	conn, err := r.connect(natsServer)
+	if err != nil {
+		return nil, fmt.Errorf("error planning jetstream migrations: %w", err)
+	}
+	defer conn.Close()
+
+	// Creating a JetStream context fails only for its options.
+	js, _ := jetstream.New(conn)
+
+	plan, err := planMigrations(ctx, js, s.options().JetStreamAssets)
+	if err != nil {
+		return nil, fmt.Errorf("error planning jetstream migrations: %w", err)
+	}
+
+	return plan, nil
+}
+
+// migrate applies the pending migrations of assets through js, recording
+// each in the migrations bucket, and reports whether none is left
+// pending. With DryRunMigrations, the pending migrations are logged
+// instead. A store ahead of the migrations is refused with ErrStoreAhead,
+// and a migration recorded since it was planned with ErrMigrationRecorded.
+func (r *jetStreamRun) migrate(
+	ctx context.Context,
+	js jetstream.JetStream,
+	assets *JetStreamAssets,
+) (bool, error) {
+	plan, err := planMigrations(ctx, js, assets)
+	if err != nil {
+		return false, fmt.Errorf("error planning jetstream migrations: %w", err)
+	}
+
+	if plan.Version > plan.Target {
+		return false, fmt.Errorf(
+			"store at version %d, migrations at %d: %w",
+			plan.Version,
+			plan.Target,
+			ErrStoreAhead,
+		)
+	}
+
+	if len(plan.Pending) == 0 {
+		return true, nil
+	}
+
+	if assets.DryRunMigrations {
+		for _, m := range plan.Pending {
+			r.server.logger.Info(
+				"jetstream migration pending",
+				"version", m.Version,
+				"description", m.Description,
+			)
+		}
+
+		return false, nil
+	}
+
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      assets.migrationsBucket(),
+		Description: "JetStream migrations applied",
+	})
+	if err != nil {
+		return false, fmt.Errorf("error creating migrations bucket: %w", err)
+	}
+
+	for _, m := range plan.Pending {
+		if err := m.Apply(ctx, js); err != nil {
+			return false, fmt.Errorf(
+				"error applying jetstream migration %d: %w",
+				m.Version,
+				err,
+			)
+		}
+
+		record, _ := json.Marshal(JetStreamMigrationRecord{
+			Version:     m.Version,
+			Description: m.Description,
+			AppliedAt:   time.Now().UTC(),
+		})
+		_, err := kv.Create(ctx, migrationKey(m.Version), record)
+		if errors.Is(err, jetstream.ErrKeyExists) {
+			err = ErrMigrationRecorded
+		}
+		if err != nil {
+			return false, fmt.Errorf(
+				"error recording jetstream migration %d: %w",
+				m.Version,
+				err,
+			)
+		}
+
+		r.server.logger.Info(
+			"jetstream migration applied",
+			"version", m.Version,
+			"description", m.Description,
+		)
+	}
+
+	return true, nil
+}
+
+// planMigrations returns the plan of the migrations of assets, reading
+// the migrations applied from the migrations bucket through js. A store
+// without the bucket has applied none. A migration not applied below the
+// version of the store is refused with ErrMigrationOutOfOrder.
+func planMigrations(
+	ctx context.Context,
+	js jetstream.JetStream,
+	assets *JetStreamAssets,
+) (*JetStreamMigrationPlan, error) {
+	applied, err := appliedMigrations(ctx, js, assets.migrationsBucket())
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &JetStreamMigrationPlan{Applied: applied}
+	done := make(map[uint64]bool, len(applied))
+	for _, record := range applied {
+		done[record.Version] = true
+		plan.Version = max(plan.Version, record.Version)
+	}
+
+	migrations := slices.SortedFunc(
+		slices.Values(assets.Migrations),
+		func(a, b JetStreamMigration) int {
+			return cmp.Compare(a.Version, b.Version)
+		},
+	)
+	for _, m := range migrations {
+		plan.Target = max(plan.Target, m.Version)
+		if done[m.Version] {
+			continue
+		}
+		if m.Version < plan.Version {
+			return nil, fmt.Errorf(
+				"migration %d below store version %d: %w",
+				m.Version,
+				plan.Version,
+				ErrMigrationOutOfOrder,
+			)
+		}
+		plan.Pending = append(plan.Pending, m)
+	}
+
+	return plan, nil
+}
+
+// appliedMigrations returns the migrations recorded in bucket, in order
+// of version.
+func appliedMigrations(
+	ctx context.Context,
+	js jetstream.JetStream,
+	bucket string,
+) ([]JetStreamMigrationRecord, error) {
+	kv, err := js.KeyValue(ctx, bucket)
+	if errors.Is(err, jetstream.ErrBucketNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations bucket: %w", err)
+	}
+
+	keys, err := kv.ListKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations bucket: %w", err)
+	}
+
+	var records []JetStreamMigrationRecord
+	for key := range keys.Keys() {
+		entry, err := kv.Get(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %w", key, err)
+		}
+
+		var record JetStreamMigrationRecord
+		if err := json.Unmarshal(entry.Value(), &record); err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %w", key, err)
+		}
+		records = append(records, record)
+	}
+
+	slices.SortFunc(records, func(a, b JetStreamMigrationRecord) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return records, nil
+}
+
+// migrationsBucket returns the key-value bucket recording the migrations
+// applied.
+func (a *JetStreamAssets) migrationsBucket() string {
+	if a.MigrationsBucket != "" {
+		return a.MigrationsBucket
+	}
+
+	return DefaultMigrationsBucket
+}
+
+// migrationKey returns the key recording the migration version.
+func migrationKey(
+	version uint64,
+) string {
+	return strconv.FormatUint(version, 10)
+}
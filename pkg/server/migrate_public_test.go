@@ -0,0 +1,443 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+type MigratePublicTestSuite struct {
+	suite.Suite
+
+	srv  *server.Server
+	logs *syncBuffer
+	// applied are the versions of the migrations applied, in order.
+	applied []uint64
+}
+
+func (s *MigratePublicTestSuite) SetupTest() {
+	s.logs = &syncBuffer{}
+	s.applied = nil
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(s.logs, nil)),
+		&server.Options{
+			Options: &natsserver.Options{
+				Host:      "127.0.0.1",
+				Port:      freePort(s.T()),
+				NoSigs:    true,
+				JetStream: true,
+				StoreDir:  s.T().TempDir(),
+			},
+			ReadyTimeout: 5 * time.Second,
+			JetStreamAssets: &server.JetStreamAssets{
+				Streams: []jetstream.StreamConfig{{
+					Name:     "ORDERS",
+					Subjects: []string{"orders.>", "refunds.>"},
+				}},
+				Migrations: []server.JetStreamMigration{
+					// Out of order, to be applied in order.
+					s.addSubject(2, "refunds.>"),
+					{
+						Version:     1,
+						Description: "create orders stream",
+						Apply: func(
+							ctx context.Context,
+							js jetstream.JetStream,
+						) error {
+							s.applied = append(s.applied, 1)
+							_, err := js.CreateOrUpdateStream(
+								ctx,
+								jetstream.StreamConfig{
+									Name:     "ORDERS",
+									Subjects: []string{"orders.>"},
+								},
+							)
+
+							return err
+						},
+					},
+				},
+			},
+		},
+	)
+}
+
+func (s *MigratePublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *MigratePublicTestSuite) TearDownSubTest() {
+	s.srv.Stop()
+}
+
+func (s *MigratePublicTestSuite) TearDownTest() {
+	s.srv.Stop()
+}
+
+func (s *MigratePublicTestSuite) TestStart() {
+	tests := []struct {
+		name         string
+		setup        func(assets *server.JetStreamAssets)
+		validateFunc func(err error)
+	}{
+		{
+			name:  "applies migrations in order and records them",
+			setup: func(*server.JetStreamAssets) {},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Equal([]uint64{1, 2}, s.applied)
+
+				plan := s.plan()
+				s.EqualValues(2, plan.Version)
+				s.EqualValues(2, plan.Target)
+				s.Empty(plan.Pending)
+				s.Require().Len(plan.Applied, 2)
+				s.Equal("create orders stream", plan.Applied[0].Description)
+				s.EqualValues(2, plan.Applied[1].Version)
+				s.False(plan.Applied[1].AppliedAt.IsZero())
+
+				kv, err := s.jetStream().KeyValue(
+					context.Background(),
+					server.DefaultMigrationsBucket,
+				)
+				s.Require().NoError(err)
+				_, err = kv.Get(context.Background(), "2")
+				s.Require().NoError(err)
+				s.True(s.logs.wait("jetstream migration applied"))
+			},
+		},
+		{
+			name: "applies only new migrations on restart",
+			setup: func(assets *server.JetStreamAssets) {
+				assets.Migrations = assets.Migrations[1:]
+				assets.Streams[0].Subjects = []string{"orders.>"}
+				s.Require().NoError(s.srv.Start())
+				s.srv.Stop()
+
+				assets.Migrations = append(
+					assets.Migrations,
+					s.addSubject(2, "refunds.>"),
+					s.addSubject(3, "returns.>"),
+				)
+				assets.Streams[0].Subjects = []string{
+					"orders.>",
+					"refunds.>",
+					"returns.>",
+				}
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Equal([]uint64{1, 2, 3}, s.applied)
+				s.EqualValues(3, s.plan().Version)
+			},
+		},
+		{
+			name: "records migrations in named bucket",
+			setup: func(assets *server.JetStreamAssets) {
+				assets.MigrationsBucket = "schema"
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				_, err = s.jetStream().KeyValue(context.Background(), "schema")
+				s.Require().NoError(err)
+			},
+		},
+		{
+			name: "logs plan without applying in dry run",
+			setup: func(assets *server.JetStreamAssets) {
+				assets.DryRunMigrations = true
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Empty(s.applied)
+				s.True(s.logs.wait("jetstream migration pending"))
+
+				plan := s.plan()
+				s.Zero(plan.Version)
+				s.EqualValues(2, plan.Target)
+				s.Require().Len(plan.Pending, 2)
+				s.EqualValues(1, plan.Pending[0].Version)
+				s.EqualValues(2, plan.Pending[1].Version)
+
+				// Assets wait for the migrations.
+				_, err = s.jetStream().Stream(context.Background(), "ORDERS")
+				s.ErrorIs(err, jetstream.ErrStreamNotFound)
+			},
+		},
+		{
+			name: "refuses store ahead of migrations",
+			setup: func(assets *server.JetStreamAssets) {
+				s.Require().NoError(s.srv.Start())
+				s.srv.Stop()
+
+				assets.Migrations = assets.Migrations[1:]
+				assets.Streams[0].Subjects = []string{"orders.>"}
+				s.applied = nil
+			},
+			validateFunc: func(err error) {
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhaseProvision, startErr.Phase)
+				s.ErrorIs(err, server.ErrStoreAhead)
+				s.ErrorContains(err, "store at version 2, migrations at 1")
+				s.Empty(s.applied)
+			},
+		},
+		{
+			name: "refuses migration below store version",
+			setup: func(assets *server.JetStreamAssets) {
+				assets.Migrations = []server.JetStreamMigration{
+					assets.Migrations[1],
+					s.addSubject(3, "returns.>"),
+				}
+				assets.Streams[0].Subjects = []string{"orders.>", "returns.>"}
+				s.Require().NoError(s.srv.Start())
+				s.srv.Stop()
+
+				assets.Migrations = append(
+					assets.Migrations,
+					s.addSubject(2, "refunds.>"),
+				)
+				s.applied = nil
+			},
+			validateFunc: func(err error) {
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhaseProvision, startErr.Phase)
+				s.ErrorIs(err, server.ErrMigrationOutOfOrder)
+				s.ErrorContains(err, "migration 2 below store version 3")
+				s.Empty(s.applied)
+			},
+		},
+		{
+			name: "fails start on migration recorded concurrently",
+			setup: func(assets *server.JetStreamAssets) {
+				assets.Migrations[0].Apply = func(
+					ctx context.Context,
+					js jetstream.JetStream,
+				) error {
+					// Another server applies and records it meanwhile.
+					kv, err := js.KeyValue(ctx, server.DefaultMigrationsBucket)
+					if err != nil {
+						return err
+					}
+					_, err = kv.Create(ctx, "2", []byte("{}"))
+
+					return err
+				}
+			},
+			validateFunc: func(err error) {
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhaseProvision, startErr.Phase)
+				s.ErrorIs(err, server.ErrMigrationRecorded)
+				s.ErrorContains(err, "error recording jetstream migration 2")
+			},
+		},
+		{
+			name: "fails start on failed migration",
+			setup: func(assets *server.JetStreamAssets) {
+				assets.Migrations[0].Apply = func(
+					context.Context,
+					jetstream.JetStream,
+				) error {
+					return errors.New("boom")
+				}
+			},
+			validateFunc: func(err error) {
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhaseProvision, startErr.Phase)
+				s.ErrorContains(err, "error applying jetstream migration 2: boom")
+				s.Equal([]uint64{1}, s.applied)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup(s.srv.Opts.JetStreamAssets)
+
+			tc.validateFunc(s.srv.Start())
+		})
+	}
+}
+
+func (s *MigratePublicTestSuite) TestPlanJetStreamMigrations() {
+	tests := []struct {
+		name         string
+		setup        func()
+		validateFunc func(plan *server.JetStreamMigrationPlan, err error)
+	}{
+		{
+			name: "plans reloaded migrations",
+			setup: func() {
+				s.Require().NoError(s.srv.Start())
+				newOpts := *s.srv.Opts
+				newOpts.Options = s.srv.Opts.Clone()
+				assets := *s.srv.Opts.JetStreamAssets
+				assets.Migrations = append(
+					assets.Migrations,
+					s.addSubject(3, "returns.>"),
+				)
+				assets.DryRunMigrations = true
+				newOpts.JetStreamAssets = &assets
+				s.Require().NoError(s.srv.Reload(&newOpts))
+			},
+			validateFunc: func(
+				plan *server.JetStreamMigrationPlan,
+				err error,
+			) {
+				s.Require().NoError(err)
+				s.EqualValues(2, plan.Version)
+				s.EqualValues(3, plan.Target)
+				s.Require().Len(plan.Pending, 1)
+				s.EqualValues(3, plan.Pending[0].Version)
+
+				// A dry run leaves them pending when reconciling.
+				_, err = s.srv.ReconcileJetStream(context.Background())
+				s.Require().NoError(err)
+				s.Equal([]uint64{1, 2}, s.applied)
+			},
+		},
+		{
+			name:  "returns not running",
+			setup: func() {},
+			validateFunc: func(
+				plan *server.JetStreamMigrationPlan,
+				err error,
+			) {
+				s.ErrorIs(err, server.ErrNotRunning)
+				s.Nil(plan)
+			},
+		},
+		{
+			name: "returns no assets",
+			setup: func() {
+				s.srv.Opts.JetStreamAssets = nil
+				s.Require().NoError(s.srv.Start())
+			},
+			validateFunc: func(
+				plan *server.JetStreamMigrationPlan,
+				err error,
+			) {
+				s.ErrorIs(err, server.ErrNoJetStreamAssets)
+				s.Nil(plan)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+
+			tc.validateFunc(
+				s.srv.PlanJetStreamMigrations(context.Background()),
+			)
+		})
+	}
+}
+
+func (s *MigratePublicTestSuite) TestReconcileJetStream() {
+	s.srv.Opts.JetStreamAssets.DryRunMigrations = true
+	s.Require().NoError(s.srv.Start())
+	s.Empty(s.applied)
+
+	newOpts := *s.srv.Opts
+	newOpts.Options = s.srv.Opts.Clone()
+	assets := *s.srv.Opts.JetStreamAssets
+	assets.DryRunMigrations = false
+	newOpts.JetStreamAssets = &assets
+	s.Require().NoError(s.srv.Reload(&newOpts))
+
+	drift, err := s.srv.ReconcileJetStream(context.Background())
+	s.Require().NoError(err)
+	s.Empty(drift)
+	s.Equal([]uint64{1, 2}, s.applied)
+	stream, err := s.jetStream().Stream(context.Background(), "ORDERS")
+	s.Require().NoError(err)
+	s.Equal(
+		[]string{"orders.>", "refunds.>"},
+		stream.CachedInfo().Config.Subjects,
+	)
+}
+
+// addSubject returns the migration version adding subject to the
+// ORDERS stream.
+func (s *MigratePublicTestSuite) addSubject(
+	version uint64,
+	subject string,
+) server.JetStreamMigration {
+	return server.JetStreamMigration{
+		Version:     version,
+		Description: fmt.Sprintf("add %s to orders stream", subject),
+		Apply: func(
+			ctx context.Context,
+			js jetstream.JetStream,
+		) error {
+			s.applied = append(s.applied, version)
+			stream, err := js.Stream(ctx, "ORDERS")
+			if err != nil {
+				return err
+			}
+			cfg := stream.CachedInfo().Config
+			cfg.Subjects = append(cfg.Subjects, subject)
+			_, err = js.UpdateStream(ctx, cfg)
+
+			return err
+		},
+	}
+}
+
+// plan returns the migration plan of the running server.
+func (s *MigratePublicTestSuite) plan() *server.JetStreamMigrationPlan {
+	plan, err := s.srv.PlanJetStreamMigrations(context.Background())
+	s.Require().NoError(err)
+
+	return plan
+}
+
+// jetStream connects to the server, returning its JetStream context.
+func (s *MigratePublicTestSuite) jetStream() jetstream.JetStream {
+	nc, err := nats.Connect(fmt.Sprintf("nats://127.0.0.1:%d", s.srv.Opts.Port))
+	s.Require().NoError(err)
+	s.T().Cleanup(nc.Close)
+	js, err := jetstream.New(nc)
+	s.Require().NoError(err)
+
+	return js
+}
+
+func TestMigratePublicTestSuite(t *testing.T) {
+	suite.Run(t, new(MigratePublicTestSuite))
+}
@@ -22,3 +22,4 @@
 package mocks
 
 //go:generate go tool go.uber.org/mock/mockgen -destination=./nats_server_instance.gen.go -package=mocks github.com/osapi-io/nats-server/pkg/server NATSServerInstance
+//go:generate go tool go.uber.org/mock/mockgen -destination=./authentication.gen.go -package=mocks github.com/nats-io/nats-server/v2/server Authentication,ClientAuthentication
@@ -10,6 +10,7 @@
 package mocks
 
 import (
+	net "net"
 	reflect "reflect"
 	time "time"
 
@@ -41,6 +42,62 @@ func (m *MockNATSServerInstance) EXPECT() *MockNATSServerInstanceMockRecorder {
 	return m.recorder
 }
 
+// InProcessConn mocks base method.
+func (m *MockNATSServerInstance) InProcessConn() (net.Conn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InProcessConn")
+	ret0, _ := ret[0].(net.Conn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InProcessConn indicates an expected call of InProcessConn.
+func (mr *MockNATSServerInstanceMockRecorder) InProcessConn() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InProcessConn", reflect.TypeOf((*MockNATSServerInstance)(nil).InProcessConn))
+}
+
+// LameDuckShutdown mocks base method.
+func (m *MockNATSServerInstance) LameDuckShutdown() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "LameDuckShutdown")
+}
+
+// LameDuckShutdown indicates an expected call of LameDuckShutdown.
+func (mr *MockNATSServerInstanceMockRecorder) LameDuckShutdown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LameDuckShutdown", reflect.TypeOf((*MockNATSServerInstance)(nil).LameDuckShutdown))
+}
+
+// LookupAccount mocks base method.
+func (m *MockNATSServerInstance) LookupAccount(name string) (*server.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupAccount", name)
+	ret0, _ := ret[0].(*server.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupAccount indicates an expected call of LookupAccount.
+func (mr *MockNATSServerInstanceMockRecorder) LookupAccount(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupAccount", reflect.TypeOf((*MockNATSServerInstance)(nil).LookupAccount), name)
+}
+
+// NumClients mocks base method.
+func (m *MockNATSServerInstance) NumClients() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NumClients")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// NumClients indicates an expected call of NumClients.
+func (mr *MockNATSServerInstanceMockRecorder) NumClients() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NumClients", reflect.TypeOf((*MockNATSServerInstance)(nil).NumClients))
+}
+
 // ReadyForConnections mocks base method.
 func (m *MockNATSServerInstance) ReadyForConnections(timeout time.Duration) bool {
 	m.ctrl.T.Helper()
@@ -55,6 +112,20 @@ func (mr *MockNATSServerInstanceMockRecorder) ReadyForConnections(timeout any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadyForConnections", reflect.TypeOf((*MockNATSServerInstance)(nil).ReadyForConnections), timeout)
 }
 
+// ReloadOptions mocks base method.
+func (m *MockNATSServerInstance) ReloadOptions(opts *server.Options) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReloadOptions", opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReloadOptions indicates an expected call of ReloadOptions.
+func (mr *MockNATSServerInstanceMockRecorder) ReloadOptions(opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReloadOptions", reflect.TypeOf((*MockNATSServerInstance)(nil).ReloadOptions), opts)
+}
+
 // SetLogger mocks base method.
 func (m *MockNATSServerInstance) SetLogger(logger server.Logger, debug, trace bool) {
 	m.ctrl.T.Helper()
@@ -90,3 +161,15 @@ func (mr *MockNATSServerInstanceMockRecorder) Start() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockNATSServerInstance)(nil).Start))
 }
+
+// WaitForShutdown mocks base method.
+func (m *MockNATSServerInstance) WaitForShutdown() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "WaitForShutdown")
+}
+
+// WaitForShutdown indicates an expected call of WaitForShutdown.
+func (mr *MockNATSServerInstanceMockRecorder) WaitForShutdown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForShutdown", reflect.TypeOf((*MockNATSServerInstance)(nil).WaitForShutdown))
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+)
+
+// Authenticate maps the verified certificate of the client with Mapper,
+// returning ErrInvalidCredentials for a client without one.
+func (m *MutualTLS) Authenticate(
+	ctx context.Context,
+	req *AuthRequest,
+) (*AuthResult, error) {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 {
+		return nil, ErrInvalidCredentials
+	}
+
+	cert := req.TLS.VerifiedChains[0][0]
+	result, err := m.Mapper.MapCertificate(ctx, cert, req)
+	if err != nil || result == nil {
+		return result, err
+	}
+
+	if result.User == "" {
+		named := *result
+		named.User = cert.Subject.String()
+		result = &named
+	}
+
+	return result, nil
+}
+
+// MapCertificate calls f.
+func (f CertificateMapperFunc) MapCertificate(
+	ctx context.Context,
+	cert *x509.Certificate,
+	req *AuthRequest,
+) (*AuthResult, error) {
+	return f(ctx, cert, req)
+}
+
+// MapCertificate binds the client to the user keyed by the first identity
+// of cert found, returning ErrInvalidCredentials when none is.
+func (u CertificateUsers) MapCertificate(
+	_ context.Context,
+	cert *x509.Certificate,
+	_ *AuthRequest,
+) (*AuthResult, error) {
+	for _, identity := range certificateIdentities(cert) {
+		if user, ok := u[identity]; ok {
+			return &AuthResult{
+				Account:     user.Account,
+				Permissions: user.Permissions,
+				User:        identity,
+			}, nil
+		}
+	}
+
+	return nil, ErrInvalidCredentials
+}
+
+// certificateIdentities returns the identities of cert CertificateUsers
+// are keyed by, in the order they are looked up.
+func certificateIdentities(
+	cert *x509.Certificate,
+) []string {
+	identities := []string{cert.Subject.String()}
+	identities = append(identities, cert.EmailAddresses...)
+	identities = append(identities, cert.DNSNames...)
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+
+	return identities
+}
+
+// authenticator returns what authenticates clients in place of the
+// NATS server: the Authenticator, or MutualTLS, or nil for neither.
+func (o *Options) authenticator() Authenticator {
+	switch {
+	case o.Authenticator != nil:
+		return o.Authenticator
+	case o.MutualTLS != nil:
+		return o.MutualTLS
+	default:
+		return nil
+	}
+}
+
+// requireClientCertificates makes the client TLS of natsOpts require a
+// verified certificate from every client when the options have
+// MutualTLS. The TLS configuration is copied, as it may be shared.
+func (o *Options) requireClientCertificates(
+	natsOpts *natsserver.Options,
+) {
+	if o.MutualTLS == nil || natsOpts.TLSConfig == nil {
+		return
+	}
+
+	config := natsOpts.TLSConfig.Clone()
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	natsOpts.TLSConfig = config
+	natsOpts.TLSVerify = true
+}
@@ -0,0 +1,598 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+type MutualTLSPublicTestSuite struct {
+	suite.Suite
+
+	srv  *server.Server
+	logs *syncBuffer
+	// ca issues the server's certificate, and clients the client
+	// certificates, through an intermediate.
+	ca      *testCA
+	clients *testCA
+	users   server.CertificateUsers
+}
+
+func (s *MutualTLSPublicTestSuite) SetupTest() {
+	s.logs = &syncBuffer{}
+	s.ca = newTestCA(s.T())
+	s.clients = s.ca.intermediate(s.T(), "clients CA")
+
+	dir := s.T().TempDir()
+	files := &server.TLSFiles{
+		CertFile: filepath.Join(dir, "server.pem"),
+		KeyFile:  filepath.Join(dir, "server-key.pem"),
+		CAFile:   filepath.Join(dir, "ca.pem"),
+	}
+	certPEM, keyPEM := s.ca.issue(s.T(), serverCertificate(
+		1,
+		time.Now().Add(-time.Hour),
+		time.Now().Add(time.Hour),
+	))
+	for path, data := range map[string][]byte{
+		files.CertFile: certPEM,
+		files.KeyFile:  keyPEM,
+		files.CAFile:   s.ca.pem,
+	} {
+		s.Require().NoError(os.WriteFile(path, data, 0o600))
+	}
+
+	subjects := &natsserver.SubjectPermission{
+		Allow: []string{"orders.>", "_INBOX.>"},
+	}
+	s.users = server.CertificateUsers{
+		"CN=alice,OU=orders,O=acme": {
+			Account: "orders",
+			Permissions: &natsserver.Permissions{
+				Publish:   subjects,
+				Subscribe: subjects,
+			},
+		},
+		"bob@acme.test":            {Account: "billing"},
+		"spiffe://acme.test/carol": {Account: "orders"},
+	}
+
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(s.logs, nil)),
+		&server.Options{
+			Options: &natsserver.Options{
+				Host:   "127.0.0.1",
+				Port:   freePort(s.T()),
+				NoSigs: true,
+				Accounts: []*natsserver.Account{
+					natsserver.NewAccount("orders"),
+					natsserver.NewAccount("billing"),
+				},
+			},
+			ReadyTimeout: 5 * time.Second,
+			TLSFiles:     files,
+			MutualTLS:    &server.MutualTLS{Mapper: s.users},
+		},
+	)
+}
+
+func (s *MutualTLSPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *MutualTLSPublicTestSuite) TearDownSubTest() {
+	s.srv.Stop()
+}
+
+func (s *MutualTLSPublicTestSuite) TearDownTest() {
+	s.srv.Stop()
+}
+
+func (s *MutualTLSPublicTestSuite) TestStart() {
+	tests := []struct {
+		name         string
+		setup        func(opts *server.Options)
+		validateFunc func()
+	}{
+		{
+			name:  "binds clients by subject and alternative names",
+			setup: func(*server.Options) {},
+			validateFunc: func() {
+				violations := make(chan error, 1)
+				alice, err := s.connect(
+					s.clientCertificate(pkix.Name{
+						CommonName:         "alice",
+						OrganizationalUnit: []string{"orders"},
+						Organization:       []string{"acme"},
+					}),
+					nats.ErrorHandler(func(
+						_ *nats.Conn,
+						_ *nats.Subscription,
+						err error,
+					) {
+						violations <- err
+					}),
+				)
+				s.Require().NoError(err)
+				defer alice.Close()
+
+				sub, err := alice.SubscribeSync("orders.new")
+				s.Require().NoError(err)
+				s.Require().NoError(alice.Flush())
+
+				bob, err := s.connect(s.clientCertificate(
+					pkix.Name{CommonName: "bob"},
+					func(c *x509.Certificate) {
+						c.EmailAddresses = []string{"bob@acme.test"}
+					},
+				))
+				s.Require().NoError(err)
+				defer bob.Close()
+				s.Require().NoError(bob.Publish("orders.new", []byte("bob")))
+				s.Require().NoError(bob.Flush())
+
+				carol, err := s.connect(s.clientCertificate(
+					pkix.Name{CommonName: "carol"},
+					func(c *x509.Certificate) {
+						c.URIs = []*url.URL{{
+							Scheme: "spiffe",
+							Host:   "acme.test",
+							Path:   "/carol",
+						}}
+					},
+				))
+				s.Require().NoError(err)
+				defer carol.Close()
+				s.Require().NoError(carol.Publish("orders.new", []byte("carol")))
+
+				msg, err := sub.NextMsg(time.Second)
+				s.Require().NoError(err)
+				s.Equal("carol", string(msg.Data))
+
+				s.Require().NoError(alice.Publish("billing.new", nil))
+				select {
+				case err := <-violations:
+					s.ErrorContains(err, "Permissions Violation")
+				case <-time.After(time.Second):
+					s.Fail("publish outside permissions allowed")
+				}
+			},
+		},
+		{
+			name:  "refuses certificates of unknown users",
+			setup: func(*server.Options) {},
+			validateFunc: func() {
+				_, err := s.connect(s.clientCertificate(
+					pkix.Name{CommonName: "mallory"},
+				))
+				s.ErrorIs(err, nats.ErrAuthorization)
+			},
+		},
+		{
+			name:  "refuses clients without certificates",
+			setup: func(*server.Options) {},
+			validateFunc: func() {
+				_, err := s.connect(nil)
+				s.Error(err)
+			},
+		},
+		{
+			name:  "refuses certificates of other authorities",
+			setup: func(*server.Options) {},
+			validateFunc: func() {
+				s.clients = newTestCA(s.T())
+				_, err := s.connect(s.clientCertificate(pkix.Name{
+					CommonName:         "alice",
+					OrganizationalUnit: []string{"orders"},
+					Organization:       []string{"acme"},
+				}))
+				s.Error(err)
+			},
+		},
+		{
+			name: "logs mapper errors",
+			setup: func(opts *server.Options) {
+				opts.MutualTLS.Mapper = server.CertificateMapperFunc(func(
+					context.Context,
+					*x509.Certificate,
+					*server.AuthRequest,
+				) (*server.AuthResult, error) {
+					return nil, errors.New("directory unavailable")
+				})
+			},
+			validateFunc: func() {
+				_, err := s.connect(s.clientCertificate(
+					pkix.Name{CommonName: "alice"},
+				))
+				s.ErrorIs(err, nats.ErrAuthorization)
+				s.True(s.logs.wait("directory unavailable"))
+			},
+		},
+		{
+			name: "requires client certificates of tls config",
+			setup: func(opts *server.Options) {
+				pool := x509.NewCertPool()
+				pool.AddCert(s.clients.cert)
+				cert, err := tls.LoadX509KeyPair(
+					opts.TLSFiles.CertFile,
+					opts.TLSFiles.KeyFile,
+				)
+				s.Require().NoError(err)
+
+				opts.TLSFiles = nil
+				opts.TLSConfig = &tls.Config{
+					MinVersion:   tls.VersionTLS12,
+					Certificates: []tls.Certificate{cert},
+					ClientCAs:    pool,
+				}
+				opts.TLS = true
+			},
+			validateFunc: func() {
+				_, err := s.connect(nil)
+				s.Error(err)
+				s.Equal(tls.NoClientCert, s.srv.Opts.TLSConfig.ClientAuth)
+
+				nc, err := s.connect(s.clientCertificate(
+					pkix.Name{CommonName: "bob"},
+					func(c *x509.Certificate) {
+						c.EmailAddresses = []string{"bob@acme.test"}
+					},
+				))
+				s.Require().NoError(err)
+				nc.Close()
+			},
+		},
+		{
+			name: "authenticates dev tls client certificates",
+			setup: func(opts *server.Options) {
+				opts.TLSFiles = nil
+				opts.DevTLS = &server.DevTLS{ClientName: "dev"}
+				opts.MutualTLS.Mapper = server.CertificateUsers{
+					"CN=dev": {Account: "orders"},
+				}
+			},
+			validateFunc: func() {
+				certs, err := s.srv.DevCertificates()
+				s.Require().NoError(err)
+
+				nc, err := nats.Connect(
+					fmt.Sprintf("nats://127.0.0.1:%d", s.srv.Opts.Port),
+					nats.Secure(certs.ClientTLSConfig()),
+					nats.NoReconnect(),
+				)
+				s.Require().NoError(err)
+				nc.Close()
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup(s.srv.Opts)
+			s.Require().NoError(s.srv.Start())
+
+			tc.validateFunc()
+		})
+	}
+}
+
+func (s *MutualTLSPublicTestSuite) TestReload() {
+	tests := []struct {
+		name         string
+		change       func(opts *server.Options)
+		validateFunc func(err error)
+	}{
+		{
+			name: "replaces the mapper",
+			change: func(opts *server.Options) {
+				opts.MutualTLS = &server.MutualTLS{
+					Mapper: server.CertificateUsers{
+						"CN=mallory": {Account: "orders"},
+					},
+				}
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+
+				nc, err := s.connect(s.clientCertificate(
+					pkix.Name{CommonName: "mallory"},
+				))
+				s.Require().NoError(err)
+				nc.Close()
+			},
+		},
+		{
+			name: "refuses removing mutual tls",
+			change: func(opts *server.Options) {
+				opts.MutualTLS = nil
+			},
+			validateFunc: func(err error) {
+				var reloadErr *server.ReloadError
+				s.Require().ErrorAs(err, &reloadErr)
+				s.Equal([]string{"MutualTLS"}, reloadErr.Fields)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.Require().NoError(s.srv.Start())
+			newOpts := *s.srv.Opts
+			newOpts.Options = s.srv.Opts.Clone()
+			tc.change(&newOpts)
+
+			tc.validateFunc(s.srv.Reload(&newOpts))
+		})
+	}
+}
+
+func (s *MutualTLSPublicTestSuite) TestAuthenticate() {
+	cert := &x509.Certificate{
+		Subject: pkix.Name{CommonName: "alice", Organization: []string{"acme"}},
+	}
+	verified := &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{cert}},
+	}
+
+	tests := []struct {
+		name         string
+		tls          *tls.ConnectionState
+		result       *server.AuthResult
+		err          error
+		validateFunc func(result *server.AuthResult, err error)
+	}{
+		{
+			name:   "names the connection after the subject",
+			tls:    verified,
+			result: &server.AuthResult{Account: "orders"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Require().NoError(err)
+				s.Equal(&server.AuthResult{
+					Account: "orders",
+					User:    "CN=alice,O=acme",
+				}, result)
+			},
+		},
+		{
+			name:   "keeps the user named by the mapper",
+			tls:    verified,
+			result: &server.AuthResult{User: "alice"},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Require().NoError(err)
+				s.Equal("alice", result.User)
+			},
+		},
+		{
+			name: "returns mapper errors",
+			tls:  verified,
+			err:  server.ErrInvalidCredentials,
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.ErrorIs(err, server.ErrInvalidCredentials)
+				s.Nil(result)
+			},
+		},
+		{
+			name: "returns no result when the mapper returns none",
+			tls:  verified,
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.NoError(err)
+				s.Nil(result)
+			},
+		},
+		{
+			name: "refuses clients without tls",
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.ErrorIs(err, server.ErrInvalidCredentials)
+				s.Nil(result)
+			},
+		},
+		{
+			name: "refuses clients without verified certificates",
+			tls:  &tls.ConnectionState{},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.ErrorIs(err, server.ErrInvalidCredentials)
+				s.Nil(result)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			mtls := &server.MutualTLS{
+				Mapper: server.CertificateMapperFunc(func(
+					_ context.Context,
+					got *x509.Certificate,
+					_ *server.AuthRequest,
+				) (*server.AuthResult, error) {
+					s.Same(cert, got)
+					return tc.result, tc.err
+				}),
+			}
+
+			tc.validateFunc(mtls.Authenticate(
+				context.Background(),
+				&server.AuthRequest{TLS: tc.tls},
+			))
+		})
+	}
+}
+
+func (s *MutualTLSPublicTestSuite) TestCertificateUsers() {
+	users := server.CertificateUsers{
+		"CN=alice,O=acme":          {Account: "subject"},
+		"alice@acme.test":          {Account: "email"},
+		"alice.acme.test":          {Account: "dns"},
+		"spiffe://acme.test/alice": {Account: "uri"},
+	}
+
+	tests := []struct {
+		name         string
+		cert         *x509.Certificate
+		validateFunc func(result *server.AuthResult, err error)
+	}{
+		{
+			name: "maps the subject first",
+			cert: &x509.Certificate{
+				Subject: pkix.Name{
+					CommonName:   "alice",
+					Organization: []string{"acme"},
+				},
+				EmailAddresses: []string{"alice@acme.test"},
+			},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Require().NoError(err)
+				s.Equal("subject", result.Account)
+				s.Equal("CN=alice,O=acme", result.User)
+			},
+		},
+		{
+			name: "maps email addresses before dns names",
+			cert: &x509.Certificate{
+				EmailAddresses: []string{"other@acme.test", "alice@acme.test"},
+				DNSNames:       []string{"alice.acme.test"},
+			},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Require().NoError(err)
+				s.Equal("email", result.Account)
+				s.Equal("alice@acme.test", result.User)
+			},
+		},
+		{
+			name: "maps dns names",
+			cert: &x509.Certificate{DNSNames: []string{"alice.acme.test"}},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Require().NoError(err)
+				s.Equal("dns", result.Account)
+			},
+		},
+		{
+			name: "maps uris",
+			cert: &x509.Certificate{URIs: []*url.URL{{
+				Scheme: "spiffe",
+				Host:   "acme.test",
+				Path:   "/alice",
+			}}},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.Require().NoError(err)
+				s.Equal("uri", result.Account)
+			},
+		},
+		{
+			name: "refuses unknown identities",
+			cert: &x509.Certificate{Subject: pkix.Name{CommonName: "bob"}},
+			validateFunc: func(result *server.AuthResult, err error) {
+				s.ErrorIs(err, server.ErrInvalidCredentials)
+				s.Nil(result)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(users.MapCertificate(
+				context.Background(),
+				tc.cert,
+				&server.AuthRequest{},
+			))
+		})
+	}
+}
+
+// connect connects to the server presenting cert, or no certificate when
+// nil.
+func (s *MutualTLSPublicTestSuite) connect(
+	cert *tls.Certificate,
+	opts ...nats.Option,
+) (*nats.Conn, error) {
+	pool := x509.NewCertPool()
+	pool.AddCert(s.ca.cert)
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: "localhost",
+		RootCAs:    pool,
+	}
+	if cert != nil {
+		config.Certificates = []tls.Certificate{*cert}
+	}
+
+	opts = append(
+		opts,
+		nats.Secure(config),
+		nats.NoReconnect(),
+		nats.Timeout(time.Second),
+	)
+
+	return nats.Connect(
+		fmt.Sprintf("nats://127.0.0.1:%d", s.srv.Opts.Port),
+		opts...,
+	)
+}
+
+// clientCertificate returns a client certificate for subject issued by
+// the clients' certificate authority, with the intermediate in its chain,
+// changed by changes.
+func (s *MutualTLSPublicTestSuite) clientCertificate(
+	subject pkix.Name,
+	changes ...func(c *x509.Certificate),
+) *tls.Certificate {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      subject,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	for _, change := range changes {
+		change(template)
+	}
+
+	certPEM, keyPEM := s.clients.issue(s.T(), template)
+	cert, err := tls.X509KeyPair(
+		append(certPEM, s.clients.pem...),
+		keyPEM,
+	)
+	s.Require().NoError(err)
+
+	return &cert
+}
+
+func TestMutualTLSPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(MutualTLSPublicTestSuite))
+}
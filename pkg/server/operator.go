@@ -0,0 +1,515 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nats-io/jwt/v2"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+)
+
+// newOperatorRun returns the operator mode for a run of the server with
+// opts, with the operator loaded and an account resolver holding its
+// account JWTs, or nil when opts has no Operator.
+func (s *Server) newOperatorRun(
+	opts *Options,
+) (*operatorRun, error) {
+	if opts.Operator == nil {
+		return nil, nil
+	}
+
+	op, err := s.loadOperator(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := op.resolver(opts.Operator.ResolverDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &operatorRun{
+		operator: op,
+		resolver: resolver,
+	}, nil
+}
+
+// loadOperator returns the operator of opts, with the accounts of opts it
+// lacks created. An operator kept in memory is created on the first start
+// and reused on later ones; one kept in a directory is loaded from it on
+// every start.
+func (s *Server) loadOperator(
+	opts *Options,
+) (*operator, error) {
+	cfg := opts.Operator
+
+	s.mu.Lock()
+	op := s.operator
+	s.mu.Unlock()
+
+	if op == nil || cfg.Dir != "" {
+		key, err := loadKey(cfg.Dir, "operator", nkeys.PrefixByteOperator)
+		if err != nil {
+			return nil, err
+		}
+
+		op = &operator{
+			dir:      cfg.Dir,
+			key:      key,
+			accounts: make(map[string]*operatorAccount),
+		}
+	}
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	names, err := op.accountNames(cfg.Accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		if op.accounts[name] != nil {
+			continue
+		}
+
+		acc, err := op.loadAccount(
+			name,
+			opts.JetStream && name != SystemAccountName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		op.accounts[name] = acc
+	}
+
+	// The operator JWT is signed on every start, naming the system
+	// account; operator keys always have a public key.
+	pub, _ := op.key.PublicKey()
+	op.claims = jwt.NewOperatorClaims(pub)
+	op.claims.Name = cmp.Or(cfg.Name, DefaultOperatorName)
+	op.claims.SystemAccount = op.accounts[SystemAccountName].publicKey()
+	token, err := op.claims.Encode(op.key)
+	if err != nil {
+		return nil, fmt.Errorf("error signing operator: %w", err)
+	}
+	if err := writeFile(op.dir, "operator.jwt", token); err != nil {
+		return nil, fmt.Errorf("error saving operator: %w", err)
+	}
+
+	s.mu.Lock()
+	s.operator = op
+	s.mu.Unlock()
+
+	return op, nil
+}
+
+// accountNames returns the names of the system account, the accounts of
+// names, and the accounts in the operator's directory, which include those
+// added while the server ran.
+func (o *operator) accountNames(
+	names []string,
+) ([]string, error) {
+	names = append([]string{SystemAccountName}, names...)
+	if o.dir == "" {
+		return names, nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(o.dir, "accounts"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("error reading accounts: %w", err)
+	}
+
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".jwt"); ok {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+// loadAccount returns the account name, loaded from the operator's
+// directory, or created, signed, and written to it. A new account has
+// unlimited JetStream when jetstream is set.
+func (o *operator) loadAccount(
+	name string,
+	jetstream bool,
+) (*operatorAccount, error) {
+	acc, err := o.accountKey(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.dir != "" {
+		path := filepath.Join(o.dir, "accounts", name+".jwt")
+		data, err := os.ReadFile(path)
+		if err == nil {
+			acc.token = string(bytes.TrimSpace(data))
+			if err := o.checkAccount(acc); err != nil {
+				return nil, fmt.Errorf("error loading account %q: %w", name, err)
+			}
+
+			return acc, nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("error loading account %q: %w", name, err)
+		}
+	}
+
+	token, err := o.createAccount(name, acc, jetstream)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.save(name, acc, token); err != nil {
+		return nil, err
+	}
+
+	return acc, nil
+}
+
+// accountKey returns the account name holding only its key, loaded from
+// the operator's directory, or created and written to it.
+func (o *operator) accountKey(
+	name string,
+) (*operatorAccount, error) {
+	key, err := loadKey(
+		o.dir,
+		filepath.Join("accounts", name),
+		nkeys.PrefixByteAccount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &operatorAccount{key: key}, nil
+}
+
+// createAccount returns the JWT of the new account name with the key of
+// acc, signed but not saved. The account has unlimited JetStream when
+// jetstream is set.
+func (o *operator) createAccount(
+	name string,
+	acc *operatorAccount,
+	jetstream bool,
+) (string, error) {
+	claims := jwt.NewAccountClaims(acc.publicKey())
+	claims.Name = name
+	if jetstream {
+		claims.Limits.JetStreamLimits.MemoryStorage = jwt.NoLimit
+		claims.Limits.JetStreamLimits.DiskStorage = jwt.NoLimit
+	}
+
+	token, err := o.sign(claims)
+	if err != nil {
+		return "", fmt.Errorf("error creating account %q: %w", name, err)
+	}
+
+	return token, nil
+}
+
+// checkAccount checks that the JWT of acc is an account JWT for its key,
+// signed by the operator.
+func (o *operator) checkAccount(
+	acc *operatorAccount,
+) error {
+	claims, err := jwt.DecodeAccountClaims(acc.token)
+	if err == nil {
+		err = o.check(claims)
+	}
+	if err == nil && claims.Subject != acc.publicKey() {
+		err = fmt.Errorf("account jwt does not match the account key")
+	}
+
+	return err
+}
+
+// check checks that claims are valid, leaving their expiry to the NATS
+// server, and signed by the operator.
+func (o *operator) check(
+	claims *jwt.AccountClaims,
+) error {
+	vr := jwt.CreateValidationResults()
+	claims.Validate(vr)
+	if vr.IsBlocking(false) {
+		return errors.Join(vr.Errors()...)
+	}
+
+	// Operator keys always have a public key.
+	if issuer, _ := o.key.PublicKey(); claims.Issuer != issuer {
+		return fmt.Errorf("account jwt not signed by the operator")
+	}
+
+	return nil
+}
+
+// sign returns the JWT of claims signed by the operator.
+func (o *operator) sign(
+	claims *jwt.AccountClaims,
+) (string, error) {
+	token, err := claims.Encode(o.key)
+	if err != nil {
+		return "", fmt.Errorf("error signing account jwt: %w", err)
+	}
+
+	return token, nil
+}
+
+// save records token as the JWT of acc, the account name, and writes it
+// to the operator's directory.
+func (o *operator) save(
+	name string,
+	acc *operatorAccount,
+	token string,
+) error {
+	if err := writeFile(
+		o.dir,
+		filepath.Join("accounts", name+".jwt"),
+		token,
+	); err != nil {
+		return fmt.Errorf("error saving account %q: %w", name, err)
+	}
+	acc.token = token
+
+	return nil
+}
+
+// resolver returns an account resolver holding the operator's account
+// JWTs, kept in dir, or in memory when dir is "".
+func (o *operator) resolver(
+	dir string,
+) (natsserver.AccountResolver, error) {
+	var resolver natsserver.AccountResolver = &natsserver.MemAccResolver{}
+	if dir != "" {
+		dirResolver, err := natsserver.NewDirAccResolver(
+			dir,
+			0,
+			0,
+			natsserver.NoDelete,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error opening account resolver: %w", err)
+		}
+		resolver = dirResolver
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for name, acc := range o.accounts {
+		if err := resolver.Store(acc.publicKey(), acc.token); err != nil {
+			resolver.Close()
+			return nil, fmt.Errorf(
+				"error storing account %q in resolver: %w",
+				name,
+				err,
+			)
+		}
+	}
+
+	return resolver, nil
+}
+
+// publicKey returns the public key of the account.
+func (a *operatorAccount) publicKey() string {
+	// Account keys always have a public key.
+	pub, _ := a.key.PublicKey()
+
+	return pub
+}
+
+// configure makes natsOpts trust the operator, with its system account,
+// resolving accounts through the run's resolver.
+func (r *operatorRun) configure(
+	natsOpts *natsserver.Options,
+) {
+	if r == nil {
+		return
+	}
+
+	natsOpts.TrustedOperators = []*jwt.OperatorClaims{r.operator.claims}
+	natsOpts.SystemAccount = r.operator.claims.SystemAccount
+	natsOpts.AccountResolver = r.resolver
+}
+
+// start connects to natsServer in process as a user of the system
+// account, signed for the run, to push account JWT updates through.
+func (r *operatorRun) start(
+	natsServer NATSServerInstance,
+) error {
+	if r == nil {
+		return nil
+	}
+
+	user, err := nkeys.CreateUser()
+	if err != nil {
+		return fmt.Errorf("error creating operator system user: %w", err)
+	}
+	// User keys always have a public key and seed.
+	pub, _ := user.PublicKey()
+	seed, _ := user.Seed()
+
+	claims := jwt.NewUserClaims(pub)
+	claims.Name = operatorSystemUser
+	r.operator.mu.Lock()
+	system := r.operator.accounts[SystemAccountName]
+	r.operator.mu.Unlock()
+	token, err := claims.Encode(system.key)
+	if err != nil {
+		return fmt.Errorf("error signing operator system user: %w", err)
+	}
+
+	conn, err := nats.Connect(
+		"",
+		nats.InProcessServer(natsServer),
+		nats.UserJWTAndSeed(token, string(seed)),
+		nats.Name(operatorSystemUser),
+		nats.NoReconnect(),
+	)
+	if err != nil {
+		return fmt.Errorf("error connecting operator system user: %w", err)
+	}
+
+	r.conn = conn
+
+	return nil
+}
+
+// stop closes the run's system account connection and account resolver,
+// which the NATS server also closes when it shuts down.
+func (r *operatorRun) stop() {
+	if r == nil {
+		return
+	}
+
+	if r.conn != nil {
+		r.conn.Close()
+	}
+	r.resolver.Close()
+}
+
+// push gives token, the JWT of the account pub, to the NATS server, which
+// applies it to the account's clients.
+func (r *operatorRun) push(
+	pub string,
+	token string,
+) error {
+	msg, err := r.conn.Request(
+		fmt.Sprintf(accountUpdateSubject, pub),
+		[]byte(token),
+		accountUpdateTimeout,
+	)
+
+	var res natsserver.ServerAPIClaimUpdateResponse
+	if err == nil {
+		err = json.Unmarshal(msg.Data, &res)
+	}
+	if err == nil && res.Error != nil {
+		err = errors.New(res.Error.Description)
+	}
+	if err != nil {
+		return fmt.Errorf("error pushing account jwt: %w", err)
+	}
+
+	// A directory resolver stores the JWTs it is pushed, where the NATS
+	// server only updates the accounts it has loaded. The memory resolver
+	// is only given the JWT once the NATS server accepted it.
+	if !r.resolver.IsTrackingUpdate() {
+		// Storing in the memory resolver cannot fail.
+		_ = r.resolver.Store(pub, token)
+	}
+
+	return nil
+}
+
+// loadKey returns the key stored as name in dir, or a new key with prefix
+// written there when there is none. Keys are not stored when dir is "".
+func loadKey(
+	dir string,
+	name string,
+	prefix nkeys.PrefixByte,
+) (nkeys.KeyPair, error) {
+	var seed []byte
+	err := fs.ErrNotExist
+	if dir != "" {
+		seed, err = os.ReadFile(filepath.Join(dir, name+".nk"))
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		key, err := nkeys.CreatePair(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("error creating key: %w", err)
+		}
+		// New keys always have a seed.
+		seed, _ = key.Seed()
+		if err := writeFile(dir, name+".nk", string(seed)); err != nil {
+			return nil, fmt.Errorf("error saving key: %w", err)
+		}
+
+		return key, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading key: %w", err)
+	}
+
+	key, err := nkeys.FromSeed(bytes.TrimSpace(seed))
+	if err == nil {
+		if pub, _ := key.PublicKey(); nkeys.Prefix(pub) != prefix {
+			err = fmt.Errorf("expected %s key", prefix)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading key %s: %w", name, err)
+	}
+
+	return key, nil
+}
+
+// writeFile writes data to the file name in dir, readable only by its
+// owner, creating the directories it is in. Nothing is written when dir
+// is "".
+func writeFile(
+	dir string,
+	name string,
+	data string,
+) error {
+	if dir == "" {
+		return nil
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(data), 0o600)
+}
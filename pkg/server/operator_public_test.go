@@ -0,0 +1,1158 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+type OperatorPublicTestSuite struct {
+	suite.Suite
+
+	mockCtrl       *gomock.Controller
+	mockNATSServer *mocks.MockNATSServerInstance
+	srv            *server.Server
+	started        []*natsserver.Options
+	newNATSServer  func(*natsserver.Options) (server.NATSServerInstance, error)
+}
+
+func (s *OperatorPublicTestSuite) SetupSuite() {
+	s.newNATSServer = server.NewNATSServer
+}
+
+func (s *OperatorPublicTestSuite) TearDownSuite() {
+	server.NewNATSServer = s.newNATSServer
+}
+
+func (s *OperatorPublicTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockNATSServer = mocks.NewMockNATSServerInstance(s.mockCtrl)
+	s.started = nil
+	s.srv = s.newServer(&server.Operator{Accounts: []string{"APP"}})
+
+	server.NewNATSServer = func(
+		opts *natsserver.Options,
+	) (server.NATSServerInstance, error) {
+		s.started = append(s.started, opts)
+		return s.newNATSServer(opts)
+	}
+}
+
+func (s *OperatorPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *OperatorPublicTestSuite) TearDownSubTest() {
+	s.srv.Stop()
+}
+
+func (s *OperatorPublicTestSuite) TearDownTest() {
+	s.srv.Stop()
+	s.mockCtrl.Finish()
+}
+
+func (s *OperatorPublicTestSuite) TestStart() {
+	tests := []struct {
+		name         string
+		setup        func(opts *server.Options)
+		validateFunc func(err error)
+	}{
+		{
+			name:  "configures operator mode in memory",
+			setup: func(*server.Options) {},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Require().Len(s.started, 1)
+				started := s.started[0]
+
+				s.Require().Len(started.TrustedOperators, 1)
+				operator := started.TrustedOperators[0]
+				s.Equal("embedded", operator.Name)
+				s.True(nkeys.IsValidPublicAccountKey(operator.SystemAccount))
+				s.Equal(operator.SystemAccount, started.SystemAccount)
+				s.IsType(&natsserver.MemAccResolver{}, started.AccountResolver)
+				s.Nil(s.srv.Opts.TrustedOperators)
+
+				app := s.claims("APP")
+				s.Equal("APP", app.Name)
+				s.Equal(operator.Subject, app.Issuer)
+				s.False(app.Limits.IsJSEnabled())
+				s.Equal(operator.SystemAccount, s.claims("SYS").Subject)
+			},
+		},
+		{
+			name:  "keeps keys in memory across restarts",
+			setup: func(*server.Options) {},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				app := s.claims("APP")
+
+				s.Require().NoError(s.srv.Restart())
+				s.Require().Len(s.started, 2)
+				s.Equal(
+					s.started[0].TrustedOperators[0].Subject,
+					s.started[1].TrustedOperators[0].Subject,
+				)
+				s.Equal(app.Subject, s.claims("APP").Subject)
+			},
+		},
+		{
+			name: "creates keys in directory and loads them again",
+			setup: func(opts *server.Options) {
+				opts.Operator.Name = "acme"
+				opts.Operator.Dir = s.T().TempDir()
+				opts.JetStream = true
+				opts.StoreDir = s.T().TempDir()
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				dir := s.srv.Opts.Operator.Dir
+				for _, name := range []string{
+					"operator.nk",
+					"operator.jwt",
+					"accounts/SYS.nk",
+					"accounts/SYS.jwt",
+					"accounts/APP.nk",
+					"accounts/APP.jwt",
+				} {
+					info, err := os.Stat(filepath.Join(dir, name))
+					s.Require().NoError(err)
+					s.Equal(os.FileMode(0o600), info.Mode().Perm())
+				}
+
+				operator, err := jwt.DecodeOperatorClaims(
+					s.readFile(dir, "operator.jwt"),
+				)
+				s.Require().NoError(err)
+				s.Equal("acme", operator.Name)
+				s.True(s.claims("APP").Limits.IsJSEnabled())
+				s.False(s.claims("SYS").Limits.IsJSEnabled())
+
+				app := s.claims("APP")
+				s.srv.Stop()
+				s.srv = s.newServer(s.srv.Opts.Operator)
+				s.srv.Opts.JetStream = true
+				s.srv.Opts.StoreDir = s.T().TempDir()
+				s.Require().NoError(s.srv.Start())
+				s.Equal(app, s.claims("APP"))
+				s.Equal(
+					operator.Subject,
+					s.started[1].TrustedOperators[0].Subject,
+				)
+			},
+		},
+		{
+			name: "stores account jwts in directory resolver",
+			setup: func(opts *server.Options) {
+				opts.Operator.ResolverDir = s.T().TempDir()
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.IsType(
+					&natsserver.DirAccResolver{},
+					s.started[0].AccountResolver,
+				)
+
+				token, err := s.srv.AccountJWT("APP")
+				s.Require().NoError(err)
+				s.Equal(token, s.readFile(
+					s.srv.Opts.Operator.ResolverDir,
+					s.claims("APP").Subject+".jwt",
+				))
+			},
+		},
+		{
+			name: "fails when key cannot be saved",
+			setup: func(opts *server.Options) {
+				opts.Operator.Dir = s.T().TempDir()
+				s.symlinkMissing(opts.Operator.Dir, "operator.nk")
+			},
+			validateFunc: func(err error) {
+				s.createFailed(err, "error saving key")
+			},
+		},
+		{
+			name: "fails when directory cannot be created",
+			setup: func(opts *server.Options) {
+				opts.Operator.Dir = filepath.Join(s.T().TempDir(), "operator")
+				s.symlinkMissing(filepath.Dir(opts.Operator.Dir), "operator")
+			},
+			validateFunc: func(err error) {
+				s.createFailed(err, "error saving key")
+			},
+		},
+		{
+			name: "fails when account key cannot be read",
+			setup: func(opts *server.Options) {
+				opts.Operator.Dir = s.T().TempDir()
+				s.mkdir(opts.Operator.Dir, "accounts/APP.nk")
+			},
+			validateFunc: func(err error) {
+				s.createFailed(err, "error reading key")
+			},
+		},
+		{
+			name: "fails when key cannot be read",
+			setup: func(opts *server.Options) {
+				opts.Operator.Dir = s.T().TempDir()
+				s.mkdir(opts.Operator.Dir, "operator.nk")
+			},
+			validateFunc: func(err error) {
+				s.createFailed(err, "error reading key")
+			},
+		},
+		{
+			name: "fails when key is not a seed",
+			setup: func(opts *server.Options) {
+				opts.Operator.Dir = s.T().TempDir()
+				s.writeFile(opts.Operator.Dir, "operator.nk", "junk")
+			},
+			validateFunc: func(err error) {
+				s.createFailed(err, "error reading key operator")
+			},
+		},
+		{
+			name: "fails when key is of another kind",
+			setup: func(opts *server.Options) {
+				opts.Operator.Dir = s.T().TempDir()
+				key, err := nkeys.CreateAccount()
+				s.Require().NoError(err)
+				s.writeSeed(opts.Operator.Dir, "operator.nk", key)
+			},
+			validateFunc: func(err error) {
+				s.createFailed(
+					err,
+					"error reading key operator: expected operator key",
+				)
+			},
+		},
+		{
+			name: "fails when accounts cannot be read",
+			setup: func(opts *server.Options) {
+				opts.Operator.Dir = s.T().TempDir()
+				s.writeFile(opts.Operator.Dir, "accounts", "")
+			},
+			validateFunc: func(err error) {
+				s.createFailed(err, "error reading accounts")
+			},
+		},
+		{
+			name: "fails when account jwt cannot be read",
+			setup: func(opts *server.Options) {
+				opts.Operator.Dir = s.T().TempDir()
+				s.mkdir(opts.Operator.Dir, "accounts/APP.jwt")
+			},
+			validateFunc: func(err error) {
+				s.createFailed(err, `error loading account "APP"`)
+			},
+		},
+		{
+			name: "fails when account jwt is invalid",
+			setup: func(opts *server.Options) {
+				opts.Operator.Dir = s.T().TempDir()
+				s.writeFile(opts.Operator.Dir, "accounts/APP.jwt", "junk")
+			},
+			validateFunc: func(err error) {
+				s.createFailed(err, `error loading account "APP"`)
+			},
+		},
+		{
+			name: "fails when account jwt is of another operator",
+			setup: func(opts *server.Options) {
+				opts.Operator.Dir = s.T().TempDir()
+				other, err := nkeys.CreateOperator()
+				s.Require().NoError(err)
+				s.writeAccount(opts.Operator.Dir, "APP", other)
+			},
+			validateFunc: func(err error) {
+				s.createFailed(err, "account jwt not signed by the operator")
+			},
+		},
+		{
+			name: "fails when account jwt is of another account",
+			setup: func(opts *server.Options) {
+				opts.Operator.Dir = s.T().TempDir()
+				operator, err := nkeys.CreateOperator()
+				s.Require().NoError(err)
+				s.writeSeed(opts.Operator.Dir, "operator.nk", operator)
+				s.writeAccount(opts.Operator.Dir, "APP", operator)
+			},
+			validateFunc: func(err error) {
+				s.createFailed(err, "account jwt does not match the account key")
+			},
+		},
+		{
+			name: "fails when account jwt cannot be saved",
+			setup: func(opts *server.Options) {
+				opts.Operator.Dir = s.T().TempDir()
+				s.symlinkMissing(opts.Operator.Dir, "accounts/APP.jwt")
+			},
+			validateFunc: func(err error) {
+				s.createFailed(err, `error saving account "APP"`)
+			},
+		},
+		{
+			name: "fails when operator jwt cannot be saved",
+			setup: func(opts *server.Options) {
+				opts.Operator.Dir = s.T().TempDir()
+				s.mkdir(opts.Operator.Dir, "operator.jwt")
+			},
+			validateFunc: func(err error) {
+				s.createFailed(err, "error saving operator")
+			},
+		},
+		{
+			name: "fails when resolver directory cannot be created",
+			setup: func(opts *server.Options) {
+				opts.Operator.ResolverDir = s.writeFile(
+					s.T().TempDir(),
+					"file",
+					"",
+				)
+			},
+			validateFunc: func(err error) {
+				s.createFailed(err, "error opening account resolver")
+			},
+		},
+		{
+			name: "fails when resolver cannot store account",
+			setup: func(opts *server.Options) {
+				opts.Operator.Dir = s.T().TempDir()
+				opts.Operator.ResolverDir = s.T().TempDir()
+				key, err := nkeys.CreateAccount()
+				s.Require().NoError(err)
+				s.writeSeed(opts.Operator.Dir, "accounts/APP.nk", key)
+				pub, err := key.PublicKey()
+				s.Require().NoError(err)
+				s.mkdir(opts.Operator.ResolverDir, pub+".jwt")
+			},
+			validateFunc: func(err error) {
+				s.createFailed(err, `error storing account "APP" in resolver`)
+			},
+		},
+		{
+			name: "fails when system user cannot connect",
+			setup: func(*server.Options) {
+				server.NewNATSServer = func(
+					*natsserver.Options,
+				) (server.NATSServerInstance, error) {
+					return s.mockNATSServer, nil
+				}
+				s.mockNATSServer.EXPECT().Start().AnyTimes()
+				s.mockNATSServer.EXPECT().
+					ReadyForConnections(gomock.Any()).
+					Return(true)
+				s.mockNATSServer.EXPECT().SetLogger(gomock.Any(), true, true)
+				s.mockNATSServer.EXPECT().
+					InProcessConn().
+					Return(nil, errors.New("server shutting down"))
+				s.mockNATSServer.EXPECT().Shutdown()
+			},
+			validateFunc: func(err error) {
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhaseReady, startErr.Phase)
+				s.ErrorContains(err, "error connecting operator system user")
+				s.ErrorContains(err, "server shutting down")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup(s.srv.Opts)
+
+			tc.validateFunc(s.srv.Start())
+		})
+	}
+}
+
+func (s *OperatorPublicTestSuite) TestAccountJWT() {
+	tests := []struct {
+		name         string
+		setup        func()
+		account      string
+		validateFunc func(token string, err error)
+	}{
+		{
+			name:    "returns account jwt",
+			setup:   s.start,
+			account: "APP",
+			validateFunc: func(token string, err error) {
+				s.Require().NoError(err)
+				claims, err := jwt.DecodeAccountClaims(token)
+				s.Require().NoError(err)
+				s.Equal("APP", claims.Name)
+			},
+		},
+		{
+			name:    "fails for unknown account",
+			setup:   s.start,
+			account: "ORDERS",
+			validateFunc: func(_ string, err error) {
+				s.ErrorIs(err, server.ErrUnknownAccount)
+				s.ErrorContains(err, `error getting account "ORDERS"`)
+			},
+		},
+		{
+			name:    "fails when not running",
+			setup:   func() {},
+			account: "APP",
+			validateFunc: func(_ string, err error) {
+				s.ErrorIs(err, server.ErrNotRunning)
+			},
+		},
+		{
+			name: "fails when not in operator mode",
+			setup: func() {
+				s.srv.Opts.Operator = nil
+				s.start()
+			},
+			account: "APP",
+			validateFunc: func(_ string, err error) {
+				s.ErrorIs(err, server.ErrNotOperatorMode)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+
+			tc.validateFunc(s.srv.AccountJWT(tc.account))
+		})
+	}
+}
+
+func (s *OperatorPublicTestSuite) TestAddAccountJWT() {
+	tests := []struct {
+		name         string
+		setup        func()
+		account      string
+		validateFunc func(pub string, err error)
+	}{
+		{
+			name:    "adds account clients can use",
+			setup:   s.start,
+			account: "ORDERS",
+			validateFunc: func(pub string, err error) {
+				s.Require().NoError(err)
+				s.True(nkeys.IsValidPublicAccountKey(pub))
+				claims := s.claims("ORDERS")
+				s.Equal(pub, claims.Subject)
+				s.Equal("ORDERS", claims.Name)
+				s.False(claims.Limits.IsJSEnabled())
+
+				nc, _, err := s.connect("ORDERS")
+				s.Require().NoError(err)
+				nc.Close()
+			},
+		},
+		{
+			name: "keeps account for later starts",
+			setup: func() {
+				s.srv.Opts.Operator.Dir = s.T().TempDir()
+				s.srv.Opts.JetStream = true
+				s.srv.Opts.StoreDir = s.T().TempDir()
+				s.start()
+			},
+			account: "ORDERS",
+			validateFunc: func(pub string, err error) {
+				s.Require().NoError(err)
+				s.True(s.claims("ORDERS").Limits.IsJSEnabled())
+
+				s.Require().NoError(s.srv.Restart())
+				s.Equal(pub, s.claims("ORDERS").Subject)
+				nc, _, err := s.connect("ORDERS")
+				s.Require().NoError(err)
+				nc.Close()
+			},
+		},
+		{
+			name:    "fails for existing account",
+			setup:   s.start,
+			account: "APP",
+			validateFunc: func(_ string, err error) {
+				s.ErrorIs(err, server.ErrAccountExists)
+				s.ErrorContains(err, `error adding account "APP"`)
+			},
+		},
+		{
+			name:    "fails for invalid name",
+			setup:   s.start,
+			account: "a/b",
+			validateFunc: func(_ string, err error) {
+				s.EqualError(
+					err,
+					`error adding account "a/b": invalid account name`,
+				)
+			},
+		},
+		{
+			name: "fails when account cannot be saved",
+			setup: func() {
+				s.srv.Opts.Operator.Dir = s.T().TempDir()
+				s.start()
+				s.symlinkMissing(s.srv.Opts.Operator.Dir, "accounts/ORDERS.jwt")
+			},
+			account: "ORDERS",
+			validateFunc: func(_ string, err error) {
+				s.ErrorContains(err, `error saving account "ORDERS"`)
+
+				_, err = s.srv.AccountJWT("ORDERS")
+				s.ErrorIs(err, server.ErrUnknownAccount)
+			},
+		},
+		{
+			name: "does not keep account server refuses",
+			setup: func() {
+				dir := s.T().TempDir()
+				s.srv.Opts.Operator.Dir = dir
+				s.srv.Opts.Operator.ResolverDir = s.T().TempDir()
+				key, err := nkeys.CreateAccount()
+				s.Require().NoError(err)
+				s.writeSeed(dir, "accounts/ORDERS.nk", key)
+				pub, err := key.PublicKey()
+				s.Require().NoError(err)
+				s.mkdir(s.srv.Opts.Operator.ResolverDir, pub+".jwt")
+				s.start()
+			},
+			account: "ORDERS",
+			validateFunc: func(_ string, err error) {
+				s.ErrorContains(err, "error pushing account jwt")
+				s.NoFileExists(filepath.Join(
+					s.srv.Opts.Operator.Dir,
+					"accounts",
+					"ORDERS.jwt",
+				))
+
+				_, err = s.srv.AccountJWT("ORDERS")
+				s.ErrorIs(err, server.ErrUnknownAccount)
+				s.Require().NoError(s.srv.Restart())
+				_, err = s.srv.AccountJWT("ORDERS")
+				s.ErrorIs(err, server.ErrUnknownAccount)
+			},
+		},
+		{
+			name:    "fails when not running",
+			setup:   func() {},
+			account: "ORDERS",
+			validateFunc: func(_ string, err error) {
+				s.ErrorIs(err, server.ErrNotRunning)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+
+			tc.validateFunc(s.srv.AddAccountJWT(tc.account))
+		})
+	}
+}
+
+func (s *OperatorPublicTestSuite) TestUpdateAccountJWT() {
+	tests := []struct {
+		name         string
+		setup        func()
+		account      string
+		change       func(claims *jwt.AccountClaims)
+		validateFunc func(err error)
+	}{
+		{
+			name:    "signs and saves changed claims",
+			setup:   s.start,
+			account: "APP",
+			change: func(claims *jwt.AccountClaims) {
+				claims.Limits.Subs = 10
+				claims.Subject = "ignored"
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				claims := s.claims("APP")
+				s.Equal(int64(10), claims.Limits.Subs)
+				s.True(nkeys.IsValidPublicAccountKey(claims.Subject))
+			},
+		},
+		{
+			name: "stores jwt in directory resolver",
+			setup: func() {
+				s.srv.Opts.Operator.ResolverDir = s.T().TempDir()
+				s.start()
+			},
+			account: "APP",
+			change: func(claims *jwt.AccountClaims) {
+				claims.Limits.Subs = 10
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				token, err := s.srv.AccountJWT("APP")
+				s.Require().NoError(err)
+				s.Eventually(func() bool {
+					return token == s.readFile(
+						s.srv.Opts.Operator.ResolverDir,
+						s.claims("APP").Subject+".jwt",
+					)
+				}, 5*time.Second, 10*time.Millisecond)
+			},
+		},
+		{
+			name:    "fails for unknown account",
+			setup:   s.start,
+			account: "ORDERS",
+			change:  func(*jwt.AccountClaims) {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrUnknownAccount)
+				s.ErrorContains(err, `error updating account "ORDERS"`)
+			},
+		},
+		{
+			name:    "fails for invalid claims",
+			setup:   s.start,
+			account: "APP",
+			change: func(claims *jwt.AccountClaims) {
+				claims.Imports.Add(&jwt.Import{
+					Type:    jwt.Stream,
+					Subject: "orders",
+				})
+			},
+			validateFunc: func(err error) {
+				s.ErrorContains(err, `error updating account "APP"`)
+				s.ErrorContains(err, "account to import from is not specified")
+			},
+		},
+		{
+			name:    "fails when server refuses claims",
+			setup:   s.start,
+			account: "SYS",
+			change: func(claims *jwt.AccountClaims) {
+				claims.Expires = time.Now().Add(-time.Hour).Unix()
+			},
+			validateFunc: func(err error) {
+				s.ErrorContains(err, "error pushing account jwt")
+				s.Zero(s.claims("SYS").Expires)
+			},
+		},
+		{
+			name: "fails when account cannot be saved",
+			setup: func() {
+				s.srv.Opts.Operator.Dir = s.T().TempDir()
+				s.start()
+				path := filepath.Join(
+					s.srv.Opts.Operator.Dir,
+					"accounts",
+					"APP.jwt",
+				)
+				s.Require().NoError(os.Remove(path))
+				s.Require().NoError(os.Mkdir(path, 0o700))
+			},
+			account: "APP",
+			change:  func(*jwt.AccountClaims) {},
+			validateFunc: func(err error) {
+				s.ErrorContains(err, `error saving account "APP"`)
+			},
+		},
+		{
+			name:    "fails when not running",
+			setup:   func() {},
+			account: "APP",
+			change:  func(*jwt.AccountClaims) {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrNotRunning)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+
+			tc.validateFunc(s.srv.UpdateAccountJWT(tc.account, tc.change))
+		})
+	}
+}
+
+func (s *OperatorPublicTestSuite) TestRevokeAccountJWT() {
+	tests := []struct {
+		name         string
+		setup        func()
+		account      string
+		validateFunc func(err error)
+	}{
+		{
+			name:    "fails for system account",
+			setup:   s.start,
+			account: "SYS",
+			validateFunc: func(err error) {
+				s.EqualError(
+					err,
+					`error revoking account "SYS": `+
+						"cannot revoke the system account",
+				)
+			},
+		},
+		{
+			name:    "fails for unknown account",
+			setup:   s.start,
+			account: "ORDERS",
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrUnknownAccount)
+			},
+		},
+		{
+			name:    "fails when not running",
+			setup:   func() {},
+			account: "APP",
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrNotRunning)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+
+			tc.validateFunc(s.srv.RevokeAccountJWT(tc.account))
+		})
+	}
+}
+
+func (s *OperatorPublicTestSuite) TestPushAccountJWT() {
+	var external nkeys.KeyPair
+	tests := []struct {
+		name         string
+		setup        func()
+		token        func() string
+		validateFunc func(err error)
+	}{
+		{
+			name:  "replaces jwt of operator account",
+			setup: s.startInDir,
+			token: func() string {
+				claims := s.claims("APP")
+				claims.Limits.Subs = 10
+				return s.sign(claims)
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Equal(int64(10), s.claims("APP").Limits.Subs)
+			},
+		},
+		{
+			name:  "pushes account operator does not hold",
+			setup: s.startInDir,
+			token: func() string {
+				var err error
+				external, err = nkeys.CreateAccount()
+				s.Require().NoError(err)
+				pub, err := external.PublicKey()
+				s.Require().NoError(err)
+				claims := jwt.NewAccountClaims(pub)
+				claims.Name = "EXTERNAL"
+				return s.sign(claims)
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				_, err = s.srv.AccountJWT("EXTERNAL")
+				s.ErrorIs(err, server.ErrUnknownAccount)
+
+				nc, err := s.connectAs(external)
+				s.Require().NoError(err)
+				nc.Close()
+			},
+		},
+		{
+			name:  "fails for invalid token",
+			setup: s.startInDir,
+			token: func() string { return "junk" },
+			validateFunc: func(err error) {
+				s.ErrorContains(err, "error pushing account jwt")
+			},
+		},
+		{
+			name:  "fails for jwt of another operator",
+			setup: s.startInDir,
+			token: func() string {
+				other, err := nkeys.CreateOperator()
+				s.Require().NoError(err)
+				token, err := s.claims("APP").Encode(other)
+				s.Require().NoError(err)
+				return token
+			},
+			validateFunc: func(err error) {
+				s.EqualError(
+					err,
+					"error pushing account jwt: "+
+						"account jwt not signed by the operator",
+				)
+			},
+		},
+		{
+			name:  "fails when server refuses jwt",
+			setup: s.startInDir,
+			token: func() string {
+				claims := s.claims("SYS")
+				claims.Expires = time.Now().Add(-time.Hour).Unix()
+				return s.sign(claims)
+			},
+			validateFunc: func(err error) {
+				s.ErrorContains(err, "error pushing account jwt")
+				s.Zero(s.claims("SYS").Expires)
+			},
+		},
+		{
+			name:  "fails when not running",
+			setup: func() {},
+			token: func() string { return "junk" },
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrNotRunning)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+
+			tc.validateFunc(s.srv.PushAccountJWT(tc.token()))
+		})
+	}
+}
+
+func (s *OperatorPublicTestSuite) TestServes() {
+	s.start()
+
+	nc, user, err := s.connect("APP")
+	s.Require().NoError(err)
+	closed := make(chan struct{})
+	nc.SetClosedHandler(func(*nats.Conn) { close(closed) })
+
+	// Revoking the user disconnects its client.
+	s.Require().NoError(s.srv.UpdateAccountJWT(
+		"APP",
+		func(claims *jwt.AccountClaims) {
+			claims.Revoke(user)
+		},
+	))
+	s.waitClosed(closed)
+
+	// Revoking the account disconnects its clients and refuses new ones.
+	nc, _, err = s.connect("APP")
+	s.Require().NoError(err)
+	closed = make(chan struct{})
+	nc.SetClosedHandler(func(*nats.Conn) { close(closed) })
+	s.Require().NoError(s.srv.RevokeAccountJWT("APP"))
+	s.waitClosed(closed)
+	_, _, err = s.connect("APP")
+	s.Error(err)
+
+	s.Require().NoError(s.srv.UpdateAccountJWT(
+		"APP",
+		func(claims *jwt.AccountClaims) {
+			claims.Limits.Conn = jwt.NoLimit
+			claims.Limits.LeafNodeConn = jwt.NoLimit
+		},
+	))
+	nc, _, err = s.connect("APP")
+	s.Require().NoError(err)
+	nc.Close()
+}
+
+func (s *OperatorPublicTestSuite) TestReload() {
+	tests := []struct {
+		name         string
+		change       func(opts *server.Options)
+		validateFunc func(err error)
+	}{
+		{
+			name: "keeps operator mode",
+			change: func(opts *server.Options) {
+				opts.MaxPayload = 1 << 20
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Require().NoError(s.srv.UpdateAccountJWT(
+					"APP",
+					func(*jwt.AccountClaims) {},
+				))
+				nc, _, err := s.connect("APP")
+				s.Require().NoError(err)
+				nc.Close()
+			},
+		},
+		{
+			name: "refuses operator change",
+			change: func(opts *server.Options) {
+				opts.Operator = &server.Operator{Name: "other"}
+			},
+			validateFunc: func(err error) {
+				var reloadErr *server.ReloadError
+				s.Require().ErrorAs(err, &reloadErr)
+				s.Equal([]string{"Operator"}, reloadErr.Fields)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.start()
+			newOpts := *s.srv.Opts
+			newOpts.Options = s.srv.Opts.Clone()
+			tc.change(&newOpts)
+
+			tc.validateFunc(s.srv.Reload(&newOpts))
+		})
+	}
+}
+
+// newServer returns a server in operator mode with operator, listening
+// on a free port.
+func (s *OperatorPublicTestSuite) newServer(
+	operator *server.Operator,
+) *server.Server {
+	return server.New(
+		slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)),
+		&server.Options{
+			Options: &natsserver.Options{
+				Host:   "127.0.0.1",
+				Port:   freePort(s.T()),
+				NoSigs: true,
+			},
+			ReadyTimeout: 5 * time.Second,
+			Operator:     operator,
+		},
+	)
+}
+
+// start starts the server.
+func (s *OperatorPublicTestSuite) start() {
+	s.Require().NoError(s.srv.Start())
+}
+
+// startInDir starts the server with its keys kept in a directory, whose
+// operator key can sign account JWTs.
+func (s *OperatorPublicTestSuite) startInDir() {
+	s.srv.Opts.Operator.Dir = s.T().TempDir()
+	s.start()
+}
+
+// claims returns the claims of the account name.
+func (s *OperatorPublicTestSuite) claims(
+	name string,
+) *jwt.AccountClaims {
+	token, err := s.srv.AccountJWT(name)
+	s.Require().NoError(err)
+	claims, err := jwt.DecodeAccountClaims(token)
+	s.Require().NoError(err)
+
+	return claims
+}
+
+// sign returns claims signed with the operator key in Operator.Dir.
+func (s *OperatorPublicTestSuite) sign(
+	claims *jwt.AccountClaims,
+) string {
+	key, err := nkeys.FromSeed(
+		[]byte(s.readFile(s.srv.Opts.Operator.Dir, "operator.nk")),
+	)
+	s.Require().NoError(err)
+	token, err := claims.Encode(key)
+	s.Require().NoError(err)
+
+	return token
+}
+
+// connect connects to the server as a new user of the account name,
+// signed with a signing key added to the account, returning the user's
+// public key.
+func (s *OperatorPublicTestSuite) connect(
+	name string,
+) (*nats.Conn, string, error) {
+	signer, err := nkeys.CreateAccount()
+	s.Require().NoError(err)
+	signerPub, err := signer.PublicKey()
+	s.Require().NoError(err)
+	s.Require().NoError(s.srv.UpdateAccountJWT(
+		name,
+		func(claims *jwt.AccountClaims) {
+			claims.SigningKeys.Add(signerPub)
+		},
+	))
+
+	return s.dial(signer, s.claims(name).Subject)
+}
+
+// connectAs connects to the server as a new user signed with the key of
+// its account.
+func (s *OperatorPublicTestSuite) connectAs(
+	key nkeys.KeyPair,
+) (*nats.Conn, error) {
+	nc, _, err := s.dial(key, "")
+
+	return nc, err
+}
+
+// dial connects to the server as a new user signed by signer, on behalf
+// of the account issuer when set.
+func (s *OperatorPublicTestSuite) dial(
+	signer nkeys.KeyPair,
+	issuer string,
+) (*nats.Conn, string, error) {
+	user, err := nkeys.CreateUser()
+	s.Require().NoError(err)
+	pub, err := user.PublicKey()
+	s.Require().NoError(err)
+	seed, err := user.Seed()
+	s.Require().NoError(err)
+
+	claims := jwt.NewUserClaims(pub)
+	claims.IssuerAccount = issuer
+	token, err := claims.Encode(signer)
+	s.Require().NoError(err)
+
+	nc, err := nats.Connect(
+		fmt.Sprintf("nats://127.0.0.1:%d", s.srv.Opts.Port),
+		nats.UserJWTAndSeed(token, string(seed)),
+		nats.NoReconnect(),
+	)
+
+	return nc, pub, err
+}
+
+// waitClosed waits for a connection to be closed by the server.
+func (s *OperatorPublicTestSuite) waitClosed(
+	closed <-chan struct{},
+) {
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		s.Fail("connection not closed")
+	}
+}
+
+// readFile returns the contents of the file name in dir.
+func (s *OperatorPublicTestSuite) readFile(
+	dir string,
+	name string,
+) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	s.Require().NoError(err)
+
+	return string(data)
+}
+
+// writeFile writes data to the file name in dir, returning its path.
+func (s *OperatorPublicTestSuite) writeFile(
+	dir string,
+	name string,
+	data string,
+) string {
+	path := filepath.Join(dir, name)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o700))
+	s.Require().NoError(os.WriteFile(path, []byte(data), 0o600))
+
+	return path
+}
+
+// writeSeed writes the seed of key to the file name in dir.
+func (s *OperatorPublicTestSuite) writeSeed(
+	dir string,
+	name string,
+	key nkeys.KeyPair,
+) {
+	seed, err := key.Seed()
+	s.Require().NoError(err)
+	s.writeFile(dir, name, string(seed))
+}
+
+// writeAccount writes the JWT of a new account name signed by operator to
+// dir.
+func (s *OperatorPublicTestSuite) writeAccount(
+	dir string,
+	name string,
+	operator nkeys.KeyPair,
+) {
+	key, err := nkeys.CreateAccount()
+	s.Require().NoError(err)
+	pub, err := key.PublicKey()
+	s.Require().NoError(err)
+	token, err := jwt.NewAccountClaims(pub).Encode(operator)
+	s.Require().NoError(err)
+	s.writeFile(dir, "accounts/"+name+".jwt", token)
+}
+
+// symlinkMissing makes the file name in dir a link to a file in a missing
+// directory, which reads as missing but cannot be written.
+func (s *OperatorPublicTestSuite) symlinkMissing(
+	dir string,
+	name string,
+) {
+	path := filepath.Join(dir, name)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o700))
+	s.Require().NoError(os.Symlink(
+		filepath.Join(dir, "missing", "file"),
+		path,
+	))
+}
+
+// mkdir creates the directory name in dir.
+func (s *OperatorPublicTestSuite) mkdir(
+	dir string,
+	name string,
+) {
+	s.Require().NoError(os.MkdirAll(filepath.Join(dir, name), 0o700))
+}
+
+// createFailed checks that err is a start failing to create the NATS
+// server, containing msg.
+func (s *OperatorPublicTestSuite) createFailed(
+	err error,
+	msg string,
+) {
+	var startErr *server.StartError
+	s.Require().ErrorAs(err, &startErr)
+	s.Equal(server.StartPhaseCreate, startErr.Phase)
+	s.ErrorContains(err, msg)
+	s.Empty(s.started)
+}
+
+func TestOperatorPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(OperatorPublicTestSuite))
+}
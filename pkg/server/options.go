@@ -0,0 +1,216 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+)
+
+// NewWithOptions creates a new embedded NATS server from DefaultOptions
+// with opts applied in order.
+func NewWithOptions(
+	logger *slog.Logger,
+	opts ...Option,
+) *Server {
+	o := DefaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return New(logger, o)
+}
+
+// DefaultOptions returns options suited to an embedded server: listening
+// on the loopback interface on a random port, leaving signal handling to
+// the application, with bounded JetStream storage should it be enabled.
+func DefaultOptions() *Options {
+	return &Options{
+		Options: &natsserver.Options{
+			Host:               "127.0.0.1",
+			Port:               natsserver.RANDOM_PORT,
+			NoSigs:             true,
+			JetStreamMaxMemory: DefaultJetStreamMaxMemory,
+			JetStreamMaxStore:  DefaultJetStreamMaxStore,
+		},
+		ReadyTimeout: DefaultReadyTimeout,
+	}
+}
+
+// WithHost sets the host the server listens on for clients.
+func WithHost(
+	host string,
+) Option {
+	return func(o *Options) {
+		o.Host = host
+	}
+}
+
+// WithPort sets the port the server listens on for clients. Use
+// natsserver.RANDOM_PORT to have one picked.
+func WithPort(
+	port int,
+) Option {
+	return func(o *Options) {
+		o.Port = port
+	}
+}
+
+// WithServerName sets the name the server identifies itself with.
+func WithServerName(
+	name string,
+) Option {
+	return func(o *Options) {
+		o.ServerName = name
+	}
+}
+
+// WithDebug enables debug logging and, with trace, protocol tracing.
+func WithDebug(
+	trace bool,
+) Option {
+	return func(o *Options) {
+		o.Debug = true
+		o.Trace = trace
+	}
+}
+
+// WithJetStream enables JetStream, storing its data in storeDir.
+func WithJetStream(
+	storeDir string,
+) Option {
+	return func(o *Options) {
+		o.JetStream = true
+		o.StoreDir = storeDir
+	}
+}
+
+// WithJetStreamLimits sets the memory and file storage limits of
+// JetStream, in bytes. A negative limit leaves it to the NATS server.
+func WithJetStreamLimits(
+	maxMemory int64,
+	maxStore int64,
+) Option {
+	return func(o *Options) {
+		o.JetStreamMaxMemory = maxMemory
+		o.JetStreamMaxStore = maxStore
+	}
+}
+
+// WithReadyTimeout sets how long Start waits for the server to be ready
+// for connections.
+func WithReadyTimeout(
+	timeout time.Duration,
+) Option {
+	return func(o *Options) {
+		o.ReadyTimeout = timeout
+	}
+}
+
+// WithUsers adds users authenticating with a username and password.
+func WithUsers(
+	users ...*natsserver.User,
+) Option {
+	return func(o *Options) {
+		o.Users = append(o.Users, users...)
+	}
+}
+
+// WithNkeys adds users authenticating with an nkey.
+func WithNkeys(
+	users ...*natsserver.NkeyUser,
+) Option {
+	return func(o *Options) {
+		o.Nkeys = append(o.Nkeys, users...)
+	}
+}
+
+// WithAccounts adds accounts for users to be bound to.
+func WithAccounts(
+	accounts ...*natsserver.Account,
+) Option {
+	return func(o *Options) {
+		o.Accounts = append(o.Accounts, accounts...)
+	}
+}
+
+// WithSystemAccount sets the name of the system account.
+func WithSystemAccount(
+	name string,
+) Option {
+	return func(o *Options) {
+		o.SystemAccount = name
+	}
+}
+
+// WithTLS secures client connections with config. Clients must present a
+// verified certificate when config requires one. A nil config is reported
+// by Validate.
+func WithTLS(
+	config *tls.Config,
+) Option {
+	return func(o *Options) {
+		o.TLS = true
+		o.TLSConfig = config
+		o.TLSVerify = config != nil &&
+			config.ClientAuth == tls.RequireAndVerifyClientCert
+	}
+}
+
+// WithDevTLS secures client connections with certificates generated for
+// hosts, or "localhost" and the loopback addresses when none are given;
+// see DevTLS.
+func WithDevTLS(
+	hosts ...string,
+) Option {
+	return func(o *Options) {
+		o.DevTLS = &DevTLS{Hosts: hosts}
+	}
+}
+
+// WithDrainOnStop sets Options.DrainOnStop.
+func WithDrainOnStop() Option {
+	return func(o *Options) {
+		o.DrainOnStop = true
+	}
+}
+
+// WithAllowRestart sets Options.AllowRestart.
+func WithAllowRestart() Option {
+	return func(o *Options) {
+		o.AllowRestart = true
+	}
+}
+
+// WithHooks adds the hooks in hooks to those already set, after them.
+func WithHooks(
+	hooks Hooks,
+) Option {
+	return func(o *Options) {
+		o.Hooks.OnStarting = append(o.Hooks.OnStarting, hooks.OnStarting...)
+		o.Hooks.OnReady = append(o.Hooks.OnReady, hooks.OnReady...)
+		o.Hooks.OnStopping = append(o.Hooks.OnStopping, hooks.OnStopping...)
+		o.Hooks.OnStopped = append(o.Hooks.OnStopped, hooks.OnStopped...)
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+type OptionsPublicTestSuite struct {
+	suite.Suite
+
+	logger *slog.Logger
+}
+
+func (s *OptionsPublicTestSuite) SetupTest() {
+	s.logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+}
+
+// apply returns DefaultOptions with opts applied.
+func apply(
+	opts ...server.Option,
+) *server.Options {
+	o := server.DefaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+func (s *OptionsPublicTestSuite) TestNewWithOptions() {
+	tests := []struct {
+		name         string
+		opts         []server.Option
+		validateFunc func(srv *server.Server)
+	}{
+		{
+			name: "uses defaults without options",
+			validateFunc: func(srv *server.Server) {
+				s.Equal(server.DefaultOptions(), srv.Opts)
+				s.Equal(server.StateNew, srv.State())
+			},
+		},
+		{
+			name: "applies options in order",
+			opts: []server.Option{
+				server.WithPort(4222),
+				server.WithReadyTimeout(time.Second),
+				server.WithPort(4223),
+			},
+			validateFunc: func(srv *server.Server) {
+				s.Equal(4223, srv.Opts.Port)
+				s.Equal(time.Second, srv.Opts.ReadyTimeout)
+				s.True(srv.Opts.NoSigs)
+			},
+		},
+		{
+			name: "starts nats server with defaults",
+			opts: []server.Option{
+				server.WithJetStream(s.T().TempDir()),
+			},
+			validateFunc: func(srv *server.Server) {
+				s.Require().NoError(srv.Start())
+				defer srv.Stop()
+
+				s.Equal(server.StateRunning, srv.State())
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(server.NewWithOptions(s.logger, tc.opts...))
+		})
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestDefaultOptions() {
+	opts := server.DefaultOptions()
+
+	s.Equal("127.0.0.1", opts.Host)
+	s.Equal(natsserver.RANDOM_PORT, opts.Port)
+	s.True(opts.NoSigs)
+	s.False(opts.JetStream)
+	s.EqualValues(server.DefaultJetStreamMaxMemory, opts.JetStreamMaxMemory)
+	s.EqualValues(server.DefaultJetStreamMaxStore, opts.JetStreamMaxStore)
+	s.Equal(server.DefaultReadyTimeout, opts.ReadyTimeout)
+	s.NoError(opts.Validate())
+	s.NotSame(opts.Options, server.DefaultOptions().Options)
+}
+
+func (s *OptionsPublicTestSuite) TestWithHost() {
+	s.Equal("0.0.0.0", apply(server.WithHost("0.0.0.0")).Host)
+}
+
+func (s *OptionsPublicTestSuite) TestWithPort() {
+	s.Equal(4222, apply(server.WithPort(4222)).Port)
+}
+
+func (s *OptionsPublicTestSuite) TestWithServerName() {
+	s.Equal("embedded", apply(server.WithServerName("embedded")).ServerName)
+}
+
+func (s *OptionsPublicTestSuite) TestWithDebug() {
+	tests := []struct {
+		name  string
+		trace bool
+	}{
+		{
+			name:  "enables debug logging",
+			trace: false,
+		},
+		{
+			name:  "enables debug logging and tracing",
+			trace: true,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			opts := apply(server.WithDebug(tc.trace))
+
+			s.True(opts.Debug)
+			s.Equal(tc.trace, opts.Trace)
+		})
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestWithJetStream() {
+	opts := apply(server.WithJetStream(".nats/jetstream/"))
+
+	s.True(opts.JetStream)
+	s.Equal(".nats/jetstream/", opts.StoreDir)
+}
+
+func (s *OptionsPublicTestSuite) TestWithJetStreamLimits() {
+	opts := apply(server.WithJetStreamLimits(1<<20, -1))
+
+	s.EqualValues(1<<20, opts.JetStreamMaxMemory)
+	s.EqualValues(-1, opts.JetStreamMaxStore)
+}
+
+func (s *OptionsPublicTestSuite) TestWithReadyTimeout() {
+	s.Equal(time.Minute, apply(server.WithReadyTimeout(time.Minute)).ReadyTimeout)
+}
+
+func (s *OptionsPublicTestSuite) TestWithUsers() {
+	first := &natsserver.User{Username: "first"}
+	second := &natsserver.User{Username: "second"}
+
+	opts := apply(server.WithUsers(first), server.WithUsers(second))
+
+	s.Equal([]*natsserver.User{first, second}, opts.Users)
+}
+
+func (s *OptionsPublicTestSuite) TestWithNkeys() {
+	first := &natsserver.NkeyUser{Nkey: nkeyService1}
+	second := &natsserver.NkeyUser{Nkey: nkeyService2}
+
+	opts := apply(server.WithNkeys(first), server.WithNkeys(second))
+
+	s.Equal([]*natsserver.NkeyUser{first, second}, opts.Nkeys)
+}
+
+func (s *OptionsPublicTestSuite) TestWithAccounts() {
+	app := natsserver.NewAccount("APP")
+
+	s.Equal(
+		[]*natsserver.Account{app},
+		apply(server.WithAccounts(app)).Accounts,
+	)
+}
+
+func (s *OptionsPublicTestSuite) TestWithSystemAccount() {
+	s.Equal("SYS", apply(server.WithSystemAccount("SYS")).SystemAccount)
+}
+
+func (s *OptionsPublicTestSuite) TestWithTLS() {
+	tests := []struct {
+		name           string
+		config         *tls.Config
+		expectedVerify bool
+	}{
+		{
+			name:           "secures client connections",
+			config:         &tls.Config{MinVersion: tls.VersionTLS12},
+			expectedVerify: false,
+		},
+		{
+			name: "verifies client certificates when required",
+			config: &tls.Config{
+				MinVersion: tls.VersionTLS12,
+				ClientAuth: tls.RequireAndVerifyClientCert,
+			},
+			expectedVerify: true,
+		},
+		{
+			name:           "leaves nil config for validation",
+			config:         nil,
+			expectedVerify: false,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			opts := apply(server.WithTLS(tc.config))
+
+			s.True(opts.TLS)
+			s.Same(tc.config, opts.TLSConfig)
+			s.Equal(tc.expectedVerify, opts.TLSVerify)
+		})
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestWithDevTLS() {
+	tests := []struct {
+		name          string
+		hosts         []string
+		expectedHosts []string
+	}{
+		{
+			name:          "generates certificates for hosts",
+			hosts:         []string{"nats.local", "10.0.0.1"},
+			expectedHosts: []string{"nats.local", "10.0.0.1"},
+		},
+		{
+			name:          "defaults to the loopback hosts",
+			expectedHosts: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			opts := apply(server.WithDevTLS(tc.hosts...))
+
+			s.Require().NotNil(opts.DevTLS)
+			s.Equal(tc.expectedHosts, opts.DevTLS.Hosts)
+			s.False(opts.DevTLS.Verify)
+		})
+	}
+}
+
+func (s *OptionsPublicTestSuite) TestWithDrainOnStop() {
+	s.True(apply(server.WithDrainOnStop()).DrainOnStop)
+}
+
+func (s *OptionsPublicTestSuite) TestWithAllowRestart() {
+	s.True(apply(server.WithAllowRestart()).AllowRestart)
+}
+
+func (s *OptionsPublicTestSuite) TestWithHooks() {
+	hook := func(name string) server.Hook {
+		return server.Hook{
+			Name: name,
+			Fn:   func(context.Context, *server.Server) error { return nil },
+		}
+	}
+
+	opts := apply(
+		server.WithHooks(server.Hooks{
+			OnStarting: []server.Hook{hook("starting")},
+			OnReady:    []server.Hook{hook("first")},
+		}),
+		server.WithHooks(server.Hooks{
+			OnReady:    []server.Hook{hook("second")},
+			OnStopping: []server.Hook{hook("stopping")},
+			OnStopped:  []server.Hook{hook("stopped")},
+		}),
+	)
+
+	names := func(hooks []server.Hook) []string {
+		var result []string
+		for _, h := range hooks {
+			result = append(result, h.Name)
+		}
+		return result
+	}
+
+	s.Equal([]string{"starting"}, names(opts.Hooks.OnStarting))
+	s.Equal([]string{"first", "second"}, names(opts.Hooks.OnReady))
+	s.Equal([]string{"stopping"}, names(opts.Hooks.OnStopping))
+	s.Equal([]string{"stopped"}, names(opts.Hooks.OnStopped))
+}
+
+func TestOptionsPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(OptionsPublicTestSuite))
+}
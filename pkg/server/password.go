@@ -0,0 +1,126 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HashPassword returns password bcrypt hashed with cost, or with
+// DefaultPasswordCost when cost is zero. The NATS server accepts the hash
+// wherever it accepts the password, comparing clients' passwords to it.
+func HashPassword(
+	password string,
+	cost int,
+) (string, error) {
+	if cost == 0 {
+		cost = DefaultPasswordCost
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", fmt.Errorf("error hashing password: %w", err)
+	}
+
+	return string(hash), nil
+}
+
+// IsHashedPassword reports whether password is a bcrypt hash.
+func IsHashedPassword(
+	password string,
+) bool {
+	_, err := bcrypt.Cost([]byte(password))
+
+	return err == nil
+}
+
+// HashPasswords replaces the password of each of users that is not yet
+// hashed with its bcrypt hash, as HashPassword does with cost.
+func HashPasswords(
+	users []*natsserver.User,
+	cost int,
+) error {
+	for _, user := range users {
+		if IsHashedPassword(user.Password) {
+			continue
+		}
+
+		hash, err := HashPassword(user.Password, cost)
+		if err != nil {
+			return fmt.Errorf("user %q: %w", user.Username, err)
+		}
+		user.Password = hash
+	}
+
+	return nil
+}
+
+// checkHashedPasswords returns an error wrapping ErrPlaintextPassword that
+// names every user of natsOpts, the options the NATS server is given, whose
+// password is not hashed, when RequireHashedPasswords is set.
+func (o *Options) checkHashedPasswords(
+	natsOpts *natsserver.Options,
+) error {
+	if !o.RequireHashedPasswords {
+		return nil
+	}
+
+	var plaintext []string
+	if natsOpts.Username != "" && !IsHashedPassword(natsOpts.Password) {
+		plaintext = append(plaintext, natsOpts.Username)
+	}
+	for _, user := range natsOpts.Users {
+		if !IsHashedPassword(user.Password) {
+			plaintext = append(plaintext, user.Username)
+		}
+	}
+
+	if len(plaintext) > 0 {
+		return fmt.Errorf(
+			"%w for users: %s",
+			ErrPlaintextPassword,
+			strings.Join(plaintext, ", "),
+		)
+	}
+
+	return nil
+}
+
+// comparePassword reports whether given matches password, which may be a
+// bcrypt hash. Plain passwords are compared in constant time.
+func comparePassword(
+	password string,
+	given string,
+) bool {
+	if IsHashedPassword(password) {
+		return bcrypt.CompareHashAndPassword(
+			[]byte(password),
+			[]byte(given),
+		) == nil
+	}
+
+	return subtle.ConstantTimeCompare([]byte(password), []byte(given)) == 1
+}
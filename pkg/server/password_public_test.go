@@ -0,0 +1,310 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+type PasswordPublicTestSuite struct {
+	suite.Suite
+
+	mockCtrl       *gomock.Controller
+	mockNATSServer *mocks.MockNATSServerInstance
+	srv            *server.Server
+	started        []*natsserver.Options
+	terminate      func()
+	newNATSServer  func(*natsserver.Options) (server.NATSServerInstance, error)
+	hash           string
+}
+
+func (s *PasswordPublicTestSuite) SetupSuite() {
+	s.newNATSServer = server.NewNATSServer
+
+	hash, err := server.HashPassword("s3cr3t", bcrypt.MinCost)
+	s.Require().NoError(err)
+	s.hash = hash
+}
+
+func (s *PasswordPublicTestSuite) TearDownSuite() {
+	server.NewNATSServer = s.newNATSServer
+}
+
+func (s *PasswordPublicTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockNATSServer = mocks.NewMockNATSServerInstance(s.mockCtrl)
+	s.terminate = expectWaitForShutdown(s.mockNATSServer)
+	s.started = nil
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		&server.Options{
+			Options:                &natsserver.Options{},
+			ReadyTimeout:           5 * time.Second,
+			RequireHashedPasswords: true,
+		},
+	)
+
+	server.NewNATSServer = func(
+		opts *natsserver.Options,
+	) (server.NATSServerInstance, error) {
+		s.started = append(s.started, opts)
+		return s.mockNATSServer, nil
+	}
+}
+
+func (s *PasswordPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *PasswordPublicTestSuite) TearDownSubTest() {
+	s.terminate()
+}
+
+func (s *PasswordPublicTestSuite) TearDownTest() {
+	s.terminate()
+	s.mockCtrl.Finish()
+}
+
+func (s *PasswordPublicTestSuite) TestHashPassword() {
+	tests := []struct {
+		name         string
+		password     string
+		cost         int
+		validateFunc func(hash string, err error)
+	}{
+		{
+			name:     "hashes with cost",
+			password: "s3cr3t",
+			cost:     bcrypt.MinCost,
+			validateFunc: func(hash string, err error) {
+				s.Require().NoError(err)
+				s.NoError(bcrypt.CompareHashAndPassword(
+					[]byte(hash),
+					[]byte("s3cr3t"),
+				))
+				cost, err := bcrypt.Cost([]byte(hash))
+				s.Require().NoError(err)
+				s.Equal(bcrypt.MinCost, cost)
+			},
+		},
+		{
+			name:     "hashes with default cost",
+			password: "s3cr3t",
+			validateFunc: func(hash string, err error) {
+				s.Require().NoError(err)
+				cost, err := bcrypt.Cost([]byte(hash))
+				s.Require().NoError(err)
+				s.Equal(server.DefaultPasswordCost, cost)
+			},
+		},
+		{
+			name:     "returns error when password is too long",
+			password: strings.Repeat("x", 73),
+			cost:     bcrypt.MinCost,
+			validateFunc: func(hash string, err error) {
+				s.Empty(hash)
+				s.ErrorIs(err, bcrypt.ErrPasswordTooLong)
+				s.ErrorContains(err, "error hashing password: ")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(server.HashPassword(tc.password, tc.cost))
+		})
+	}
+}
+
+func (s *PasswordPublicTestSuite) TestIsHashedPassword() {
+	tests := []struct {
+		name     string
+		password func() string
+		expected bool
+	}{
+		{
+			name:     "reports hash",
+			password: func() string { return s.hash },
+			expected: true,
+		},
+		{
+			name:     "reports plaintext",
+			password: func() string { return "s3cr3t" },
+		},
+		{
+			name:     "reports truncated hash",
+			password: func() string { return s.hash[:20] },
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.Equal(tc.expected, server.IsHashedPassword(tc.password()))
+		})
+	}
+}
+
+func (s *PasswordPublicTestSuite) TestHashPasswords() {
+	tests := []struct {
+		name         string
+		users        func() []*natsserver.User
+		validateFunc func(users []*natsserver.User, err error)
+	}{
+		{
+			name: "hashes plaintext passwords",
+			users: func() []*natsserver.User {
+				return []*natsserver.User{
+					{Username: "app", Password: "s3cr3t"},
+					{Username: "ops", Password: s.hash},
+				}
+			},
+			validateFunc: func(users []*natsserver.User, err error) {
+				s.Require().NoError(err)
+				s.True(server.IsHashedPassword(users[0].Password))
+				s.NoError(bcrypt.CompareHashAndPassword(
+					[]byte(users[0].Password),
+					[]byte("s3cr3t"),
+				))
+				s.Equal(s.hash, users[1].Password)
+			},
+		},
+		{
+			name: "returns error naming user",
+			users: func() []*natsserver.User {
+				return []*natsserver.User{
+					{Username: "app", Password: strings.Repeat("x", 73)},
+				}
+			},
+			validateFunc: func(_ []*natsserver.User, err error) {
+				s.ErrorIs(err, bcrypt.ErrPasswordTooLong)
+				s.ErrorContains(err, `user "app": error hashing password`)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			users := tc.users()
+			err := server.HashPasswords(users, bcrypt.MinCost)
+			tc.validateFunc(users, err)
+		})
+	}
+}
+
+func (s *PasswordPublicTestSuite) TestStart() {
+	tests := []struct {
+		name         string
+		setup        func(opts *server.Options)
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name: "starts when passwords are hashed",
+			setup: func(opts *server.Options) {
+				opts.Username = "admin"
+				opts.Password = s.hash
+				opts.Users = []*natsserver.User{
+					{Username: "app", Password: s.hash},
+				}
+			},
+			mockSetup: func() { expectStart(s.mockNATSServer) },
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name: "checks passwords once secrets are resolved",
+			setup: func(opts *server.Options) {
+				s.T().Setenv("PASSWORD_TEST_HASH", s.hash)
+				opts.Users = []*natsserver.User{
+					{Username: "app", Password: "env://PASSWORD_TEST_HASH"},
+				}
+			},
+			mockSetup: func() { expectStart(s.mockNATSServer) },
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name: "starts with plaintext passwords when not required",
+			setup: func(opts *server.Options) {
+				opts.RequireHashedPasswords = false
+				opts.Users = []*natsserver.User{
+					{Username: "app", Password: "s3cr3t"},
+				}
+			},
+			mockSetup: func() { expectStart(s.mockNATSServer) },
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name: "refuses plaintext passwords",
+			setup: func(opts *server.Options) {
+				opts.Username = "admin"
+				opts.Password = "admin"
+				opts.Users = []*natsserver.User{
+					{Username: "app", Password: s.hash},
+					{Username: "ops", Password: "s3cr3t"},
+					{Username: "guest"},
+				}
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhaseCreate, startErr.Phase)
+				s.ErrorIs(err, server.ErrPlaintextPassword)
+				s.ErrorContains(
+					err,
+					"password not bcrypt hashed for users: admin, ops, guest",
+				)
+				s.NotContains(err.Error(), "s3cr3t")
+				s.Empty(s.started)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup(s.srv.Opts)
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.Start())
+		})
+	}
+}
+
+func TestPasswordPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(PasswordPublicTestSuite))
+}
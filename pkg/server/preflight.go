@@ -0,0 +1,131 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"syscall"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+)
+
+// storeLockFile is the file in the JetStream store directory locked for as
+// long as a server runs with it.
+const storeLockFile = ".nats-server.lock"
+
+// Error describes the listener that could not be opened.
+func (e *ListenerError) Error() string {
+	return fmt.Sprintf("%s listener on %s: %v", e.Listener, e.Address, e.Err)
+}
+
+// Unwrap returns the error from opening the listener.
+func (e *ListenerError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrPortInUse and the address was taken.
+func (e *ListenerError) Is(
+	target error,
+) bool {
+	return target == ErrPortInUse && errors.Is(e.Err, syscall.EADDRINUSE)
+}
+
+// preflight checks that the NATS server can be started with opts before it
+// is created, which would otherwise only log the failure and never become
+// ready. It returns the lock taken on the JetStream store directory, if
+// any, which must be held until the server has shut down.
+func preflight(
+	opts *natsserver.Options,
+) (*os.File, error) {
+	for _, l := range listeners(opts) {
+		if err := checkListener(l); err != nil {
+			return nil, err
+		}
+	}
+
+	if !opts.JetStream || opts.StoreDir == "" {
+		return nil, nil
+	}
+
+	return lockStoreDir(opts.StoreDir)
+}
+
+// listeners returns the listeners the NATS server opens on fixed ports,
+// with the client listener on natsserver.DEFAULT_PORT when Port is zero.
+// Ports picked at random cannot conflict and are left out, as are the
+// other listeners with a zero port, which the NATS server does not open.
+func listeners(
+	opts *natsserver.Options,
+) []listener {
+	httpHost := opts.HTTPHost
+	if httpHost == "" {
+		httpHost = opts.Host
+	}
+
+	var all []listener
+	if !opts.DontListen {
+		port := opts.Port
+		if port == 0 {
+			port = natsserver.DEFAULT_PORT
+		}
+		all = append(all, listener{"client", opts.Host, port})
+	}
+	all = append(
+		all,
+		listener{"monitoring", httpHost, opts.HTTPPort},
+		listener{"monitoring", httpHost, opts.HTTPSPort},
+		listener{"profiling", opts.Host, opts.ProfPort},
+		listener{"cluster", opts.Cluster.Host, opts.Cluster.Port},
+		listener{"gateway", opts.Gateway.Host, opts.Gateway.Port},
+		listener{"leafnode", opts.LeafNode.Host, opts.LeafNode.Port},
+		listener{"websocket", opts.Websocket.Host, opts.Websocket.Port},
+		listener{"mqtt", opts.MQTT.Host, opts.MQTT.Port},
+	)
+
+	return slices.DeleteFunc(
+		all,
+		func(l listener) bool { return l.port <= 0 },
+	)
+}
+
+// checkListener opens and closes l's address, returning a *ListenerError
+// when it cannot be listened on.
+func checkListener(
+	l listener,
+) error {
+	addr := net.JoinHostPort(l.host, strconv.Itoa(l.port))
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return &ListenerError{
+			Listener: l.name,
+			Address:  addr,
+			Err:      err,
+		}
+	}
+
+	return ln.Close()
+}
@@ -0,0 +1,392 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+type PreflightPublicTestSuite struct {
+	suite.Suite
+
+	mockCtrl       *gomock.Controller
+	mockNATSServer *mocks.MockNATSServerInstance
+	logger         *slog.Logger
+	srv            *server.Server
+	terminate      func()
+	newNATSServer  func(*natsserver.Options) (server.NATSServerInstance, error)
+}
+
+func (s *PreflightPublicTestSuite) SetupSuite() {
+	s.newNATSServer = server.NewNATSServer
+}
+
+func (s *PreflightPublicTestSuite) TearDownSuite() {
+	server.NewNATSServer = s.newNATSServer
+}
+
+func (s *PreflightPublicTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockNATSServer = mocks.NewMockNATSServerInstance(s.mockCtrl)
+	s.terminate = expectWaitForShutdown(s.mockNATSServer)
+	s.logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+	s.srv = s.newServer(&natsserver.Options{})
+
+	server.NewNATSServer = func(
+		_ *natsserver.Options,
+	) (server.NATSServerInstance, error) {
+		return s.mockNATSServer, nil
+	}
+}
+
+func (s *PreflightPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *PreflightPublicTestSuite) TearDownSubTest() {
+	s.terminate()
+}
+
+func (s *PreflightPublicTestSuite) TearDownTest() {
+	s.terminate()
+	s.mockCtrl.Finish()
+}
+
+func (s *PreflightPublicTestSuite) newServer(
+	opts *natsserver.Options,
+) *server.Server {
+	return server.New(s.logger, &server.Options{
+		Options:      opts,
+		ReadyTimeout: 5 * time.Second,
+	})
+}
+
+// occupy listens on a free local port until the test ends and returns it.
+func (s *PreflightPublicTestSuite) occupy() int {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = ln.Close() })
+
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// startOther starts another server with the JetStream store directory dir,
+// returning it running.
+func (s *PreflightPublicTestSuite) startOther(
+	dir string,
+) *server.Server {
+	other := s.newServer(&natsserver.Options{JetStream: true, StoreDir: dir})
+	expectStart(s.mockNATSServer)
+	s.Require().NoError(other.Start())
+
+	return other
+}
+
+func (s *PreflightPublicTestSuite) TestStart() {
+	tests := []struct {
+		name         string
+		opts         func() *natsserver.Options
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name: "starts when fixed ports are free",
+			opts: func() *natsserver.Options {
+				return &natsserver.Options{
+					Host:     "127.0.0.1",
+					Port:     freePort(s.T()),
+					HTTPPort: freePort(s.T()),
+					Cluster: natsserver.ClusterOpts{
+						Host: "127.0.0.1",
+						Port: freePort(s.T()),
+					},
+					JetStream: true,
+					StoreDir:  s.T().TempDir(),
+				}
+			},
+			mockSetup: func() { expectStart(s.mockNATSServer) },
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name: "skips client listener when not listening",
+			opts: func() *natsserver.Options {
+				return &natsserver.Options{
+					Host:       "127.0.0.1",
+					Port:       s.occupy(),
+					DontListen: true,
+				}
+			},
+			mockSetup: func() { expectStart(s.mockNATSServer) },
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name: "fails when client port is in use",
+			opts: func() *natsserver.Options {
+				return &natsserver.Options{
+					Host: "127.0.0.1",
+					Port: s.occupy(),
+				}
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrPortInUse)
+
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhasePreflight, startErr.Phase)
+
+				var listenerErr *server.ListenerError
+				s.Require().ErrorAs(err, &listenerErr)
+				s.Equal("client", listenerErr.Listener)
+				s.Equal(
+					net.JoinHostPort(s.srv.Opts.Host, strconv.Itoa(s.srv.Opts.Port)),
+					listenerErr.Address,
+				)
+				s.Equal(server.StateFailed, s.srv.State())
+			},
+		},
+		{
+			name: "fails when default client port is in use",
+			opts: func() *natsserver.Options {
+				// Another server may already hold the default port.
+				ln, err := net.Listen("tcp", ":4222")
+				if err == nil {
+					s.T().Cleanup(func() { _ = ln.Close() })
+				}
+
+				return &natsserver.Options{}
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrPortInUse)
+
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhasePreflight, startErr.Phase)
+
+				var listenerErr *server.ListenerError
+				s.Require().ErrorAs(err, &listenerErr)
+				s.Equal("client", listenerErr.Listener)
+				s.Equal(":4222", listenerErr.Address)
+			},
+		},
+		{
+			name: "names the listener whose port is in use",
+			opts: func() *natsserver.Options {
+				return &natsserver.Options{
+					Host: "127.0.0.1",
+					Port: -1,
+					LeafNode: natsserver.LeafNodeOpts{
+						Host: "127.0.0.1",
+						Port: s.occupy(),
+					},
+				}
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				var listenerErr *server.ListenerError
+				s.Require().ErrorAs(err, &listenerErr)
+				s.Equal("leafnode", listenerErr.Listener)
+				s.ErrorIs(err, server.ErrPortInUse)
+			},
+		},
+		{
+			name: "fails when address cannot be listened on",
+			opts: func() *natsserver.Options {
+				// An address reserved for documentation, assigned to no
+				// interface.
+				return &natsserver.Options{
+					Host: "192.0.2.1",
+					Port: freePort(s.T()),
+				}
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				var listenerErr *server.ListenerError
+				s.Require().ErrorAs(err, &listenerErr)
+				s.NotErrorIs(err, server.ErrPortInUse)
+			},
+		},
+		{
+			name: "fails when store directory is in use",
+			opts: func() *natsserver.Options {
+				dir := s.T().TempDir()
+				s.startOther(dir)
+
+				return &natsserver.Options{JetStream: true, StoreDir: dir}
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrStoreDirLocked)
+
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhasePreflight, startErr.Phase)
+			},
+		},
+		{
+			name: "starts once other server releases store directory",
+			opts: func() *natsserver.Options {
+				dir := s.T().TempDir()
+				other := s.startOther(dir)
+				s.mockNATSServer.EXPECT().Shutdown()
+				other.Stop()
+
+				return &natsserver.Options{JetStream: true, StoreDir: dir}
+			},
+			mockSetup: func() { expectStart(s.mockNATSServer) },
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name: "fails when store directory cannot be created",
+			opts: func() *natsserver.Options {
+				return &natsserver.Options{
+					JetStream: true,
+					StoreDir: filepath.Join(
+						s.T().TempDir(),
+						"file",
+						"jetstream",
+					),
+				}
+			},
+			mockSetup: func() {
+				// Validation passes, then a file takes the place of the
+				// store directory's parent.
+				s.srv.Opts.Hooks.OnStarting = []server.Hook{{
+					Name: "block store directory",
+					Fn: func(_ context.Context, _ *server.Server) error {
+						return os.WriteFile(
+							filepath.Dir(s.srv.Opts.StoreDir),
+							nil,
+							0o600,
+						)
+					},
+				}}
+			},
+			validateFunc: func(err error) {
+				s.ErrorContains(err, "error creating store directory")
+			},
+		},
+		{
+			name: "fails when store directory lock cannot be opened",
+			opts: func() *natsserver.Options {
+				dir := s.T().TempDir()
+				s.Require().NoError(
+					os.Mkdir(filepath.Join(dir, ".nats-server.lock"), 0o750),
+				)
+
+				return &natsserver.Options{JetStream: true, StoreDir: dir}
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorContains(err, "error opening store directory lock")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.srv = s.newServer(tc.opts())
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.Start())
+		})
+	}
+}
+
+func (s *PreflightPublicTestSuite) TestError() {
+	err := &server.ListenerError{
+		Listener: "cluster",
+		Address:  "127.0.0.1:6222",
+		Err:      errors.New("boom"),
+	}
+
+	s.EqualError(err, "cluster listener on 127.0.0.1:6222: boom")
+}
+
+func (s *PreflightPublicTestSuite) TestUnwrap() {
+	cause := errors.New("boom")
+	err := &server.ListenerError{Err: cause}
+
+	s.Same(cause, errors.Unwrap(err))
+}
+
+func (s *PreflightPublicTestSuite) TestIs() {
+	tests := []struct {
+		name     string
+		err      error
+		target   error
+		expected bool
+	}{
+		{
+			name:     "matches ErrPortInUse when address is taken",
+			err:      syscall.EADDRINUSE,
+			target:   server.ErrPortInUse,
+			expected: true,
+		},
+		{
+			name:     "does not match ErrPortInUse for other errors",
+			err:      syscall.EADDRNOTAVAIL,
+			target:   server.ErrPortInUse,
+			expected: false,
+		},
+		{
+			name:     "does not match other errors",
+			err:      syscall.EADDRINUSE,
+			target:   server.ErrNotReady,
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			err := &server.ListenerError{Err: tc.err}
+
+			s.Equal(tc.expected, errors.Is(err, tc.target))
+		})
+	}
+}
+
+func TestPreflightPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(PreflightPublicTestSuite))
+}
@@ -0,0 +1,550 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// The JetStream API error codes of the NATS server refusing a stream
+// update, which the jetstream package has no names for.
+const (
+	jsErrCodeStreamMirrorNotUpdatable   jetstream.ErrorCode = 10055
+	jsErrCodeStreamReplicasNotUpdatable jetstream.ErrorCode = 10061
+)
+
+// driftErrorCodes are the JetStream API error codes refusing an update
+// for changing a stream's mirror or replicas, which cannot be changed in
+// place. The other settings that cannot be changed share their error codes
+// with invalid configurations, so they are compared before updating; see
+// streamDrift and consumerDrift. Other errors updating an asset are
+// failures.
+var driftErrorCodes = map[jetstream.ErrorCode]bool{
+	jsErrCodeStreamMirrorNotUpdatable:   true,
+	jsErrCodeStreamReplicasNotUpdatable: true,
+}
+
+// ReconcileJetStream applies the migrations and creates and updates the
+// Options.JetStreamAssets of the running server, as Start does, returning
+// the assets that drifted. It applies assets and migrations changed by
+// Reload. A server that is not running returns ErrNotRunning, and one
+// that has not run with JetStreamAssets ErrNoJetStreamAssets.
+func (s *Server) ReconcileJetStream(
+	ctx context.Context,
+) ([]JetStreamDrift, error) {
+	// Reload configures how the run connects.
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	natsServer, r, err := s.runningJetStream()
+	if err != nil {
+		return nil, fmt.Errorf("error reconciling jetstream: %w", err)
+	}
+
+	return r.reconcile(ctx, natsServer, s.options().JetStreamAssets)
+}
+
+// runningJetStream returns the NATS server and JetStream provisioning of
+// the running server, with ErrNotRunning when it is not running and
+// ErrNoJetStreamAssets when it has no JetStreamAssets.
+func (s *Server) runningJetStream() (NATSServerInstance, *jetStreamRun, error) {
+	s.mu.Lock()
+	state, natsServer, r := s.state, s.natsServer, s.run.jetStream
+	s.mu.Unlock()
+
+	if state != StateRunning {
+		return nil, nil, ErrNotRunning
+	}
+
+	if r == nil {
+		return nil, nil, ErrNoJetStreamAssets
+	}
+
+	return natsServer, r, nil
+}
+
+// newJetStreamRun returns the JetStream provisioning of a run of the
+// server with opts, with a new password, or nil when opts has no
+// JetStreamAssets.
+func (s *Server) newJetStreamRun(
+	opts *Options,
+	operator *operatorRun,
+) *jetStreamRun {
+	if opts.JetStreamAssets == nil {
+		return nil
+	}
+
+	return &jetStreamRun{
+		server:     s,
+		account:    opts.JetStreamAssets.Account,
+		password:   rand.Text(),
+		operator:   operator,
+		clientAuth: opts.authenticator() != nil,
+	}
+}
+
+// configure decides how the run connects to the NATS server started with
+// natsOpts: in operator mode with a user it signs, with clientAuth as the
+// user it authenticates, with users as another user added to natsOpts, or
+// else with the single user or token of natsOpts, if any.
+func (r *jetStreamRun) configure(
+	natsOpts *natsserver.Options,
+) {
+	if r == nil {
+		return
+	}
+
+	r.credentials = nil
+	switch {
+	case r.operator != nil:
+	case r.clientAuth:
+		r.credentials = nats.UserInfo(jetStreamUser, r.password)
+	case len(natsOpts.Users) > 0 || len(natsOpts.Nkeys) > 0:
+		user := &natsserver.User{
+			Username: jetStreamUser,
+			Password: r.password,
+		}
+		i := slices.IndexFunc(
+			natsOpts.Accounts,
+			func(acc *natsserver.Account) bool {
+				return acc.Name == r.account
+			},
+		)
+		if i >= 0 {
+			user.Account = natsOpts.Accounts[i]
+		}
+		natsOpts.Users = append(natsOpts.Users, user)
+
+		if natsOpts.AuthCallout != nil {
+			// The auth callout settings may be shared with the options
+			// natsOpts was cloned from.
+			callout := *natsOpts.AuthCallout
+			callout.AuthUsers = append(
+				slices.Clip(callout.AuthUsers),
+				jetStreamUser,
+			)
+			natsOpts.AuthCallout = &callout
+		}
+		r.credentials = nats.UserInfo(jetStreamUser, r.password)
+	case natsOpts.Username != "":
+		r.credentials = nats.UserInfo(natsOpts.Username, natsOpts.Password)
+	case natsOpts.Authorization != "":
+		r.credentials = nats.Token(natsOpts.Authorization)
+	}
+}
+
+// authenticate returns how the run's user is bound when req is from it,
+// or nil when req is from another client.
+func (r *jetStreamRun) authenticate(
+	req *AuthRequest,
+) *AuthResult {
+	if r == nil || req.Username != jetStreamUser ||
+		subtle.ConstantTimeCompare(
+			[]byte(req.Password),
+			[]byte(r.password),
+		) != 1 {
+		return nil
+	}
+
+	return &AuthResult{
+		Account: r.account,
+		User:    jetStreamUser,
+	}
+}
+
+// reconcile applies the migrations of assets on natsServer, then creates
+// and updates assets, returning the assets that drifted, which are
+// logged. Assets are left alone while migrations are pending.
+func (r *jetStreamRun) reconcile(
+	ctx context.Context,
+	natsServer NATSServerInstance,
+	assets *JetStreamAssets,
+) ([]JetStreamDrift, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	conn, err := r.connect(natsServer)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Creating a JetStream context fails only for its options.
+	js, _ := jetstream.New(conn)
+
+	migrated, err := r.migrate(ctx, js, assets)
+	if err != nil || !migrated {
+		return nil, err
+	}
+
+	var drift []JetStreamDrift
+	for _, asset := range assets.assets(js) {
+		d, err := r.reconcileAsset(ctx, asset)
+		if err != nil {
+			return drift, err
+		}
+		if d != nil {
+			drift = append(drift, *d)
+		}
+	}
+
+	return drift, nil
+}
+
+// connect connects to natsServer in process as the run's user.
+func (r *jetStreamRun) connect(
+	natsServer NATSServerInstance,
+) (*nats.Conn, error) {
+	opts := []nats.Option{
+		nats.InProcessServer(natsServer),
+		nats.Name(jetStreamUser),
+		nats.NoReconnect(),
+	}
+
+	if r.operator != nil {
+		creds, err := r.operator.credentials(
+			r.account,
+			&UserJWT{Name: jetStreamUser},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting jetstream user: %w", err)
+		}
+		opts = append(opts, nats.UserJWTAndSeed(creds.JWT, string(creds.Seed)))
+	}
+
+	if r.credentials != nil {
+		opts = append(opts, r.credentials)
+	}
+
+	conn, err := nats.Connect("", opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting jetstream user: %w", err)
+	}
+
+	return conn, nil
+}
+
+// reconcileAsset creates asset when it does not exist and updates it
+// otherwise, returning its drift when the update changes what cannot be
+// changed in place.
+func (r *jetStreamRun) reconcileAsset(
+	ctx context.Context,
+	asset jetStreamAsset,
+) (*JetStreamDrift, error) {
+	logger := r.server.logger.With("kind", asset.kind, "name", asset.name)
+	drifted := func(reason string) *JetStreamDrift {
+		logger.Warn("jetstream asset drifted", "reason", reason)
+
+		return &JetStreamDrift{
+			Kind:   asset.kind,
+			Name:   asset.name,
+			Reason: reason,
+		}
+	}
+
+	before, err := asset.config(ctx)
+	switch {
+	case errors.Is(err, jetstream.ErrStreamNotFound),
+		errors.Is(err, jetstream.ErrConsumerNotFound):
+		if err := asset.create(ctx); err != nil {
+			return nil, fmt.Errorf(
+				"error creating jetstream %s %q: %w",
+				asset.kind,
+				asset.name,
+				err,
+			)
+		}
+		logger.Info("jetstream asset created")
+
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf(
+			"error looking up jetstream %s %q: %w",
+			asset.kind,
+			asset.name,
+			err,
+		)
+	}
+
+	if reason := asset.drift(before); reason != "" {
+		return drifted(reason), nil
+	}
+
+	after, err := asset.update(ctx)
+	var apiErr *jetstream.APIError
+	switch {
+	case errors.As(err, &apiErr) && driftErrorCodes[apiErr.ErrorCode]:
+		return drifted(apiErr.Description), nil
+	case err != nil:
+		return nil, fmt.Errorf(
+			"error updating jetstream %s %q: %w",
+			asset.kind,
+			asset.name,
+			err,
+		)
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		logger.Info("jetstream asset updated")
+	}
+
+	return nil, nil
+}
+
+// assets returns the assets, managed through js, in the order they are
+// reconciled.
+func (a *JetStreamAssets) assets(
+	js jetstream.JetStream,
+) []jetStreamAsset {
+	var assets []jetStreamAsset
+	for _, cfg := range a.Streams {
+		assets = append(assets, jetStreamAsset{
+			kind: "stream",
+			name: cfg.Name,
+			config: func(ctx context.Context) (any, error) {
+				return streamConfig(ctx, js, cfg.Name)
+			},
+			create: func(ctx context.Context) error {
+				_, err := js.CreateStream(ctx, cfg)
+				return err
+			},
+			update: func(ctx context.Context) (any, error) {
+				stream, err := js.UpdateStream(ctx, cfg)
+				if err != nil {
+					return nil, err
+				}
+
+				return stream.CachedInfo().Config, nil
+			},
+			drift: func(existing any) string {
+				return streamDrift(existing.(jetstream.StreamConfig), cfg)
+			},
+		})
+	}
+
+	for _, cfg := range a.KeyValues {
+		stream := "KV_" + cfg.Bucket
+		assets = append(assets, jetStreamAsset{
+			kind: "key-value bucket",
+			name: cfg.Bucket,
+			config: func(ctx context.Context) (any, error) {
+				return streamConfig(ctx, js, stream)
+			},
+			create: func(ctx context.Context) error {
+				_, err := js.CreateKeyValue(ctx, cfg)
+				return err
+			},
+			update: func(ctx context.Context) (any, error) {
+				if _, err := js.UpdateKeyValue(ctx, cfg); err != nil {
+					return nil, err
+				}
+
+				return streamConfig(ctx, js, stream)
+			},
+			drift: func(existing any) string {
+				return storageDrift(existing.(jetstream.StreamConfig), cfg.Storage)
+			},
+		})
+	}
+
+	for _, cfg := range a.ObjectStores {
+		stream := "OBJ_" + cfg.Bucket
+		assets = append(assets, jetStreamAsset{
+			kind: "object store",
+			name: cfg.Bucket,
+			config: func(ctx context.Context) (any, error) {
+				return streamConfig(ctx, js, stream)
+			},
+			create: func(ctx context.Context) error {
+				_, err := js.CreateObjectStore(ctx, cfg)
+				return err
+			},
+			update: func(ctx context.Context) (any, error) {
+				if _, err := js.UpdateObjectStore(ctx, cfg); err != nil {
+					return nil, err
+				}
+
+				return streamConfig(ctx, js, stream)
+			},
+			drift: func(existing any) string {
+				return storageDrift(existing.(jetstream.StreamConfig), cfg.Storage)
+			},
+		})
+	}
+
+	for _, c := range a.Consumers {
+		name := consumerName(c.Config)
+		assets = append(assets, jetStreamAsset{
+			kind: "consumer",
+			name: c.Stream + "/" + name,
+			config: func(ctx context.Context) (any, error) {
+				consumer, err := js.Consumer(ctx, c.Stream, name)
+				if err != nil {
+					return nil, err
+				}
+
+				return consumer.CachedInfo().Config, nil
+			},
+			create: func(ctx context.Context) error {
+				_, err := js.CreateConsumer(ctx, c.Stream, c.Config)
+				return err
+			},
+			update: func(ctx context.Context) (any, error) {
+				consumer, err := js.UpdateConsumer(ctx, c.Stream, c.Config)
+				if err != nil {
+					return nil, err
+				}
+
+				return consumer.CachedInfo().Config, nil
+			},
+			drift: func(existing any) string {
+				return consumerDrift(existing.(jetstream.ConsumerConfig), c.Config)
+			},
+		})
+	}
+
+	return assets
+}
+
+// streamConfig returns the configuration of the stream name.
+func streamConfig(
+	ctx context.Context,
+	js jetstream.JetStream,
+	name string,
+) (any, error) {
+	stream, err := js.Stream(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return stream.CachedInfo().Config, nil
+}
+
+// streamDrift returns why the stream configured as existing cannot be
+// updated to declared in place, or "" when it can as far as the settings
+// the NATS server refuses to change go. A change of mirror or replicas is
+// left to the NATS server to refuse; see driftErrorCodes.
+func streamDrift(
+	existing jetstream.StreamConfig,
+	declared jetstream.StreamConfig,
+) string {
+	workQueue := jetstream.WorkQueuePolicy
+	switch {
+	case existing.Storage != declared.Storage:
+		return "storage type can not be changed"
+	case existing.Retention != declared.Retention &&
+		(existing.Retention == workQueue || declared.Retention == workQueue):
+		return "retention policy can not be changed to or from work queue"
+	case existing.Sealed && !declared.Sealed:
+		return "sealed stream can not be unsealed"
+	case existing.DenyDelete && !declared.DenyDelete:
+		return "deny delete can not be cancelled"
+	case existing.DenyPurge && !declared.DenyPurge:
+		return "deny purge can not be cancelled"
+	case existing.AllowMsgTTL && !declared.AllowMsgTTL:
+		return "message TTLs can not be disabled"
+	case existing.AllowMsgCounter != declared.AllowMsgCounter:
+		return "message counter can not be changed"
+	case existing.AllowMsgSchedules && !declared.AllowMsgSchedules:
+		return "message schedules can not be disabled"
+	case existing.PersistMode != declared.PersistMode:
+		return "persist mode can not be changed"
+	}
+
+	return ""
+}
+
+// storageDrift returns why the stream of a key-value bucket or object
+// store configured as existing cannot be updated to storage, or "" when it
+// can.
+func storageDrift(
+	existing jetstream.StreamConfig,
+	storage jetstream.StorageType,
+) string {
+	if existing.Storage != storage {
+		return "storage type can not be changed"
+	}
+
+	return ""
+}
+
+// consumerDrift returns why the consumer configured as existing cannot be
+// updated to declared in place, or "" when it can. A declared MaxWaiting
+// of zero leaves the NATS server's default, so it is not compared.
+func consumerDrift(
+	existing jetstream.ConsumerConfig,
+	declared jetstream.ConsumerConfig,
+) string {
+	switch {
+	case existing.DeliverPolicy != declared.DeliverPolicy:
+		return "deliver policy can not be changed"
+	case existing.OptStartSeq != declared.OptStartSeq:
+		return "start sequence can not be changed"
+	case !equalTime(existing.OptStartTime, declared.OptStartTime):
+		return "start time can not be changed"
+	case existing.AckPolicy != declared.AckPolicy:
+		return "ack policy can not be changed"
+	case existing.ReplayPolicy != declared.ReplayPolicy:
+		return "replay policy can not be changed"
+	case existing.MemoryStorage != declared.MemoryStorage:
+		return "storage type can not be changed"
+	case declared.MaxWaiting != 0 && existing.MaxWaiting != declared.MaxWaiting:
+		return "max waiting can not be changed"
+	}
+
+	return ""
+}
+
+// equalTime reports whether a and b are both nil or the same time.
+func equalTime(
+	a *time.Time,
+	b *time.Time,
+) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+// consumerName returns the name cfg gives the consumer, its durable name
+// or else its name.
+func consumerName(
+	cfg jetstream.ConsumerConfig,
+) string {
+	if cfg.Durable != "" {
+		return cfg.Durable
+	}
+
+	return cfg.Name
+}
@@ -0,0 +1,513 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+)
+
+type ProvisionPublicTestSuite struct {
+	suite.Suite
+
+	srv  *server.Server
+	logs *syncBuffer
+}
+
+func (s *ProvisionPublicTestSuite) SetupTest() {
+	s.logs = &syncBuffer{}
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(s.logs, nil)),
+		&server.Options{
+			Options: &natsserver.Options{
+				Host:      "127.0.0.1",
+				Port:      freePort(s.T()),
+				NoSigs:    true,
+				JetStream: true,
+				StoreDir:  s.T().TempDir(),
+			},
+			ReadyTimeout: 5 * time.Second,
+			JetStreamAssets: &server.JetStreamAssets{
+				Streams: []jetstream.StreamConfig{{
+					Name:     "ORDERS",
+					Subjects: []string{"orders.>"},
+				}},
+				Consumers: []server.JetStreamConsumer{{
+					Stream: "ORDERS",
+					Config: jetstream.ConsumerConfig{
+						Durable:   "worker",
+						AckPolicy: jetstream.AckExplicitPolicy,
+					},
+				}},
+				KeyValues: []jetstream.KeyValueConfig{{
+					Bucket: "settings",
+				}},
+				ObjectStores: []jetstream.ObjectStoreConfig{{
+					Bucket: "files",
+				}},
+			},
+		},
+	)
+}
+
+func (s *ProvisionPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *ProvisionPublicTestSuite) TearDownSubTest() {
+	s.srv.Stop()
+}
+
+func (s *ProvisionPublicTestSuite) TearDownTest() {
+	s.srv.Stop()
+}
+
+func (s *ProvisionPublicTestSuite) TestStart() {
+	tests := []struct {
+		name         string
+		setup        func(opts *server.Options)
+		validateFunc func(err error)
+	}{
+		{
+			name:  "creates assets before ready",
+			setup: func(*server.Options) {},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				js := s.jetStream()
+				s.assertAssets(js)
+				s.True(s.logs.wait("jetstream asset created"))
+			},
+		},
+		{
+			name: "updates assets on restart",
+			setup: func(opts *server.Options) {
+				s.Require().NoError(s.srv.Start())
+				s.srv.Stop()
+
+				assets := opts.JetStreamAssets
+				assets.Streams[0].Subjects = []string{"orders.>", "refunds.>"}
+				assets.Consumers[0].Config.Description = "order worker"
+				assets.KeyValues[0].History = 5
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				js := s.jetStream()
+				ctx := context.Background()
+
+				stream, err := js.Stream(ctx, "ORDERS")
+				s.Require().NoError(err)
+				s.Equal(
+					[]string{"orders.>", "refunds.>"},
+					stream.CachedInfo().Config.Subjects,
+				)
+				consumer, err := js.Consumer(ctx, "ORDERS", "worker")
+				s.Require().NoError(err)
+				s.Equal("order worker", consumer.CachedInfo().Config.Description)
+				kv, err := js.KeyValue(ctx, "settings")
+				s.Require().NoError(err)
+				status, err := kv.Status(ctx)
+				s.Require().NoError(err)
+				s.EqualValues(5, status.History())
+				s.True(s.logs.wait("jetstream asset updated"))
+			},
+		},
+		{
+			name: "leaves drifted assets as they are",
+			setup: func(opts *server.Options) {
+				s.Require().NoError(s.srv.Start())
+				s.srv.Stop()
+
+				opts.JetStreamAssets.Streams[0].Storage = jetstream.MemoryStorage
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				stream, err := s.jetStream().Stream(context.Background(), "ORDERS")
+				s.Require().NoError(err)
+				s.Equal(
+					jetstream.FileStorage,
+					stream.CachedInfo().Config.Storage,
+				)
+				s.True(s.logs.wait("jetstream asset drifted"))
+			},
+		},
+		{
+			name: "fails provision phase on update refused other than drift",
+			setup: func(opts *server.Options) {
+				s.Require().NoError(s.srv.Start())
+				s.srv.Stop()
+
+				assets := opts.JetStreamAssets
+				assets.Streams[0].Subjects = []string{"orders.>", "refunds.>"}
+				assets.Streams = append([]jetstream.StreamConfig{{
+					Name:     "REFUNDS",
+					Subjects: []string{"refunds.>"},
+				}}, assets.Streams...)
+			},
+			validateFunc: func(err error) {
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhaseProvision, startErr.Phase)
+				s.ErrorContains(err, `error updating jetstream stream "ORDERS"`)
+
+				var apiErr *jetstream.APIError
+				s.Require().ErrorAs(err, &apiErr)
+				s.Equal(jetstream.ErrorCode(10065), apiErr.ErrorCode)
+			},
+		},
+		{
+			name: "fails provision phase on invalid declaration",
+			setup: func(opts *server.Options) {
+				s.Require().NoError(s.srv.Start())
+				s.srv.Stop()
+
+				opts.JetStreamAssets.Streams[0].Subjects = []string{"orders..>"}
+			},
+			validateFunc: func(err error) {
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhaseProvision, startErr.Phase)
+				s.ErrorContains(err, `error updating jetstream stream "ORDERS"`)
+
+				var apiErr *jetstream.APIError
+				s.Require().ErrorAs(err, &apiErr)
+				s.Equal(jetstream.ErrorCode(10052), apiErr.ErrorCode)
+			},
+		},
+		{
+			name: "creates assets in account of users",
+			setup: func(opts *server.Options) {
+				orders := s.jetStreamAccount("ORDERS")
+				opts.Accounts = []*natsserver.Account{orders}
+				opts.Users = []*natsserver.User{{
+					Username: "app",
+					Password: "secret",
+					Account:  orders,
+				}}
+				opts.JetStreamAssets.Account = "ORDERS"
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Len(s.srv.Opts.Users, 1)
+				s.assertAssets(s.jetStream(nats.UserInfo("app", "secret")))
+			},
+		},
+		{
+			name: "creates assets as single user",
+			setup: func(opts *server.Options) {
+				opts.Username = "app"
+				opts.Password = "secret"
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.assertAssets(s.jetStream(nats.UserInfo("app", "secret")))
+			},
+		},
+		{
+			name: "creates assets with token",
+			setup: func(opts *server.Options) {
+				opts.Authorization = "token"
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.assertAssets(s.jetStream(nats.Token("token")))
+			},
+		},
+		{
+			name: "creates assets with authenticator",
+			setup: func(opts *server.Options) {
+				opts.Accounts = []*natsserver.Account{
+					s.jetStreamAccount("ORDERS"),
+				}
+				opts.Authenticator = server.StaticAuthenticator{
+					"app": {Password: "secret", Account: "ORDERS"},
+				}
+				opts.JetStreamAssets.Account = "ORDERS"
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.assertAssets(s.jetStream(nats.UserInfo("app", "secret")))
+			},
+		},
+		{
+			name: "creates assets in operator mode",
+			setup: func(opts *server.Options) {
+				opts.Operator = &server.Operator{Accounts: []string{"APP"}}
+				opts.JetStreamAssets.Account = "APP"
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				creds, err := s.srv.UserCredentials("APP", &server.UserJWT{
+					Name: "app",
+				})
+				s.Require().NoError(err)
+				s.assertAssets(s.jetStream(
+					nats.UserJWTAndSeed(creds.JWT, string(creds.Seed)),
+				))
+			},
+		},
+		{
+			name: "fails provision phase on consumer without stream",
+			setup: func(opts *server.Options) {
+				opts.JetStreamAssets.Consumers[0].Stream = "MISSING"
+			},
+			validateFunc: func(err error) {
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhaseProvision, startErr.Phase)
+				s.ErrorContains(err, `jetstream consumer "MISSING/worker"`)
+				s.Equal(server.StateFailed, s.srv.State())
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup(s.srv.Opts)
+
+			tc.validateFunc(s.srv.Start())
+		})
+	}
+}
+
+func (s *ProvisionPublicTestSuite) TestReconcileJetStream() {
+	tests := []struct {
+		name         string
+		setup        func()
+		validateFunc func(drift []server.JetStreamDrift, err error)
+	}{
+		{
+			name: "applies reloaded assets",
+			setup: func() {
+				s.Require().NoError(s.srv.Start())
+				newOpts := *s.srv.Opts
+				newOpts.Options = s.srv.Opts.Clone()
+				assets := *s.srv.Opts.JetStreamAssets
+				assets.Streams = []jetstream.StreamConfig{{
+					Name:     "ORDERS",
+					Subjects: []string{"orders.>"},
+					MaxMsgs:  100,
+				}}
+				newOpts.JetStreamAssets = &assets
+				s.Require().NoError(s.srv.Reload(&newOpts))
+			},
+			validateFunc: func(drift []server.JetStreamDrift, err error) {
+				s.Require().NoError(err)
+				s.Empty(drift)
+				stream, err := s.jetStream().Stream(context.Background(), "ORDERS")
+				s.Require().NoError(err)
+				s.EqualValues(100, stream.CachedInfo().Config.MaxMsgs)
+			},
+		},
+		{
+			name: "returns drift",
+			setup: func() {
+				s.Require().NoError(s.srv.Start())
+				assets := s.srv.Opts.JetStreamAssets
+				assets.Streams[0].Storage = jetstream.MemoryStorage
+				assets.KeyValues[0].Storage = jetstream.MemoryStorage
+				assets.ObjectStores[0].Storage = jetstream.MemoryStorage
+				assets.Consumers[0].Config.DeliverPolicy = jetstream.DeliverNewPolicy
+			},
+			validateFunc: func(drift []server.JetStreamDrift, err error) {
+				s.Require().NoError(err)
+				s.Require().Len(drift, 4)
+				s.Equal("stream", drift[0].Kind)
+				s.Equal("ORDERS", drift[0].Name)
+				s.Equal("storage type can not be changed", drift[0].Reason)
+				s.Equal("key-value bucket", drift[1].Kind)
+				s.Equal("settings", drift[1].Name)
+				s.Equal("object store", drift[2].Kind)
+				s.Equal("consumer", drift[3].Kind)
+				s.Equal("ORDERS/worker", drift[3].Name)
+				s.Equal("deliver policy can not be changed", drift[3].Reason)
+			},
+		},
+		{
+			name: "recreates deleted assets",
+			setup: func() {
+				s.Require().NoError(s.srv.Start())
+				s.Require().NoError(s.jetStream().DeleteStream(
+					context.Background(),
+					"ORDERS",
+				))
+			},
+			validateFunc: func(drift []server.JetStreamDrift, err error) {
+				s.Require().NoError(err)
+				s.Empty(drift)
+				s.assertAssets(s.jetStream())
+			},
+		},
+		{
+			name:  "returns not running",
+			setup: func() {},
+			validateFunc: func(drift []server.JetStreamDrift, err error) {
+				s.ErrorIs(err, server.ErrNotRunning)
+				s.Nil(drift)
+			},
+		},
+		{
+			name: "returns no assets",
+			setup: func() {
+				s.srv.Opts.JetStreamAssets = nil
+				s.Require().NoError(s.srv.Start())
+			},
+			validateFunc: func(drift []server.JetStreamDrift, err error) {
+				s.ErrorIs(err, server.ErrNoJetStreamAssets)
+				s.Nil(drift)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+
+			tc.validateFunc(s.srv.ReconcileJetStream(context.Background()))
+		})
+	}
+}
+
+func (s *ProvisionPublicTestSuite) TestReload() {
+	tests := []struct {
+		name         string
+		change       func(opts *server.Options)
+		validateFunc func(err error)
+	}{
+		{
+			name: "keeps provisioning user of users",
+			change: func(opts *server.Options) {
+				opts.Users = append(opts.Users, &natsserver.User{
+					Username: "other",
+					Password: "secret",
+				})
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				drift, err := s.srv.ReconcileJetStream(context.Background())
+				s.Require().NoError(err)
+				s.Empty(drift)
+			},
+		},
+		{
+			name: "refuses account change",
+			change: func(opts *server.Options) {
+				opts.Accounts = append(
+					opts.Accounts,
+					natsserver.NewAccount("OTHER"),
+				)
+				assets := *opts.JetStreamAssets
+				assets.Account = "OTHER"
+				opts.JetStreamAssets = &assets
+			},
+			validateFunc: func(err error) {
+				var reloadErr *server.ReloadError
+				s.Require().ErrorAs(err, &reloadErr)
+				s.Equal([]string{"JetStreamAssets"}, reloadErr.Fields)
+			},
+		},
+		{
+			name: "refuses removing assets",
+			change: func(opts *server.Options) {
+				opts.JetStreamAssets = nil
+			},
+			validateFunc: func(err error) {
+				var reloadErr *server.ReloadError
+				s.Require().ErrorAs(err, &reloadErr)
+				s.Equal([]string{"JetStreamAssets"}, reloadErr.Fields)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.srv.Opts.Users = []*natsserver.User{{
+				Username: "app",
+				Password: "secret",
+			}}
+			s.Require().NoError(s.srv.Start())
+			newOpts := *s.srv.Opts
+			newOpts.Options = s.srv.Opts.Clone()
+			tc.change(&newOpts)
+
+			tc.validateFunc(s.srv.Reload(&newOpts))
+		})
+	}
+}
+
+// jetStream connects to the server with opts, returning its JetStream
+// context.
+func (s *ProvisionPublicTestSuite) jetStream(
+	opts ...nats.Option,
+) jetstream.JetStream {
+	nc, err := nats.Connect(
+		fmt.Sprintf("nats://127.0.0.1:%d", s.srv.Opts.Port),
+		opts...,
+	)
+	s.Require().NoError(err)
+	s.T().Cleanup(nc.Close)
+	js, err := jetstream.New(nc)
+	s.Require().NoError(err)
+
+	return js
+}
+
+// assertAssets asserts that the assets of SetupTest exist in js.
+func (s *ProvisionPublicTestSuite) assertAssets(
+	js jetstream.JetStream,
+) {
+	ctx := context.Background()
+	_, err := js.Stream(ctx, "ORDERS")
+	s.Require().NoError(err)
+	_, err = js.Consumer(ctx, "ORDERS", "worker")
+	s.Require().NoError(err)
+	_, err = js.KeyValue(ctx, "settings")
+	s.Require().NoError(err)
+	_, err = js.ObjectStore(ctx, "files")
+	s.Require().NoError(err)
+}
+
+// jetStreamAccount returns the account name with JetStream enabled, as a
+// configuration file enables it.
+func (s *ProvisionPublicTestSuite) jetStreamAccount(
+	name string,
+) *natsserver.Account {
+	opts := &natsserver.Options{}
+	s.Require().NoError(opts.ProcessConfigString(
+		fmt.Sprintf("accounts { %s { jetstream: enabled } }", name),
+	))
+	s.Require().Len(opts.Accounts, 1)
+
+	return opts.Accounts[0]
+}
+
+func TestProvisionPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(ProvisionPublicTestSuite))
+}
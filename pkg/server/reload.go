@@ -0,0 +1,316 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+)
+
+// reloadableFields lists the natsserver.Options fields, by lower-case
+// name, that the NATS server reloads in place or decides on itself. A
+// change to any other field needs a restart.
+var reloadableFields = map[string]bool{
+	// Logging.
+	"debug": true, "trace": true, "traceverbose": true,
+	"traceheaders": true, "logtime": true, "logtimeutc": true,
+	"logfile": true, "syslog": true, "remotesyslog": true,
+	"nolog": true, "nosigs": true, "connecterrorreports": true,
+	"reconnecterrorreports": true, "maxtracedmsglen": true,
+	// Authentication and authorization.
+	"username": true, "password": true, "authorization": true,
+	"authtimeout": true, "users": true, "nkeys": true, "accounts": true,
+	"noauthuser": true, "systemaccount": true, "defaultsentinel": true,
+	"resolver": true, "accountresolver": true, "accountsresolver": true,
+	"accountresolvertlsconfig": true, "proxies": true,
+	// TLS.
+	"tlsconfig": true, "tlstimeout": true, "tlspinnedcerts": true,
+	"tlshandshakefirst": true, "tlshandshakefirstfallback": true,
+	"ocspconfig": true, "ocspcacheconfig": true,
+	// Limits.
+	"maxconn": true, "maxcontrolline": true, "maxpayload": true,
+	"pinginterval": true, "maxpingsout": true, "writedeadline": true,
+	"disableshortfirstping": true, "nofastproducerstall": true,
+	// Listeners and clustering, which the NATS server checks field by
+	// field itself.
+	"port": true, "clientadvertise": true, "cluster": true, "routes": true,
+	"gateway": true, "leafnode": true, "websocket": true, "mqtt": true,
+	// JetStream, likewise checked by the NATS server.
+	"jetstream": true, "storedir": true, "jetstreammaxmemory": true,
+	"jetstreammaxstore": true, "jetstreammetacompact": true,
+	"jetstreammetacompactsize": true, "jetstreammetacompactsync": true,
+	"jetstreamconcurrentios": true,
+	// Miscellaneous.
+	"tags": true, "metadata": true, "pidfile": true, "portsfiledir": true,
+	"profblockrate": true, "configdigest": true,
+}
+
+// Error lists the fields that cannot be reloaded.
+func (e *ReloadError) Error() string {
+	return fmt.Sprintf(
+		"%s for fields: %s",
+		ErrNotReloadable,
+		strings.Join(e.Fields, ", "),
+	)
+}
+
+// Is reports whether target is ErrNotReloadable.
+func (e *ReloadError) Is(
+	target error,
+) bool {
+	return target == ErrNotReloadable
+}
+
+// Reload applies newOpts to the running server without disconnecting
+// clients, through the NATS server's configuration reload. Logging,
+// authentication, authorization, TLS, and limits can be changed this way.
+//
+// When newOpts changes settings that need a restart, such as Host, Reload
+// returns a *ReloadError naming every such field and leaves the server as
+// it was, unless newOpts.AllowRestart is set, in which case the server is
+// restarted with newOpts. newOpts is validated first, as Start does, and
+// once applied replaces Opts. Reloading a server that is not running
+// returns ErrNotRunning, and reloading with nil options ErrNilOptions.
+func (s *Server) Reload(
+	newOpts *Options,
+) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	return s.reload(newOpts)
+}
+
+// updateOptions reloads the server with a copy of Opts changed by change,
+// which is given the copy's NATS server options. Holding reloadMu from the
+// copy to the reload keeps concurrent changes from losing each other.
+func (s *Server) updateOptions(
+	change func(natsOpts *natsserver.Options) error,
+) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	if s.State() != StateRunning {
+		return ErrNotRunning
+	}
+
+	newOpts := *s.options()
+	newOpts.Options = newOpts.Options.Clone()
+	// Clone shares the accounts slice, which change may modify.
+	newOpts.Accounts = slices.Clone(newOpts.Accounts)
+	if err := change(newOpts.Options); err != nil {
+		return err
+	}
+
+	if errs := validateAuth(newOpts.Options); len(errs) > 0 {
+		return &ValidationError{Errs: errs}
+	}
+
+	return s.reload(&newOpts)
+}
+
+// reload applies newOpts as Reload does. The caller holds reloadMu.
+func (s *Server) reload(
+	newOpts *Options,
+) error {
+	if s.State() != StateRunning {
+		return fmt.Errorf("error reloading server: %w", ErrNotRunning)
+	}
+
+	if newOpts == nil {
+		return fmt.Errorf("error reloading server: %w", ErrNilOptions)
+	}
+
+	if err := newOpts.Validate(); err != nil {
+		return fmt.Errorf("error reloading server: %w", err)
+	}
+
+	reloaded, err := newOpts.natsOptions(context.Background())
+	if err != nil {
+		return fmt.Errorf("error reloading server: %w", err)
+	}
+
+	return s.reloadWith(newOpts, reloaded)
+}
+
+// reloadWith applies newOpts as reload does, giving the NATS server
+// reloaded, the NATS server options built from newOpts, secrets and TLS
+// files included. The caller holds reloadMu.
+func (s *Server) reloadWith(
+	newOpts *Options,
+	reloaded *natsserver.Options,
+) error {
+	s.mu.Lock()
+	state, natsServer, running := s.state, s.natsServer, s.running
+	callout, operator, devTLS := s.run.callout, s.run.operator, s.run.devTLS
+	jetStream := s.run.jetStream
+	s.mu.Unlock()
+
+	if state != StateRunning {
+		return fmt.Errorf("error reloading server: %w", ErrNotRunning)
+	}
+
+	// The auth callout service keeps its account, user, and issuer key
+	// for as long as the server runs.
+	callout.configure(reloaded)
+	// Operator mode keeps its operator and account resolver likewise.
+	operator.configure(reloaded)
+	// Development TLS keeps its certificates likewise.
+	devTLS.configure(reloaded)
+	newOpts.requireClientCertificates(reloaded)
+	// JetStream provisioning keeps its account and user likewise.
+	jetStream.configure(reloaded)
+
+	fields := nonReloadableFields(s.options().Options, running, reloaded)
+	// The NATS server keeps the custom client authentication it started
+	// with, which can swap one Authenticator for another but not add or
+	// remove one.
+	if (newOpts.Authenticator == nil) != (s.options().Authenticator == nil) {
+		fields = append(fields, "Authenticator")
+	}
+	if (newOpts.MutualTLS == nil) != (s.options().MutualTLS == nil) {
+		fields = append(fields, "MutualTLS")
+	}
+	if newOpts.AuthCallout.account() != s.options().AuthCallout.account() {
+		fields = append(fields, "AuthCallout")
+	}
+	if !reflect.DeepEqual(newOpts.Operator, s.options().Operator) {
+		fields = append(fields, "Operator")
+	}
+	if !reflect.DeepEqual(newOpts.DevTLS, s.options().DevTLS) {
+		fields = append(fields, "DevTLS")
+	}
+	if !reflect.DeepEqual(newOpts.CertWatcher, s.options().CertWatcher) {
+		fields = append(fields, "CertWatcher")
+	}
+	assets, oldAssets := newOpts.JetStreamAssets, s.options().JetStreamAssets
+	if (assets == nil) != (oldAssets == nil) ||
+		assets != nil && assets.Account != oldAssets.Account {
+		fields = append(fields, "JetStreamAssets")
+	}
+
+	if len(fields) > 0 {
+		if !newOpts.AllowRestart {
+			return &ReloadError{Fields: fields}
+		}
+
+		s.logger.Info(
+			"restarting nats server to apply options",
+			"fields", fields,
+		)
+
+		return s.restartWith(newOpts)
+	}
+
+	if err := natsServer.ReloadOptions(reloaded); err != nil {
+		// Fields the NATS server checks itself, such as StoreDir, are
+		// reported as unsupported only once it has looked at them.
+		if !newOpts.AllowRestart || !errors.Is(err, ErrNotReloadable) {
+			return fmt.Errorf("error reloading server: %w", err)
+		}
+
+		s.logger.Info(
+			"restarting nats server to apply options",
+			"error", err,
+		)
+
+		return s.restartWith(newOpts)
+	}
+
+	s.mu.Lock()
+	s.running = reloaded
+	s.Opts = newOpts
+	s.mu.Unlock()
+
+	s.logger.Info("nats server reloaded successfully")
+
+	return nil
+}
+
+// Restart stops the server, if it is running, and starts it again with
+// Opts. Clients are disconnected; Reload changes what it can without.
+func (s *Server) Restart() error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	return s.restartWith(s.options())
+}
+
+// restartWith stops the server, replaces Opts with opts, and starts the
+// server again. The caller holds reloadMu.
+func (s *Server) restartWith(
+	opts *Options,
+) error {
+	if err := s.StopContext(context.Background()); err != nil {
+		return fmt.Errorf("error restarting server: %w", err)
+	}
+
+	s.mu.Lock()
+	s.Opts = opts
+	s.mu.Unlock()
+
+	return s.Start()
+}
+
+// nonReloadableFields returns the names of the fields of newOpts that differ
+// from running, the options the NATS server is using, and cannot be
+// reloaded. A zero value in newOpts stands for the default the NATS server
+// fills in, which is the value in running only when the field was zero in
+// configured, the options running was made from; otherwise setting it to
+// zero is a change.
+func nonReloadableFields(
+	configured *natsserver.Options,
+	running *natsserver.Options,
+	newOpts *natsserver.Options,
+) []string {
+	var fields []string
+
+	configuredValue := reflect.ValueOf(configured).Elem()
+	oldValue := reflect.ValueOf(running).Elem()
+	newValue := reflect.ValueOf(newOpts).Elem()
+	for i := range oldValue.NumField() {
+		field := oldValue.Type().Field(i)
+		if !field.IsExported() || reloadableFields[strings.ToLower(field.Name)] {
+			continue
+		}
+
+		if newValue.Field(i).IsZero() {
+			if !configuredValue.Field(i).IsZero() {
+				fields = append(fields, field.Name)
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(
+			oldValue.Field(i).Interface(),
+			newValue.Field(i).Interface(),
+		) {
+			fields = append(fields, field.Name)
+		}
+	}
+
+	return fields
+}
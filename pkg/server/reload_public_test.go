@@ -0,0 +1,483 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+type ReloadPublicTestSuite struct {
+	suite.Suite
+
+	mockCtrl       *gomock.Controller
+	mockNATSServer *mocks.MockNATSServerInstance
+	srv            *server.Server
+	started        []*natsserver.Options
+	terminate      func()
+	newNATSServer  func(*natsserver.Options) (server.NATSServerInstance, error)
+}
+
+func (s *ReloadPublicTestSuite) SetupSuite() {
+	s.newNATSServer = server.NewNATSServer
+}
+
+func (s *ReloadPublicTestSuite) TearDownSuite() {
+	server.NewNATSServer = s.newNATSServer
+}
+
+func (s *ReloadPublicTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockNATSServer = mocks.NewMockNATSServerInstance(s.mockCtrl)
+	s.terminate = expectWaitForShutdown(s.mockNATSServer)
+	s.started = nil
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		&server.Options{
+			Options: &natsserver.Options{
+				Host: "127.0.0.1",
+				Port: -1,
+			},
+			ReadyTimeout: 5 * time.Second,
+		},
+	)
+
+	server.NewNATSServer = func(
+		opts *natsserver.Options,
+	) (server.NATSServerInstance, error) {
+		s.started = append(s.started, opts)
+		return s.mockNATSServer, nil
+	}
+}
+
+func (s *ReloadPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *ReloadPublicTestSuite) TearDownSubTest() {
+	s.terminate()
+}
+
+func (s *ReloadPublicTestSuite) TearDownTest() {
+	s.terminate()
+	s.mockCtrl.Finish()
+}
+
+// withOptions returns a copy of the server's options changed by fn.
+func (s *ReloadPublicTestSuite) withOptions(
+	fn func(opts *server.Options),
+) *server.Options {
+	opts := *s.srv.Opts
+	opts.Options = s.srv.Opts.Options.Clone()
+	fn(&opts)
+
+	return &opts
+}
+
+func (s *ReloadPublicTestSuite) TestReload() {
+	tests := []struct {
+		name         string
+		start        bool
+		mockSetup    func()
+		newOpts      func() *server.Options
+		validateFunc func(newOpts *server.Options, err error)
+	}{
+		{
+			name:  "reloads reloadable changes in place",
+			start: true,
+			mockSetup: func() {
+				s.mockNATSServer.EXPECT().
+					ReloadOptions(gomock.Any()).
+					DoAndReturn(func(opts *natsserver.Options) error {
+						s.True(opts.Debug)
+						s.Equal(100, opts.MaxConn)
+						return nil
+					})
+			},
+			newOpts: func() *server.Options {
+				return s.withOptions(func(opts *server.Options) {
+					opts.Debug = true
+					opts.MaxConn = 100
+				})
+			},
+			validateFunc: func(newOpts *server.Options, err error) {
+				s.NoError(err)
+				s.Same(newOpts, s.srv.Opts)
+				s.Equal(server.StateRunning, s.srv.State())
+			},
+		},
+		{
+			name: "treats zero values as unchanged defaults",
+			mockSetup: func() {
+				s.srv.Opts.Host = ""
+				expectStart(s.mockNATSServer)
+				s.Require().NoError(s.srv.Start())
+				// The NATS server fills in the default.
+				s.started[0].Host = "0.0.0.0"
+				s.mockNATSServer.EXPECT().ReloadOptions(gomock.Any())
+			},
+			newOpts: func() *server.Options {
+				return s.withOptions(func(opts *server.Options) {
+					opts.Debug = true
+				})
+			},
+			validateFunc: func(_ *server.Options, err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name:      "rejects zero values replacing set values",
+			start:     true,
+			mockSetup: func() {},
+			newOpts: func() *server.Options {
+				return s.withOptions(func(opts *server.Options) {
+					opts.Host = ""
+				})
+			},
+			validateFunc: func(_ *server.Options, err error) {
+				var reloadErr *server.ReloadError
+				s.Require().ErrorAs(err, &reloadErr)
+				s.Equal([]string{"Host"}, reloadErr.Fields)
+			},
+		},
+		{
+			name:      "rejects nil options",
+			start:     true,
+			mockSetup: func() {},
+			newOpts: func() *server.Options {
+				return nil
+			},
+			validateFunc: func(_ *server.Options, err error) {
+				s.ErrorIs(err, server.ErrNilOptions)
+				s.Len(s.started, 1)
+			},
+		},
+		{
+			name:      "rejects invalid options",
+			start:     true,
+			mockSetup: func() {},
+			newOpts: func() *server.Options {
+				return s.withOptions(func(opts *server.Options) {
+					opts.ReadyTimeout = 0
+					opts.AllowRestart = true
+				})
+			},
+			validateFunc: func(_ *server.Options, err error) {
+				s.ErrorIs(err, server.ErrInvalidOptions)
+				s.NotEqual(time.Duration(0), s.srv.Opts.ReadyTimeout)
+				s.Len(s.started, 1)
+			},
+		},
+		{
+			name:      "rejects changes needing a restart",
+			start:     true,
+			mockSetup: func() {},
+			newOpts: func() *server.Options {
+				return s.withOptions(func(opts *server.Options) {
+					opts.Host = "0.0.0.0"
+					opts.ServerName = "renamed"
+					opts.Debug = true
+				})
+			},
+			validateFunc: func(_ *server.Options, err error) {
+				var reloadErr *server.ReloadError
+				s.Require().ErrorAs(err, &reloadErr)
+				s.Equal([]string{"ServerName", "Host"}, reloadErr.Fields)
+				s.ErrorIs(err, server.ErrNotReloadable)
+				s.Equal("127.0.0.1", s.srv.Opts.Host)
+			},
+		},
+		{
+			name:  "restarts when allowed and changes need a restart",
+			start: true,
+			mockSetup: func() {
+				s.mockNATSServer.EXPECT().Shutdown()
+				expectStart(s.mockNATSServer)
+			},
+			newOpts: func() *server.Options {
+				return s.withOptions(func(opts *server.Options) {
+					opts.Host = "0.0.0.0"
+					opts.AllowRestart = true
+				})
+			},
+			validateFunc: func(newOpts *server.Options, err error) {
+				s.NoError(err)
+				s.Same(newOpts, s.srv.Opts)
+				s.Require().Len(s.started, 2)
+				s.Equal("0.0.0.0", s.started[1].Host)
+				s.Equal(server.StateRunning, s.srv.State())
+			},
+		},
+		{
+			name:  "restarts when allowed and nats server cannot reload",
+			start: true,
+			mockSetup: func() {
+				s.mockNATSServer.EXPECT().
+					ReloadOptions(gomock.Any()).
+					Return(fmt.Errorf(
+						"%w for jetstream storage directory",
+						server.ErrNotReloadable,
+					))
+				s.mockNATSServer.EXPECT().Shutdown()
+				expectStart(s.mockNATSServer)
+			},
+			newOpts: func() *server.Options {
+				return s.withOptions(func(opts *server.Options) {
+					opts.StoreDir = s.T().TempDir()
+					opts.AllowRestart = true
+				})
+			},
+			validateFunc: func(newOpts *server.Options, err error) {
+				s.NoError(err)
+				s.Same(newOpts, s.srv.Opts)
+				s.Len(s.started, 2)
+			},
+		},
+		{
+			name:  "returns error when nats server cannot reload",
+			start: true,
+			mockSetup: func() {
+				s.mockNATSServer.EXPECT().
+					ReloadOptions(gomock.Any()).
+					Return(fmt.Errorf(
+						"%w for jetstream storage directory",
+						server.ErrNotReloadable,
+					))
+			},
+			newOpts: func() *server.Options {
+				return s.withOptions(func(opts *server.Options) {
+					opts.StoreDir = s.T().TempDir()
+				})
+			},
+			validateFunc: func(_ *server.Options, err error) {
+				s.EqualError(
+					err,
+					"error reloading server: config reload not supported "+
+						"for jetstream storage directory",
+				)
+				s.Len(s.started, 1)
+			},
+		},
+		{
+			name: "matches nats server errors for options it cannot reload",
+			mockSetup: func() {
+				server.NewNATSServer = s.newNATSServer
+				s.srv.Opts.NoSigs = true
+				s.srv.Opts.JetStream = true
+				s.srv.Opts.StoreDir = s.T().TempDir()
+				s.Require().NoError(s.srv.Start())
+				s.T().Cleanup(s.srv.Stop)
+			},
+			newOpts: func() *server.Options {
+				return s.withOptions(func(opts *server.Options) {
+					opts.StoreDir = s.T().TempDir()
+				})
+			},
+			validateFunc: func(_ *server.Options, err error) {
+				s.ErrorIs(err, server.ErrNotReloadable)
+				s.ErrorContains(err, "jetstream storage directory")
+			},
+		},
+		{
+			name:  "returns error without restarting when options are invalid",
+			start: true,
+			mockSetup: func() {
+				s.mockNATSServer.EXPECT().
+					ReloadOptions(gomock.Any()).
+					Return(errors.New("invalid limits"))
+			},
+			newOpts: func() *server.Options {
+				return s.withOptions(func(opts *server.Options) {
+					opts.MaxPayload = -1
+					opts.AllowRestart = true
+				})
+			},
+			validateFunc: func(_ *server.Options, err error) {
+				s.EqualError(err, "error reloading server: invalid limits")
+				s.Len(s.started, 1)
+			},
+		},
+		{
+			name:      "returns error when server is not running",
+			mockSetup: func() {},
+			newOpts: func() *server.Options {
+				return s.withOptions(func(opts *server.Options) {
+					opts.Debug = true
+				})
+			},
+			validateFunc: func(_ *server.Options, err error) {
+				s.ErrorIs(err, server.ErrNotRunning)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			if tc.start {
+				expectStart(s.mockNATSServer)
+				s.Require().NoError(s.srv.Start())
+			}
+
+			tc.mockSetup()
+
+			newOpts := tc.newOpts()
+			tc.validateFunc(newOpts, s.srv.Reload(newOpts))
+		})
+	}
+}
+
+func (s *ReloadPublicTestSuite) TestRestart() {
+	tests := []struct {
+		name         string
+		setup        func()
+		validateFunc func(err error)
+	}{
+		{
+			name: "restarts running server with configured options",
+			setup: func() {
+				expectStart(s.mockNATSServer)
+				s.mockNATSServer.EXPECT().Shutdown()
+				expectStart(s.mockNATSServer)
+				s.Require().NoError(s.srv.Start())
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+				s.Require().Len(s.started, 2)
+				s.NotSame(s.started[0], s.started[1])
+				s.Equal(-1, s.started[1].Port)
+				s.Equal(server.StateRunning, s.srv.State())
+			},
+		},
+		{
+			name: "keeps restarted nats server running",
+			setup: func() {
+				// The previous run's NATS server exiting must not fail
+				// the new run, which only a real server shows.
+				server.NewNATSServer = s.newNATSServer
+				s.srv.Opts.NoSigs = true
+				s.Require().NoError(s.srv.Start())
+				s.T().Cleanup(s.srv.Stop)
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+				s.Never(
+					func() bool { return s.srv.State() != server.StateRunning },
+					100*time.Millisecond,
+					5*time.Millisecond,
+				)
+			},
+		},
+		{
+			name: "starts server that is not running",
+			setup: func() {
+				expectStart(s.mockNATSServer)
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+				s.Len(s.started, 1)
+			},
+		},
+		{
+			name: "returns error when server cannot be stopped",
+			setup: func() {
+				release := make(chan struct{})
+				s.T().Cleanup(func() { close(release) })
+
+				shuttingDown := make(chan struct{})
+				expectStart(s.mockNATSServer)
+				s.mockNATSServer.EXPECT().
+					Shutdown().
+					Do(func() {
+						close(shuttingDown)
+						<-release
+					})
+				s.Require().NoError(s.srv.Start())
+
+				// Leave the server stopping in the background.
+				go s.srv.Stop()
+				<-shuttingDown
+			},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrInvalidTransition)
+				s.ErrorContains(err, "error restarting server")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+
+			tc.validateFunc(s.srv.Restart())
+		})
+	}
+}
+
+func (s *ReloadPublicTestSuite) TestError() {
+	err := &server.ReloadError{Fields: []string{"Host", "ServerName"}}
+
+	s.EqualError(
+		err,
+		"config reload not supported for fields: Host, ServerName",
+	)
+}
+
+func (s *ReloadPublicTestSuite) TestIs() {
+	tests := []struct {
+		name     string
+		target   error
+		expected bool
+	}{
+		{
+			name:     "matches ErrNotReloadable",
+			target:   server.ErrNotReloadable,
+			expected: true,
+		},
+		{
+			name:     "does not match other errors",
+			target:   server.ErrNotRunning,
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			err := &server.ReloadError{Fields: []string{"Host"}}
+
+			s.Equal(tc.expected, errors.Is(err, tc.target))
+		})
+	}
+}
+
+func TestReloadPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(ReloadPublicTestSuite))
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+)
+
+// secretScheme matches the scheme of a URI.
+var secretScheme = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*$`)
+
+// builtinSecretResolvers resolve the schemes every server understands:
+// "file:///run/secrets/nats" reads a file, trimming a trailing newline, and
+// "env://NATS_PASSWORD" reads an environment variable.
+var builtinSecretResolvers = map[string]SecretResolver{
+	"file": SecretResolverFunc(resolveFileSecret),
+	"env":  SecretResolverFunc(resolveEnvSecret),
+}
+
+// ResolveSecret calls f.
+func (f SecretResolverFunc) ResolveSecret(
+	ctx context.Context,
+	ref *url.URL,
+) (string, error) {
+	return f(ctx, ref)
+}
+
+// Error names the option and scheme of the reference, never the secret.
+func (e *SecretError) Error() string {
+	return fmt.Sprintf(
+		"error resolving %s secret for %s: %v",
+		e.Scheme,
+		e.Field,
+		e.Err,
+	)
+}
+
+// Unwrap returns the resolver's error.
+func (e *SecretError) Unwrap() error {
+	return e.Err
+}
+
+// resolveSecrets replaces the secret references within natsOpts, the copy
+// of the options the NATS server is given, with the secrets they name.
+// Values that are not references are left as they are.
+func (o *Options) resolveSecrets(
+	ctx context.Context,
+	natsOpts *natsserver.Options,
+) error {
+	secrets := map[string]*string{
+		"Password":           &natsOpts.Password,
+		"Authorization":      &natsOpts.Authorization,
+		"Cluster.Password":   &natsOpts.Cluster.Password,
+		"Gateway.Password":   &natsOpts.Gateway.Password,
+		"LeafNode.Password":  &natsOpts.LeafNode.Password,
+		"Websocket.Password": &natsOpts.Websocket.Password,
+		"Websocket.Token":    &natsOpts.Websocket.Token,
+		"MQTT.Password":      &natsOpts.MQTT.Password,
+		"MQTT.Token":         &natsOpts.MQTT.Token,
+	}
+	for i, user := range natsOpts.Users {
+		secrets[fmt.Sprintf("Users[%d].Password", i)] = &user.Password
+	}
+
+	// Clone leaves remote leaf nodes shared with Opts, so they are copied
+	// before their seeds are replaced.
+	remotes := slices.Clone(natsOpts.LeafNode.Remotes)
+	for i, remote := range remotes {
+		r := *remote
+		remotes[i] = &r
+		secrets[fmt.Sprintf("LeafNode.Remotes[%d].Nkey", i)] = &r.Nkey
+	}
+	natsOpts.LeafNode.Remotes = remotes
+
+	var errs []error
+	for _, field := range slices.Sorted(maps.Keys(secrets)) {
+		secret, err := o.resolveSecret(ctx, field, *secrets[field])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		*secrets[field] = secret
+	}
+
+	return errors.Join(errs...)
+}
+
+// resolveSecret returns the secret value names when it is a reference, and
+// value itself otherwise.
+func (o *Options) resolveSecret(
+	ctx context.Context,
+	field string,
+	value string,
+) (string, error) {
+	if !isSecretRef(value) {
+		return value, nil
+	}
+
+	scheme, _, _ := strings.Cut(value, "://")
+	ref, resolver, err := o.secretRef(value)
+	if err != nil {
+		return "", &SecretError{Field: field, Scheme: scheme, Err: err}
+	}
+
+	secret, err := resolver.ResolveSecret(ctx, ref)
+	if err != nil {
+		return "", &SecretError{Field: field, Scheme: ref.Scheme, Err: err}
+	}
+
+	return secret, nil
+}
+
+// secretRef returns the reference value along with the resolver of its
+// scheme. A reference that does not parse, or whose scheme has no
+// resolver, is an error, so a mistyped reference is not taken for a
+// literal password. Resolvers in SecretResolvers take precedence over the
+// built-in ones.
+func (o *Options) secretRef(
+	value string,
+) (*url.URL, SecretResolver, error) {
+	ref, err := url.Parse(value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolver, ok := o.SecretResolvers[ref.Scheme]
+	if !ok {
+		resolver, ok = builtinSecretResolvers[ref.Scheme]
+	}
+	if !ok {
+		return ref, nil, ErrUnknownSecretScheme
+	}
+
+	return ref, resolver, nil
+}
+
+// isSecretRef reports whether value is a secret reference, of the form
+// "scheme://". Only that form is a reference, so a password such as
+// "env:foo" is used as written.
+func isSecretRef(
+	value string,
+) bool {
+	scheme, _, ok := strings.Cut(value, "://")
+
+	return ok && secretScheme.MatchString(scheme)
+}
+
+// resolveFileSecret reads the file ref names, as "file:///abs/path" or
+// "file://relative/path", without its trailing newline.
+func resolveFileSecret(
+	_ context.Context,
+	ref *url.URL,
+) (string, error) {
+	data, err := os.ReadFile(ref.Host + ref.Path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// resolveEnvSecret reads the environment variable ref names, as
+// "env://NAME".
+func resolveEnvSecret(
+	_ context.Context,
+	ref *url.URL,
+) (string, error) {
+	value, ok := os.LookupEnv(ref.Host)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", ref.Host)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,367 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+type SecretPublicTestSuite struct {
+	suite.Suite
+
+	mockCtrl       *gomock.Controller
+	mockNATSServer *mocks.MockNATSServerInstance
+	srv            *server.Server
+	started        []*natsserver.Options
+	terminate      func()
+	newNATSServer  func(*natsserver.Options) (server.NATSServerInstance, error)
+	dir            string
+}
+
+func (s *SecretPublicTestSuite) SetupSuite() {
+	s.newNATSServer = server.NewNATSServer
+}
+
+func (s *SecretPublicTestSuite) TearDownSuite() {
+	server.NewNATSServer = s.newNATSServer
+}
+
+func (s *SecretPublicTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockNATSServer = mocks.NewMockNATSServerInstance(s.mockCtrl)
+	s.terminate = expectWaitForShutdown(s.mockNATSServer)
+	s.started = nil
+	s.dir = s.T().TempDir()
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		&server.Options{
+			Options:      &natsserver.Options{},
+			ReadyTimeout: 5 * time.Second,
+		},
+	)
+
+	server.NewNATSServer = func(
+		opts *natsserver.Options,
+	) (server.NATSServerInstance, error) {
+		s.started = append(s.started, opts)
+		return s.mockNATSServer, nil
+	}
+}
+
+func (s *SecretPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *SecretPublicTestSuite) TearDownSubTest() {
+	s.terminate()
+}
+
+func (s *SecretPublicTestSuite) TearDownTest() {
+	s.terminate()
+	s.mockCtrl.Finish()
+}
+
+// writeSecret writes secret to the file name in the test directory,
+// returning a file reference to it.
+func (s *SecretPublicTestSuite) writeSecret(
+	name string,
+	secret string,
+) string {
+	path := filepath.Join(s.dir, name)
+	s.Require().NoError(os.WriteFile(path, []byte(secret), 0o600))
+
+	return "file://" + path
+}
+
+func (s *SecretPublicTestSuite) TestStart() {
+	tests := []struct {
+		name         string
+		setup        func(opts *server.Options)
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name: "resolves references in every secret field",
+			setup: func(opts *server.Options) {
+				s.T().Setenv("SECRET_TEST_TOKEN", "t0k3n")
+				opts.Password = s.writeSecret("password", "s3cr3t\n")
+				opts.Authorization = "env://SECRET_TEST_TOKEN"
+				opts.Users = []*natsserver.User{
+					{Username: "app", Password: "vault://app"},
+				}
+				opts.Cluster.Password = "vault://cluster"
+				opts.Gateway.Password = "vault://gateway"
+				opts.LeafNode.Password = "vault://leafnode"
+				opts.LeafNode.Remotes = []*natsserver.RemoteLeafOpts{
+					{Nkey: "vault://seed"},
+				}
+				opts.Websocket.Password = "vault://websocket"
+				opts.Websocket.Token = "vault://websocket-token"
+				opts.MQTT.Password = "vault://mqtt"
+				opts.MQTT.Token = "vault://mqtt-token"
+				opts.SecretResolvers = map[string]server.SecretResolver{
+					"vault": server.SecretResolverFunc(func(
+						_ context.Context,
+						ref *url.URL,
+					) (string, error) {
+						return "from-" + ref.Host, nil
+					}),
+				}
+			},
+			mockSetup: func() { expectStart(s.mockNATSServer) },
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Require().Len(s.started, 1)
+				running := s.started[0]
+				s.Equal("s3cr3t", running.Password)
+				s.Equal("t0k3n", running.Authorization)
+				s.Equal("from-app", running.Users[0].Password)
+				s.Equal("from-cluster", running.Cluster.Password)
+				s.Equal("from-gateway", running.Gateway.Password)
+				s.Equal("from-leafnode", running.LeafNode.Password)
+				s.Equal("from-seed", running.LeafNode.Remotes[0].Nkey)
+				s.Equal("from-websocket", running.Websocket.Password)
+				s.Equal("from-websocket-token", running.Websocket.Token)
+				s.Equal("from-mqtt", running.MQTT.Password)
+				s.Equal("from-mqtt-token", running.MQTT.Token)
+
+				opts := s.srv.Opts
+				s.Equal("env://SECRET_TEST_TOKEN", opts.Authorization)
+				s.Equal("vault://app", opts.Users[0].Password)
+				s.Equal("vault://seed", opts.LeafNode.Remotes[0].Nkey)
+			},
+		},
+		{
+			name: "leaves values that are not references",
+			setup: func(opts *server.Options) {
+				s.T().Setenv("SECRET_TEST_TOKEN", "t0k3n")
+				opts.Users = []*natsserver.User{
+					{Username: "a", Password: "plain"},
+					{Username: "b", Password: "p@ss:word"},
+					{Username: "c", Password: "%zz://"},
+					{Username: "d", Password: "env:SECRET_TEST_TOKEN"},
+					{Username: "e", Password: "file:/etc/passwd"},
+				}
+			},
+			mockSetup: func() { expectStart(s.mockNATSServer) },
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				users := s.started[0].Users
+				s.Equal("plain", users[0].Password)
+				s.Equal("p@ss:word", users[1].Password)
+				s.Equal("%zz://", users[2].Password)
+				s.Equal("env:SECRET_TEST_TOKEN", users[3].Password)
+				s.Equal("file:/etc/passwd", users[4].Password)
+			},
+		},
+		{
+			name: "prefers custom resolvers over built-in ones",
+			setup: func(opts *server.Options) {
+				opts.Password = "env://SECRET_TEST_UNSET"
+				opts.SecretResolvers = map[string]server.SecretResolver{
+					"env": server.SecretResolverFunc(func(
+						context.Context,
+						*url.URL,
+					) (string, error) {
+						return "custom", nil
+					}),
+				}
+			},
+			mockSetup: func() { expectStart(s.mockNATSServer) },
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Equal("custom", s.started[0].Password)
+			},
+		},
+		{
+			name: "fails start with every secret that cannot be resolved",
+			setup: func(opts *server.Options) {
+				s.T().Setenv("SECRET_TEST_TOKEN", "t0k3n")
+				opts.Password = "file://" + filepath.Join(s.dir, "missing")
+				opts.Authorization = "env://SECRET_TEST_UNSET"
+				opts.Users = []*natsserver.User{
+					{Username: "app", Password: "env://SECRET_TEST_TOKEN"},
+				}
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhaseCreate, startErr.Phase)
+				s.ErrorIs(err, os.ErrNotExist)
+				s.ErrorContains(err, "error resolving env secret for "+
+					"Authorization: environment variable "+
+					"SECRET_TEST_UNSET is not set")
+				s.ErrorContains(err, "error resolving file secret for Password")
+
+				var secretErr *server.SecretError
+				s.Require().ErrorAs(err, &secretErr)
+				s.Equal("Authorization", secretErr.Field)
+				s.Empty(s.started)
+			},
+		},
+		{
+			name: "fails start with references no resolver understands",
+			setup: func(opts *server.Options) {
+				opts.Password = "vualt://x"
+				opts.Authorization = "vault://%zz"
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhaseCreate, startErr.Phase)
+				s.ErrorIs(err, server.ErrUnknownSecretScheme)
+				s.ErrorContains(err, "error resolving vualt secret for "+
+					"Password: no resolver for secret scheme")
+				s.ErrorContains(err, "error resolving vault secret for "+
+					"Authorization")
+
+				var secretErr *server.SecretError
+				s.Require().ErrorAs(err, &secretErr)
+				s.Empty(s.started)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup(s.srv.Opts)
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.Start())
+		})
+	}
+}
+
+func (s *SecretPublicTestSuite) TestReload() {
+	tests := []struct {
+		name         string
+		password     string
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name:     "resolves references again",
+			password: "env://SECRET_TEST_PASSWORD",
+			mockSetup: func() {
+				s.T().Setenv("SECRET_TEST_PASSWORD", "rotated")
+				s.mockNATSServer.EXPECT().
+					ReloadOptions(gomock.Any()).
+					DoAndReturn(func(opts *natsserver.Options) error {
+						s.Equal("rotated", opts.Password)
+						return nil
+					})
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name:      "fails reload when a secret cannot be resolved",
+			password:  "env://SECRET_TEST_UNSET",
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorContains(err, "error reloading server: "+
+					"error resolving env secret for Password")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			expectStart(s.mockNATSServer)
+			s.Require().NoError(s.srv.Start())
+
+			newOpts := *s.srv.Opts
+			newOpts.Options = s.srv.Opts.Options.Clone()
+			newOpts.Password = tc.password
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.Reload(&newOpts))
+		})
+	}
+}
+
+func (s *SecretPublicTestSuite) TestError() {
+	tests := []struct {
+		name     string
+		err      *server.SecretError
+		expected string
+	}{
+		{
+			name: "names the field and scheme",
+			err: &server.SecretError{
+				Field:  "Users[0].Password",
+				Scheme: "vault",
+				Err:    errors.New("permission denied"),
+			},
+			expected: "error resolving vault secret for Users[0].Password: " +
+				"permission denied",
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.Equal(tc.expected, tc.err.Error())
+		})
+	}
+}
+
+func (s *SecretPublicTestSuite) TestUnwrap() {
+	cause := errors.New("permission denied")
+
+	tests := []struct {
+		name     string
+		err      *server.SecretError
+		expected error
+	}{
+		{
+			name:     "returns the resolver error",
+			err:      &server.SecretError{Err: cause},
+			expected: cause,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.Equal(tc.expected, tc.err.Unwrap())
+		})
+	}
+}
+
+func TestSecretPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(SecretPublicTestSuite))
+}
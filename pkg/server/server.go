@@ -22,8 +22,13 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
 )
 
 // New initialize and configure a new Server instance.
@@ -34,22 +39,137 @@ func New(
 ) *Server {
 	return &Server{
 		logger: logger,
+		run:    newRun(),
 		Opts:   opts,
 	}
 }
 
 // Start start the embedded NATS server.
 func (s *Server) Start() error {
-	natsServer, err := NewNATSServer(s.Opts.Options)
+	return s.StartContext(context.Background())
+}
+
+// StartContext starts the embedded NATS server, waiting for it to be ready
+// for connections until Options.ReadyTimeout elapses or ctx ends, whichever
+// comes first. When ctx ends first, the server is shut down before
+// returning, and the returned error wraps both ErrStartCanceled and the
+// context's error. Starting a server that is already starting, running, or
+// draining returns an error wrapping ErrAlreadyStarted and a
+// *TransitionError.
+//
+// Before the NATS server is created, the listeners on fixed ports are
+// checked and the JetStream store directory is locked, so a port conflict
+// or a store directory in use fails the start at once rather than as a
+// readiness timeout. Once it is ready, the migrations of
+// Options.JetStreamAssets are applied and the assets created and updated,
+// before the OnReady hooks run. A start that fails returns a *StartError
+// naming the phase it failed in.
+func (s *Server) StartContext(
+	ctx context.Context,
+) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrStartCanceled, err)
+	}
+
+	if _, err := s.transition(StateStarting); err != nil {
+		return fmt.Errorf("%w: %w", ErrAlreadyStarted, err)
+	}
+
+	opts := s.options()
+
+	if err := opts.Validate(); err != nil {
+		return s.failStart(StartPhaseValidate, err)
+	}
+
+	if err := s.runHooks(ctx, HookStarting, opts.Hooks.OnStarting); err != nil {
+		return s.failStart(StartPhaseHooks, err)
+	}
+
+	storeLock, err := preflight(opts.Options)
 	if err != nil {
-		return fmt.Errorf("error starting server: %w", err)
+		return s.failStart(StartPhasePreflight, err)
 	}
 
-	go natsServer.Start()
+	s.mu.Lock()
+	s.run.storeLock = storeLock
+	s.mu.Unlock()
 
-	// Wait for server readiness
-	if !natsServer.ReadyForConnections(s.Opts.ReadyTimeout) {
-		return fmt.Errorf("server not ready for connections")
+	// The NATS server fills in defaults on the options it is given, so it
+	// gets a copy, leaving Opts as configured for later restarts and free
+	// of resolved secrets.
+	running, err := opts.natsOptions(ctx)
+	if err != nil {
+		return s.failStart(StartPhaseCreate, err)
+	}
+
+	auth := s.newClientAuth(opts, running)
+
+	callout, err := s.newAuthCallout(opts)
+	if err != nil {
+		return s.failStart(StartPhaseCreate, err)
+	}
+	callout.configure(running)
+
+	operator, err := s.newOperatorRun(opts)
+	if err != nil {
+		return s.failStart(StartPhaseCreate, err)
+	}
+	operator.configure(running)
+
+	devTLS, err := s.newDevTLSRun(opts)
+	if err != nil {
+		operator.stop()
+		return s.failStart(StartPhaseCreate, err)
+	}
+	devTLS.configure(running)
+	opts.requireClientCertificates(running)
+
+	jetStream := s.newJetStreamRun(opts, operator)
+	jetStream.configure(running)
+
+	watcher := s.newCertWatcher(opts)
+
+	s.mu.Lock()
+	s.run.callout = callout
+	s.run.operator = operator
+	s.run.devTLS = devTLS
+	s.run.certWatcher = watcher
+	s.run.jetStream = jetStream
+	s.mu.Unlock()
+
+	natsServer, err := NewNATSServer(running)
+	if err != nil {
+		return s.failStart(StartPhaseCreate, err)
+	}
+
+	auth.bind(natsServer, running, jetStream)
+
+	started := make(chan struct{})
+	go func() {
+		defer close(started)
+		natsServer.Start()
+	}()
+
+	// The result channel is buffered so the readiness check can always
+	// deliver and exit, even after the select below stopped listening.
+	ready := make(chan bool, 1)
+	timeout := readyTimeout(ctx, opts.ReadyTimeout)
+	go func() {
+		ready <- natsServer.ReadyForConnections(timeout)
+	}()
+
+	select {
+	case ok := <-ready:
+		if !ok {
+			abortStart(natsServer, started)
+			return s.failStart(StartPhaseReady, ErrNotReady)
+		}
+	case <-ctx.Done():
+		abortStart(natsServer, started)
+		return s.failStart(
+			StartPhaseReady,
+			fmt.Errorf("%w: %w", ErrStartCanceled, ctx.Err()),
+		)
 	}
 
 	slogWrapper := &SlogWrapper{
@@ -58,18 +178,221 @@ func (s *Server) Start() error {
 
 	natsServer.SetLogger(slogWrapper, true, true)
 
-	s.logger.Info("nats server started successfully")
+	if err := callout.start(natsServer); err != nil {
+		abortStart(natsServer, started)
+		return s.failStart(StartPhaseReady, err)
+	}
 
+	if err := operator.start(natsServer); err != nil {
+		abortStart(natsServer, started)
+		return s.failStart(StartPhaseReady, err)
+	}
+
+	if _, err := jetStream.reconcile(
+		ctx,
+		natsServer,
+		opts.JetStreamAssets,
+	); err != nil {
+		abortStart(natsServer, started)
+		return s.failStart(StartPhaseProvision, err)
+	}
+
+	s.mu.Lock()
 	s.natsServer = natsServer
+	s.running = running
+	r := s.run
+	s.mu.Unlock()
+
+	if err := s.runHooks(ctx, HookReady, opts.Hooks.OnReady); err != nil {
+		abortStart(natsServer, started)
+		return s.failStart(StartPhaseHooks, err)
+	}
+
+	watcher.start()
+
+	s.logger.Info("nats server started successfully")
+
+	// Only this goroutine moves a server out of StateStarting.
+	_, _ = s.transition(StateRunning)
+
+	go s.monitor(r, natsServer, slogWrapper)
 
 	return nil
 }
 
 // Stop gracefully stops the embedded NATS server.
 func (s *Server) Stop() {
-	if s.natsServer != nil {
-		s.logger.Info("shutting down nats server")
-		s.natsServer.Shutdown()
-		s.logger.Info("nats server shut down successfully")
+	_ = s.StopContext(context.Background())
+}
+
+// StopContext gracefully stops the embedded NATS server, giving up once ctx
+// ends. With Options.DrainOnStop set it drains the server first, as Drain
+// does. Shutdown cannot be interrupted, so when ctx ends first it carries on
+// in the background and the returned error wraps both ErrStopCanceled and
+// the context's error. Stopping a server that is not running does nothing,
+// except while it is starting or already stopping, which returns a
+// *TransitionError.
+func (s *Server) StopContext(
+	ctx context.Context,
+) error {
+	if s.options().DrainOnStop {
+		return s.Drain(ctx)
+	}
+
+	natsServer, err := s.beginStop()
+	if natsServer == nil {
+		return err
+	}
+
+	stoppingErr := s.runHooks(ctx, HookStopping, s.options().Hooks.OnStopping)
+
+	s.logger.Info("shutting down nats server")
+
+	stopped := s.shutdown(ctx, natsServer.Shutdown)
+
+	var stoppedErr error
+	select {
+	case stoppedErr = <-stopped:
+	case <-ctx.Done():
+		return errors.Join(
+			stoppingErr,
+			fmt.Errorf("%w: %w", ErrStopCanceled, ctx.Err()),
+		)
+	}
+
+	s.logger.Info("nats server shut down successfully")
+
+	return errors.Join(stoppingErr, stoppedErr)
+}
+
+// shutdown brings the NATS server down by calling fn in the background,
+// then runs the OnStopped hooks and moves the server to StateStopped. The
+// hooks' error is delivered on the returned channel once that is done.
+// The hooks are not bound to ctx ending, since shutdown carries on after
+// the caller has given up on it.
+func (s *Server) shutdown(
+	ctx context.Context,
+	fn func(),
+) <-chan error {
+	// The result channel is buffered so the goroutine can always deliver
+	// and exit, even after the caller stopped listening.
+	stopped := make(chan error, 1)
+	go func() {
+		fn()
+		err := s.runHooks(
+			context.WithoutCancel(ctx),
+			HookStopped,
+			s.options().Hooks.OnStopped,
+		)
+		_, _ = s.transition(StateStopped)
+		stopped <- err
+	}()
+
+	return stopped
+}
+
+// beginStop moves a running server to StateDraining and returns its NATS
+// server. It returns nil when there is nothing to stop, along with a
+// *TransitionError when the server is starting or already stopping.
+func (s *Server) beginStop() (NATSServerInstance, error) {
+	moved, err := s.transition(
+		StateDraining,
+		StateNew,
+		StateStopped,
+		StateFailed,
+	)
+	if !moved {
+		return nil, err
+	}
+
+	return s.instance(), nil
+}
+
+// failStart moves a server whose start has been abandoned to StateFailed,
+// returning a *StartError for cause, the reason it failed in phase.
+func (s *Server) failStart(
+	phase StartPhase,
+	cause error,
+) error {
+	err := &StartError{
+		Phase: phase,
+		Cause: cause,
+	}
+
+	// Only the starting goroutine moves a server out of StateStarting.
+	_ = s.fail(nil, err)
+
+	return err
+}
+
+// Error describes the phase the start failed in and why.
+func (e *StartError) Error() string {
+	return fmt.Sprintf("error starting server (%s): %v", e.Phase, e.Cause)
+}
+
+// Unwrap returns the cause of the failure.
+func (e *StartError) Unwrap() error {
+	return e.Cause
+}
+
+// options returns Opts, which Reload may replace.
+func (s *Server) options() *Options {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.Opts
+}
+
+// natsOptions returns the copy of the options the NATS server is given,
+// with secret references resolved, passwords checked, and TLSFiles loaded.
+func (o *Options) natsOptions(
+	ctx context.Context,
+) (*natsserver.Options, error) {
+	natsOpts := o.Options.Clone()
+	if err := o.resolveSecrets(ctx, natsOpts); err != nil {
+		return nil, err
+	}
+
+	if err := o.checkHashedPasswords(natsOpts); err != nil {
+		return nil, err
+	}
+
+	if err := o.loadTLSFiles(ctx, natsOpts); err != nil {
+		return nil, err
+	}
+
+	return natsOpts, nil
+}
+
+// readyTimeout returns how long StartContext waits for readiness: the
+// configured timeout, shortened to the time left before ctx's deadline.
+func readyTimeout(
+	ctx context.Context,
+	timeout time.Duration,
+) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			timeout = remaining
+		}
+	}
+
+	return timeout
+}
+
+// abortStart shuts down a server whose startup is being abandoned and waits
+// for the goroutine running its Start method to return, so that no
+// goroutine outlives the failed start.
+func abortStart(
+	natsServer NATSServerInstance,
+	started <-chan struct{},
+) {
+	natsServer.Shutdown()
+	<-started
+}
+
+// newRun returns a run that has not ended.
+func newRun() *run {
+	return &run{
+		done: make(chan struct{}),
 	}
 }
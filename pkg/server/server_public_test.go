@@ -21,6 +21,7 @@
 package server_test
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"os"
@@ -42,6 +43,7 @@ type ServerPublicTestSuite struct {
 	mockNATSServer *mocks.MockNATSServerInstance
 	logger         *slog.Logger
 	srv            *server.Server
+	terminate      func()
 }
 
 func (s *ServerPublicTestSuite) SetupTest() {
@@ -52,13 +54,19 @@ func (s *ServerPublicTestSuite) SetupTest() {
 		Options:      &natsserver.Options{},
 		ReadyTimeout: 5 * time.Second,
 	})
+	s.terminate = expectWaitForShutdown(s.mockNATSServer)
 }
 
 func (s *ServerPublicTestSuite) SetupSubTest() {
 	s.SetupTest()
 }
 
+func (s *ServerPublicTestSuite) TearDownSubTest() {
+	s.terminate()
+}
+
 func (s *ServerPublicTestSuite) TearDownTest() {
+	s.terminate()
 	s.mockCtrl.Finish()
 }
 
@@ -107,7 +115,7 @@ func (s *ServerPublicTestSuite) TestStart() {
 					return nil, errors.New("invalid options")
 				}
 			},
-			expectedErr: "error starting server: invalid options",
+			expectedErr: "error starting server (create): invalid options",
 		},
 		{
 			name: "returns error when not ready for connections",
@@ -122,8 +130,39 @@ func (s *ServerPublicTestSuite) TestStart() {
 					ReadyForConnections(gomock.Any()).
 					Return(false).
 					Times(1)
+				s.mockNATSServer.EXPECT().Shutdown().Times(1)
+			},
+			expectedErr: "error starting server (ready): " +
+				"server not ready for connections",
+		},
+		{
+			name: "returns error when options are invalid",
+			mockSetup: func() {
+				s.srv.Opts.ReadyTimeout = 0
 			},
-			expectedErr: "server not ready for connections",
+			expectedErr: "error starting server (validate): invalid options: " +
+				"ready timeout must be positive, got 0s",
+		},
+		{
+			name: "returns error when already running",
+			mockSetup: func() {
+				server.NewNATSServer = func(
+					_ *natsserver.Options,
+				) (server.NATSServerInstance, error) {
+					return s.mockNATSServer, nil
+				}
+				s.mockNATSServer.EXPECT().Start().AnyTimes()
+				s.mockNATSServer.EXPECT().
+					ReadyForConnections(gomock.Any()).
+					Return(true).
+					Times(1)
+				s.mockNATSServer.EXPECT().
+					SetLogger(gomock.Any(), true, true).
+					Times(1)
+				s.Require().NoError(s.srv.Start())
+			},
+			expectedErr: "server already started: " +
+				"invalid server state transition from running to starting",
 		},
 	}
 
@@ -145,6 +184,98 @@ func (s *ServerPublicTestSuite) TestStart() {
 	}
 }
 
+func (s *ServerPublicTestSuite) TestStartContext() {
+	tests := []struct {
+		name         string
+		mockSetup    func(release <-chan struct{})
+		ctxFunc      func() (context.Context, context.CancelFunc)
+		validateFunc func(err error)
+	}{
+		{
+			name: "starts server when ready before deadline",
+			mockSetup: func(_ <-chan struct{}) {
+				s.mockNATSServer.EXPECT().Start().AnyTimes()
+				s.mockNATSServer.EXPECT().
+					ReadyForConnections(gomock.Any()).
+					DoAndReturn(func(timeout time.Duration) bool {
+						s.LessOrEqual(timeout, time.Second)
+						return true
+					}).
+					Times(1)
+				s.mockNATSServer.EXPECT().
+					SetLogger(gomock.Any(), true, true).
+					Times(1)
+			},
+			ctxFunc: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name: "returns error and shuts down when deadline expires",
+			mockSetup: func(release <-chan struct{}) {
+				s.mockNATSServer.EXPECT().Start().Times(1)
+				s.mockNATSServer.EXPECT().
+					ReadyForConnections(gomock.Any()).
+					DoAndReturn(func(_ time.Duration) bool {
+						<-release
+						return false
+					}).
+					Times(1)
+				s.mockNATSServer.EXPECT().Shutdown().Times(1)
+			},
+			ctxFunc: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(
+					context.Background(),
+					10*time.Millisecond,
+				)
+			},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrStartCanceled)
+				s.ErrorIs(err, context.DeadlineExceeded)
+			},
+		},
+		{
+			name:      "returns error without starting when context is done",
+			mockSetup: func(_ <-chan struct{}) {},
+			ctxFunc: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrStartCanceled)
+				s.ErrorIs(err, context.Canceled)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			originalNewNATSServer := server.NewNATSServer
+			defer func() { server.NewNATSServer = originalNewNATSServer }()
+
+			server.NewNATSServer = func(
+				_ *natsserver.Options,
+			) (server.NATSServerInstance, error) {
+				return s.mockNATSServer, nil
+			}
+
+			release := make(chan struct{})
+			defer close(release)
+
+			tc.mockSetup(release)
+
+			ctx, cancel := tc.ctxFunc()
+			defer cancel()
+
+			tc.validateFunc(s.srv.StartContext(ctx))
+		})
+	}
+}
+
 func (s *ServerPublicTestSuite) TestStop() {
 	tests := []struct {
 		name      string
@@ -195,6 +326,113 @@ func (s *ServerPublicTestSuite) TestStop() {
 	}
 }
 
+func (s *ServerPublicTestSuite) TestStopContext() {
+	tests := []struct {
+		name         string
+		start        bool
+		mockSetup    func(release <-chan struct{})
+		validateFunc func(err error)
+	}{
+		{
+			name:  "stops running server",
+			start: true,
+			mockSetup: func(_ <-chan struct{}) {
+				s.mockNATSServer.EXPECT().Shutdown().Times(1)
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name:  "returns error when shutdown outlasts deadline",
+			start: true,
+			mockSetup: func(release <-chan struct{}) {
+				s.mockNATSServer.EXPECT().
+					Shutdown().
+					Do(func() { <-release }).
+					Times(1)
+			},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrStopCanceled)
+				s.ErrorIs(err, context.DeadlineExceeded)
+			},
+		},
+		{
+			name:  "drains server when configured to drain on stop",
+			start: true,
+			mockSetup: func(_ <-chan struct{}) {
+				s.srv.Opts.DrainOnStop = true
+				s.mockNATSServer.EXPECT().NumClients().Return(0).AnyTimes()
+				s.mockNATSServer.EXPECT().LameDuckShutdown().Times(1)
+				s.mockNATSServer.EXPECT().Shutdown().Times(1)
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name:      "returns nil when server never started",
+			mockSetup: func(_ <-chan struct{}) {},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			originalNewNATSServer := server.NewNATSServer
+			defer func() { server.NewNATSServer = originalNewNATSServer }()
+
+			server.NewNATSServer = func(
+				_ *natsserver.Options,
+			) (server.NATSServerInstance, error) {
+				return s.mockNATSServer, nil
+			}
+
+			if tc.start {
+				s.mockNATSServer.EXPECT().Start().AnyTimes()
+				s.mockNATSServer.EXPECT().
+					ReadyForConnections(gomock.Any()).
+					Return(true)
+				s.mockNATSServer.EXPECT().SetLogger(gomock.Any(), true, true)
+				s.Require().NoError(s.srv.Start())
+			}
+
+			release := make(chan struct{})
+			defer close(release)
+
+			tc.mockSetup(release)
+
+			ctx, cancel := context.WithTimeout(
+				context.Background(),
+				50*time.Millisecond,
+			)
+			defer cancel()
+
+			tc.validateFunc(s.srv.StopContext(ctx))
+		})
+	}
+}
+
+func (s *ServerPublicTestSuite) TestError() {
+	err := &server.StartError{
+		Phase: server.StartPhaseCreate,
+		Cause: errors.New("invalid options"),
+	}
+
+	s.EqualError(err, "error starting server (create): invalid options")
+}
+
+func (s *ServerPublicTestSuite) TestUnwrap() {
+	err := &server.StartError{
+		Phase: server.StartPhaseReady,
+		Cause: server.ErrNotReady,
+	}
+
+	s.ErrorIs(err, server.ErrNotReady)
+}
+
 func TestServerPublicTestSuite(t *testing.T) {
 	suite.Run(t, new(ServerPublicTestSuite))
 }
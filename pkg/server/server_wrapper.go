@@ -21,23 +21,64 @@
 package server
 
 import (
+	"fmt"
+	"net"
+	"strings"
 	"time"
 
 	natsserver "github.com/nats-io/nats-server/v2/server"
 )
 
 // NATSServerInstance defines an interface for the NATS server operations
-// used by Start() and Stop().
+// used by the Server lifecycle methods.
 type NATSServerInstance interface {
 	Start()
 	ReadyForConnections(timeout time.Duration) bool
 	SetLogger(logger natsserver.Logger, debug, trace bool)
 	Shutdown()
+	LameDuckShutdown()
+	NumClients() int
+	WaitForShutdown()
+	ReloadOptions(opts *natsserver.Options) error
+	LookupAccount(name string) (*natsserver.Account, error)
+	InProcessConn() (net.Conn, error)
 }
 
 // NewNATSServer is a public variable function wrapping natsserver.NewServer.
 var NewNATSServer = func(
 	opts *natsserver.Options,
 ) (NATSServerInstance, error) {
-	return natsserver.NewServer(opts)
+	ns, err := natsserver.NewServer(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &natsServer{Server: ns}, nil
+}
+
+// natsServer is a NATS server whose ReloadOptions errors for options it
+// cannot reload match ErrNotReloadable.
+type natsServer struct {
+	*natsserver.Server
+}
+
+// ReloadOptions reloads the NATS server with opts.
+func (s *natsServer) ReloadOptions(
+	opts *natsserver.Options,
+) error {
+	err := s.Server.ReloadOptions(opts)
+	if err == nil {
+		return nil
+	}
+
+	// The NATS server names the options it cannot reload only in the
+	// text of its error.
+	if rest, ok := strings.CutPrefix(
+		err.Error(),
+		ErrNotReloadable.Error(),
+	); ok {
+		return fmt.Errorf("%w%s", ErrNotReloadable, rest)
+	}
+
+	return err
 }
@@ -0,0 +1,194 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"fmt"
+	"slices"
+)
+
+// validTransitions lists, for each state, the states a server may move to.
+var validTransitions = map[State][]State{
+	StateNew:      {StateStarting},
+	StateStarting: {StateRunning, StateFailed},
+	StateRunning:  {StateDraining, StateFailed},
+	StateDraining: {StateStopped},
+	StateStopped:  {StateStarting},
+	StateFailed:   {StateStarting},
+}
+
+// String returns the lower-case name of the state.
+func (st State) String() string {
+	switch st {
+	case StateNew:
+		return "new"
+	case StateStarting:
+		return "starting"
+	case StateRunning:
+		return "running"
+	case StateDraining:
+		return "draining"
+	case StateStopped:
+		return "stopped"
+	case StateFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("state(%d)", int(st))
+	}
+}
+
+// Error describes the rejected transition.
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s from %s to %s", ErrInvalidTransition, e.From, e.To)
+}
+
+// Is reports whether target is ErrInvalidTransition.
+func (e *TransitionError) Is(
+	target error,
+) bool {
+	return target == ErrInvalidTransition
+}
+
+// State returns the current lifecycle state of the server.
+//
+// A server moves from StateNew to StateStarting when started, and from there
+// to StateRunning once ready or StateFailed if the start fails. Stopping or
+// draining a running server moves it to StateDraining and, once the NATS
+// server is down, to StateStopped. A running server whose NATS server shuts
+// down on its own moves to StateFailed. A stopped or failed server may be
+// started again.
+func (s *Server) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state
+}
+
+// Subscribe registers fn to be called with every later state transition, in
+// the order transitions happen, and returns a function that unregisters it.
+// fn runs synchronously on the goroutine making the transition, so it must
+// return promptly and must not call the server's lifecycle methods.
+func (s *Server) Subscribe(
+	fn func(Transition),
+) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextSubID
+	s.nextSubID++
+	s.subscribers = append(s.subscribers, subscriber{id: id, fn: fn})
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.subscribers = slices.DeleteFunc(
+			s.subscribers,
+			func(sub subscriber) bool { return sub.id == id },
+		)
+	}
+}
+
+// fail moves the server to StateFailed, recording cause as the reason r,
+// its current run, ended. It returns a *TransitionError when the server is
+// no longer in a state that can fail, or has started another run since.
+func (s *Server) fail(
+	r *run,
+	cause error,
+) error {
+	_, err := s.move(StateFailed, r, cause)
+
+	return err
+}
+
+// transition moves the server to state to and notifies subscribers. When
+// the current state is one of skip, nothing happens and moved is false. A
+// move the lifecycle does not allow returns a *TransitionError.
+func (s *Server) transition(
+	to State,
+	skip ...State,
+) (bool, error) {
+	return s.move(to, nil, nil, skip...)
+}
+
+// move implements transition and fail. When r is not nil, the move is only
+// allowed while r is the current run. Moving to StateStopped or StateFailed
+// ends the run, recording cause and closing the channel returned by Done;
+// moving to StateStarting again begins a new one.
+func (s *Server) move(
+	to State,
+	r *run,
+	cause error,
+	skip ...State,
+) (bool, error) {
+	s.transitionMu.Lock()
+	defer s.transitionMu.Unlock()
+
+	s.mu.Lock()
+	from := s.state
+	if slices.Contains(skip, from) {
+		s.mu.Unlock()
+		return false, nil
+	}
+	if !slices.Contains(validTransitions[from], to) ||
+		(r != nil && r != s.run) {
+		s.mu.Unlock()
+		return false, &TransitionError{From: from, To: to}
+	}
+	s.state = to
+	switch to {
+	case StateStarting:
+		if from != StateNew {
+			s.run = newRun()
+		}
+	case StateStopped, StateFailed:
+		s.run.cause = cause
+		close(s.run.done)
+		if s.run.storeLock != nil {
+			_ = s.run.storeLock.Close()
+		}
+		s.run.callout.stop()
+		s.run.operator.stop()
+		s.run.certWatcher.stop()
+	}
+	subscribers := slices.Clone(s.subscribers)
+	s.mu.Unlock()
+
+	s.logger.Debug(
+		"nats server state changed",
+		"from", from.String(),
+		"to", to.String(),
+	)
+
+	for _, sub := range subscribers {
+		sub.fn(Transition{From: from, To: to})
+	}
+
+	return true, nil
+}
+
+// instance returns the NATS server most recently started.
+func (s *Server) instance() NATSServerInstance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.natsServer
+}
@@ -0,0 +1,321 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+type StatePublicTestSuite struct {
+	suite.Suite
+
+	mockCtrl       *gomock.Controller
+	mockNATSServer *mocks.MockNATSServerInstance
+	srv            *server.Server
+	terminate      func()
+	newNATSServer  func(*natsserver.Options) (server.NATSServerInstance, error)
+}
+
+func (s *StatePublicTestSuite) SetupSuite() {
+	s.newNATSServer = server.NewNATSServer
+}
+
+func (s *StatePublicTestSuite) TearDownSuite() {
+	server.NewNATSServer = s.newNATSServer
+}
+
+func (s *StatePublicTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockNATSServer = mocks.NewMockNATSServerInstance(s.mockCtrl)
+	s.terminate = expectWaitForShutdown(s.mockNATSServer)
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		&server.Options{
+			Options:      &natsserver.Options{},
+			ReadyTimeout: 5 * time.Second,
+		},
+	)
+
+	server.NewNATSServer = func(
+		_ *natsserver.Options,
+	) (server.NATSServerInstance, error) {
+		return s.mockNATSServer, nil
+	}
+}
+
+func (s *StatePublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *StatePublicTestSuite) TearDownSubTest() {
+	s.terminate()
+}
+
+func (s *StatePublicTestSuite) TearDownTest() {
+	s.terminate()
+	s.mockCtrl.Finish()
+}
+
+func (s *StatePublicTestSuite) TestState() {
+	tests := []struct {
+		name         string
+		setup        func()
+		validateFunc func(state server.State)
+	}{
+		{
+			name:  "new server",
+			setup: func() {},
+			validateFunc: func(state server.State) {
+				s.Equal(server.StateNew, state)
+			},
+		},
+		{
+			name: "started server",
+			setup: func() {
+				expectStart(s.mockNATSServer)
+				s.Require().NoError(s.srv.Start())
+			},
+			validateFunc: func(state server.State) {
+				s.Equal(server.StateRunning, state)
+			},
+		},
+		{
+			name: "server whose start failed",
+			setup: func() {
+				expectNotReady(s.mockNATSServer)
+				s.Require().Error(s.srv.Start())
+			},
+			validateFunc: func(state server.State) {
+				s.Equal(server.StateFailed, state)
+			},
+		},
+		{
+			name: "stopped server",
+			setup: func() {
+				expectStart(s.mockNATSServer)
+				s.mockNATSServer.EXPECT().Shutdown()
+				s.Require().NoError(s.srv.Start())
+				s.srv.Stop()
+			},
+			validateFunc: func(state server.State) {
+				s.Equal(server.StateStopped, state)
+			},
+		},
+		{
+			name: "server still shutting down",
+			setup: func() {
+				release := make(chan struct{})
+				s.T().Cleanup(func() { close(release) })
+
+				expectStart(s.mockNATSServer)
+				s.mockNATSServer.EXPECT().Shutdown().Do(func() { <-release })
+				s.Require().NoError(s.srv.Start())
+
+				ctx, cancel := context.WithTimeout(
+					context.Background(),
+					10*time.Millisecond,
+				)
+				defer cancel()
+				s.Require().ErrorIs(
+					s.srv.StopContext(ctx),
+					server.ErrStopCanceled,
+				)
+			},
+			validateFunc: func(state server.State) {
+				s.Equal(server.StateDraining, state)
+			},
+		},
+		{
+			name: "restarted server",
+			setup: func() {
+				expectStart(s.mockNATSServer)
+				s.mockNATSServer.EXPECT().Shutdown()
+				expectStart(s.mockNATSServer)
+				s.Require().NoError(s.srv.Start())
+				s.srv.Stop()
+				s.Require().NoError(s.srv.Start())
+			},
+			validateFunc: func(state server.State) {
+				s.Equal(server.StateRunning, state)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+
+			tc.validateFunc(s.srv.State())
+		})
+	}
+}
+
+func (s *StatePublicTestSuite) TestSubscribe() {
+	tests := []struct {
+		name         string
+		run          func(unsubscribe func())
+		validateFunc func(transitions []server.Transition)
+	}{
+		{
+			name: "observes every transition in order",
+			run: func(_ func()) {
+				expectStart(s.mockNATSServer)
+				s.mockNATSServer.EXPECT().Shutdown()
+				s.Require().NoError(s.srv.Start())
+				s.srv.Stop()
+			},
+			validateFunc: func(transitions []server.Transition) {
+				s.Equal([]server.Transition{
+					{From: server.StateNew, To: server.StateStarting},
+					{From: server.StateStarting, To: server.StateRunning},
+					{From: server.StateRunning, To: server.StateDraining},
+					{From: server.StateDraining, To: server.StateStopped},
+				}, transitions)
+			},
+		},
+		{
+			name: "observes a failed start",
+			run: func(_ func()) {
+				expectNotReady(s.mockNATSServer)
+				s.Require().Error(s.srv.Start())
+			},
+			validateFunc: func(transitions []server.Transition) {
+				s.Equal([]server.Transition{
+					{From: server.StateNew, To: server.StateStarting},
+					{From: server.StateStarting, To: server.StateFailed},
+				}, transitions)
+			},
+		},
+		{
+			name: "stops observing once unsubscribed",
+			run: func(unsubscribe func()) {
+				expectStart(s.mockNATSServer)
+				unsubscribe()
+				s.Require().NoError(s.srv.Start())
+			},
+			validateFunc: func(transitions []server.Transition) {
+				s.Empty(transitions)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			var (
+				mu          sync.Mutex
+				transitions []server.Transition
+			)
+
+			unsubscribe := s.srv.Subscribe(func(t server.Transition) {
+				mu.Lock()
+				defer mu.Unlock()
+				transitions = append(transitions, t)
+			})
+
+			tc.run(unsubscribe)
+
+			mu.Lock()
+			defer mu.Unlock()
+			tc.validateFunc(transitions)
+		})
+	}
+}
+
+func (s *StatePublicTestSuite) TestString() {
+	tests := []struct {
+		name     string
+		state    server.State
+		expected string
+	}{
+		{name: "new", state: server.StateNew, expected: "new"},
+		{name: "starting", state: server.StateStarting, expected: "starting"},
+		{name: "running", state: server.StateRunning, expected: "running"},
+		{name: "draining", state: server.StateDraining, expected: "draining"},
+		{name: "stopped", state: server.StateStopped, expected: "stopped"},
+		{name: "failed", state: server.StateFailed, expected: "failed"},
+		{name: "unknown", state: server.State(42), expected: "state(42)"},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.Equal(tc.expected, tc.state.String())
+		})
+	}
+}
+
+func (s *StatePublicTestSuite) TestError() {
+	err := &server.TransitionError{
+		From: server.StateRunning,
+		To:   server.StateStarting,
+	}
+
+	s.EqualError(
+		err,
+		"invalid server state transition from running to starting",
+	)
+}
+
+func (s *StatePublicTestSuite) TestIs() {
+	tests := []struct {
+		name     string
+		target   error
+		expected bool
+	}{
+		{
+			name:     "matches ErrInvalidTransition",
+			target:   server.ErrInvalidTransition,
+			expected: true,
+		},
+		{
+			name:     "does not match other errors",
+			target:   errors.New("other"),
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			err := &server.TransitionError{
+				From: server.StateDraining,
+				To:   server.StateDraining,
+			}
+
+			s.Equal(tc.expected, errors.Is(err, tc.target))
+		})
+	}
+}
+
+func TestStatePublicTestSuite(t *testing.T) {
+	suite.Run(t, new(StatePublicTestSuite))
+}
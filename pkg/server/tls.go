@@ -0,0 +1,160 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+)
+
+// loadTLSFiles loads the TLS files of each listener into its TLS settings
+// in natsOpts, the copy of the options the NATS server is given. Nothing
+// is loaded for a listener without TLS files. A KeyFile that is a secret
+// reference is resolved to the key.
+func (o *Options) loadTLSFiles(
+	ctx context.Context,
+	natsOpts *natsserver.Options,
+) error {
+	for _, l := range o.tlsListeners(natsOpts) {
+		if l.files == nil {
+			continue
+		}
+
+		config, err := o.loadTLSConfig(ctx, l.field, l.files)
+		if err != nil {
+			return fmt.Errorf("error loading %s: %w", l.label, err)
+		}
+		*l.config = config
+	}
+
+	if o.TLSFiles != nil {
+		natsOpts.TLS = true
+		natsOpts.TLSVerify = o.TLSFiles.Verify
+	}
+
+	// Routes are verified both ways, as the NATS server does for the
+	// cluster TLS of a configuration file, since each server dials the
+	// others as a client too.
+	if o.ClusterTLSFiles != nil {
+		cluster := natsOpts.Cluster.TLSConfig
+		cluster.ClientAuth = tls.RequireAndVerifyClientCert
+		cluster.RootCAs = cluster.ClientCAs
+	}
+
+	return nil
+}
+
+// loadTLSConfig returns the TLS configuration of files, the TLS files of
+// the options' field.
+func (o *Options) loadTLSConfig(
+	ctx context.Context,
+	field string,
+	files *TLSFiles,
+) (*tls.Config, error) {
+	tlsOpts := &natsserver.TLSConfigOpts{
+		CertFile: files.CertFile,
+		KeyFile:  files.KeyFile,
+		CaFile:   files.CAFile,
+		Verify:   files.Verify,
+	}
+
+	var cert *tls.Certificate
+	if isSecretRef(files.KeyFile) {
+		key, err := o.resolveSecret(ctx, field+".KeyFile", files.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if cert, err = loadKeyPair(files.CertFile, key); err != nil {
+			return nil, err
+		}
+		tlsOpts.CertFile, tlsOpts.KeyFile = "", ""
+	}
+
+	config, err := natsserver.GenTLSConfig(tlsOpts)
+	if err != nil {
+		return nil, err
+	}
+	if cert != nil {
+		config.Certificates = []tls.Certificate{*cert}
+	}
+
+	return config, nil
+}
+
+// tlsListeners returns the listeners of natsOpts that TLS files can
+// secure, with the files the options give each.
+func (o *Options) tlsListeners(
+	natsOpts *natsserver.Options,
+) []tlsListener {
+	return []tlsListener{
+		{
+			name:   "client",
+			field:  "TLSFiles",
+			label:  "tls files",
+			files:  o.TLSFiles,
+			config: &natsOpts.TLSConfig,
+		},
+		{
+			name:   "cluster",
+			field:  "ClusterTLSFiles",
+			label:  "cluster tls files",
+			files:  o.ClusterTLSFiles,
+			config: &natsOpts.Cluster.TLSConfig,
+		},
+		{
+			name:   "leafnode",
+			field:  "LeafNodeTLSFiles",
+			label:  "leafnode tls files",
+			files:  o.LeafNodeTLSFiles,
+			config: &natsOpts.LeafNode.TLSConfig,
+		},
+		{
+			name:   "websocket",
+			field:  "WebsocketTLSFiles",
+			label:  "websocket tls files",
+			files:  o.WebsocketTLSFiles,
+			config: &natsOpts.Websocket.TLSConfig,
+		},
+	}
+}
+
+// loadKeyPair returns the certificate in certFile with the PEM-encoded key.
+func loadKeyPair(
+	certFile string,
+	key string,
+) (*tls.Certificate, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing certificate and key: %w", err)
+	}
+
+	return &cert, nil
+}
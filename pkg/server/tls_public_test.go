@@ -0,0 +1,357 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+type TLSPublicTestSuite struct {
+	suite.Suite
+
+	mockCtrl       *gomock.Controller
+	mockNATSServer *mocks.MockNATSServerInstance
+	srv            *server.Server
+	started        []*natsserver.Options
+	terminate      func()
+	newNATSServer  func(*natsserver.Options) (server.NATSServerInstance, error)
+	files          *server.TLSFiles
+}
+
+func (s *TLSPublicTestSuite) SetupSuite() {
+	s.newNATSServer = server.NewNATSServer
+}
+
+func (s *TLSPublicTestSuite) TearDownSuite() {
+	server.NewNATSServer = s.newNATSServer
+}
+
+func (s *TLSPublicTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockNATSServer = mocks.NewMockNATSServerInstance(s.mockCtrl)
+	s.terminate = expectWaitForShutdown(s.mockNATSServer)
+	s.started = nil
+	s.files = writeCertificate(s.T(), s.T().TempDir())
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		&server.Options{
+			Options:      &natsserver.Options{},
+			ReadyTimeout: 5 * time.Second,
+		},
+	)
+
+	server.NewNATSServer = func(
+		opts *natsserver.Options,
+	) (server.NATSServerInstance, error) {
+		s.started = append(s.started, opts)
+		return s.mockNATSServer, nil
+	}
+}
+
+func (s *TLSPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *TLSPublicTestSuite) TearDownSubTest() {
+	s.terminate()
+}
+
+func (s *TLSPublicTestSuite) TearDownTest() {
+	s.terminate()
+	s.mockCtrl.Finish()
+}
+
+// writeCertificate writes a self-signed certificate for localhost and its
+// key to dir, returning them as TLS files that trust the certificate.
+func writeCertificate(
+	t *testing.T,
+	dir string,
+) *server.TLSFiles {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage: x509.KeyUsageDigitalSignature |
+			x509.KeyUsageCertSign,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth,
+			x509.ExtKeyUsageClientAuth,
+		},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(
+		rand.Reader,
+		template,
+		template,
+		&key.PublicKey,
+		key,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := &server.TLSFiles{
+		CertFile: filepath.Join(dir, "server.pem"),
+		KeyFile:  filepath.Join(dir, "server-key.pem"),
+		CAFile:   filepath.Join(dir, "server.pem"),
+	}
+	for path, block := range map[string]*pem.Block{
+		files.CertFile: {Type: "CERTIFICATE", Bytes: der},
+		files.KeyFile:  {Type: "EC PRIVATE KEY", Bytes: keyDER},
+	} {
+		err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return files
+}
+
+func (s *TLSPublicTestSuite) TestStart() {
+	tests := []struct {
+		name         string
+		files        func() *server.TLSFiles
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name:      "loads tls files into nats server options",
+			files:     func() *server.TLSFiles { return s.files },
+			mockSetup: func() { expectStart(s.mockNATSServer) },
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Require().Len(s.started, 1)
+				s.True(s.started[0].TLS)
+				s.Require().NotNil(s.started[0].TLSConfig)
+				s.Len(s.started[0].TLSConfig.Certificates, 1)
+				s.False(s.started[0].TLSVerify)
+				s.Nil(s.srv.Opts.TLSConfig)
+			},
+		},
+		{
+			name: "requires client certificates when verifying",
+			files: func() *server.TLSFiles {
+				files := *s.files
+				files.Verify = true
+				return &files
+			},
+			mockSetup: func() { expectStart(s.mockNATSServer) },
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.True(s.started[0].TLSVerify)
+				s.Equal(
+					tls.RequireAndVerifyClientCert,
+					s.started[0].TLSConfig.ClientAuth,
+				)
+			},
+		},
+		{
+			name: "resolves key given as a secret reference",
+			files: func() *server.TLSFiles {
+				key, err := os.ReadFile(s.files.KeyFile)
+				s.Require().NoError(err)
+				s.T().Setenv("TLS_TEST_KEY", string(key))
+
+				files := *s.files
+				files.KeyFile = "env://TLS_TEST_KEY"
+				return &files
+			},
+			mockSetup: func() { expectStart(s.mockNATSServer) },
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Require().Len(s.started[0].TLSConfig.Certificates, 1)
+				cert := s.started[0].TLSConfig.Certificates[0]
+				s.Equal("localhost", cert.Leaf.Subject.CommonName)
+				s.NotNil(s.started[0].TLSConfig.ClientCAs)
+				s.Equal("env://TLS_TEST_KEY", s.srv.Opts.TLSFiles.KeyFile)
+			},
+		},
+		{
+			name: "fails start when key reference cannot be resolved",
+			files: func() *server.TLSFiles {
+				files := *s.files
+				files.KeyFile = "env://TLS_TEST_MISSING_KEY"
+				return &files
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				var secretErr *server.SecretError
+				s.Require().ErrorAs(err, &secretErr)
+				s.Equal("TLSFiles.KeyFile", secretErr.Field)
+				s.Empty(s.started)
+			},
+		},
+		{
+			name: "fails start when referenced key is invalid",
+			files: func() *server.TLSFiles {
+				s.T().Setenv("TLS_TEST_KEY", "not a key")
+
+				files := *s.files
+				files.KeyFile = "env://TLS_TEST_KEY"
+				return &files
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorContains(err, "error loading tls files: "+
+					"error parsing certificate and key: ")
+				s.NotContains(err.Error(), "not a key")
+			},
+		},
+		{
+			name: "fails start when certificate of referenced key is missing",
+			files: func() *server.TLSFiles {
+				s.T().Setenv("TLS_TEST_KEY", "not a key")
+
+				return &server.TLSFiles{
+					CertFile: filepath.Join(s.T().TempDir(), "missing.pem"),
+					KeyFile:  "env://TLS_TEST_KEY",
+				}
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, os.ErrNotExist)
+			},
+		},
+		{
+			name: "fails start when files cannot be loaded",
+			files: func() *server.TLSFiles {
+				return &server.TLSFiles{
+					CertFile: filepath.Join(s.T().TempDir(), "missing.pem"),
+					KeyFile:  s.files.KeyFile,
+				}
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				var startErr *server.StartError
+				s.Require().ErrorAs(err, &startErr)
+				s.Equal(server.StartPhaseCreate, startErr.Phase)
+				s.ErrorContains(err, "error loading tls files")
+				s.Empty(s.started)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.srv.Opts.TLSFiles = tc.files()
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.Start())
+		})
+	}
+}
+
+func (s *TLSPublicTestSuite) TestReload() {
+	tests := []struct {
+		name         string
+		files        func() *server.TLSFiles
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name:  "loads tls files into reloaded options",
+			files: func() *server.TLSFiles { return s.files },
+			mockSetup: func() {
+				s.mockNATSServer.EXPECT().
+					ReloadOptions(gomock.Any()).
+					DoAndReturn(func(opts *natsserver.Options) error {
+						s.True(opts.TLS)
+						s.NotNil(opts.TLSConfig)
+						return nil
+					})
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name: "fails reload when files cannot be loaded",
+			files: func() *server.TLSFiles {
+				return &server.TLSFiles{
+					CertFile: s.files.CertFile,
+					KeyFile:  filepath.Join(s.T().TempDir(), "missing.pem"),
+				}
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorContains(
+					err,
+					"error reloading server: error loading tls files",
+				)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.srv.Opts.TLSFiles = s.files
+			expectStart(s.mockNATSServer)
+			s.Require().NoError(s.srv.Start())
+
+			newOpts := *s.srv.Opts
+			newOpts.Options = s.srv.Opts.Options.Clone()
+			newOpts.TLSFiles = tc.files()
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.Reload(&newOpts))
+		})
+	}
+}
+
+func TestTLSPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(TLSPublicTestSuite))
+}
@@ -21,16 +21,55 @@
 package server
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
 	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"sync"
 	"time"
 
+	"github.com/nats-io/jwt/v2"
 	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nats-io/nkeys"
 )
 
 // Server provides an embedded NATS server implementation.
 type Server struct {
-	logger     *slog.Logger
+	logger *slog.Logger
+
+	// transitionMu serializes transitions, so that subscribers observe them
+	// in the order they happen.
+	transitionMu sync.Mutex
+
+	// reloadMu serializes Reload and Restart.
+	reloadMu sync.Mutex
+
+	// mu guards the fields below it, and the replacement of Opts.
+	mu         sync.Mutex
 	natsServer NATSServerInstance
+	// running is the copy of Opts.Options the NATS server was started or
+	// last reloaded with.
+	running     *natsserver.Options
+	state       State
+	subscribers []subscriber
+	nextSubID   int
+
+	// run is the most recent run of the server; see Done and Wait.
+	run *run
+
+	// operator holds the keys and JWTs of operator mode, kept across
+	// restarts once the server started with Options.Operator.
+	operator *operator
+
+	// devCA is the certificate authority of development TLS, kept across
+	// restarts once the server started with Options.DevTLS.
+	devCA *devCA
 
 	// Opts configuration options for the embedded NATS server.
 	Opts *Options
@@ -40,4 +79,886 @@ type Server struct {
 type Options struct {
 	*natsserver.Options
 	ReadyTimeout time.Duration
+
+	// AllowRestart lets Reload fall back to restarting the server when the
+	// new options change settings that cannot be reloaded in place, rather
+	// than returning an error. Clients are disconnected by the restart.
+	AllowRestart bool
+
+	// DrainOnStop makes Stop and StopContext drain the server in lame-duck
+	// mode before shutting it down, as Drain does. How long clients are
+	// given is set by LameDuckDuration and LameDuckGracePeriod.
+	DrainOnStop bool
+
+	// Hooks are run at points in the server lifecycle.
+	Hooks Hooks
+
+	// TLSFiles, when set, secures client connections with certificates
+	// loaded from files each time the server starts or reloads, replacing
+	// TLSConfig.
+	TLSFiles *TLSFiles
+
+	// ClusterTLSFiles, LeafNodeTLSFiles, and WebsocketTLSFiles, when set,
+	// secure the cluster, leafnode, and websocket listeners as TLSFiles
+	// does client connections, replacing their TLSConfig. Routes are
+	// always verified both ways.
+	ClusterTLSFiles   *TLSFiles
+	LeafNodeTLSFiles  *TLSFiles
+	WebsocketTLSFiles *TLSFiles
+
+	// CertWatcher, when set, reloads the server when the TLS files change
+	// while it runs, and warns of certificates about to expire; see
+	// CertWatcher.
+	CertWatcher *CertWatcher
+
+	// RequireHashedPasswords makes Start and Reload refuse users whose
+	// password is not bcrypt hashed; see HashPassword.
+	RequireHashedPasswords bool
+
+	// SecretResolvers resolve secret references of custom URI schemes,
+	// keyed by scheme, alongside the built-in "file" and "env" schemes;
+	// see SecretResolver.
+	SecretResolvers map[string]SecretResolver
+
+	// DevTLS, when set, secures client connections with certificates the
+	// Server generates itself, for tests and local development; see
+	// DevTLS. Reload cannot change it.
+	DevTLS *DevTLS
+
+	// Authenticator, when set, authenticates clients in place of Users,
+	// Nkeys, and the other authentication settings; see Authenticator.
+	// Reload may replace it, but not add or remove it.
+	Authenticator Authenticator
+
+	// MutualTLS, when set, authenticates clients by the certificates they
+	// present, which must be verified against the certificate authorities
+	// of the client TLS; see MutualTLS. Reload may replace its Mapper, but
+	// not add or remove it.
+	MutualTLS *MutualTLS
+
+	// AuthCallout, when set, runs an auth callout service inside the
+	// server, deciding which clients may connect; see AuthCallout. Reload
+	// may replace its Handler, but not add or remove it.
+	AuthCallout *AuthCallout
+
+	// Operator, when set, runs the server in operator mode, where clients
+	// authenticate with user JWTs issued by the accounts of an operator
+	// the Server holds the keys of; see Operator. Reload cannot change
+	// it.
+	Operator *Operator
+
+	// JetStreamAssets, when set, declares the streams, consumers,
+	// key-value buckets, and object stores Start creates or updates
+	// before the server is ready; see JetStreamAssets. Reload may change
+	// the assets, which Server.ReconcileJetStream then applies, but not
+	// add or remove them or change their account.
+	JetStreamAssets *JetStreamAssets
+}
+
+// Operator configures operator mode. The Server creates the operator, a
+// system account named SystemAccountName, and the accounts named in
+// Accounts the first time it starts, signing their JWTs, and gives them to
+// the NATS server through an account resolver. Account JWTs can then be
+// changed while the server runs; see Server.UpdateAccountJWT.
+type Operator struct {
+	// Name is the name of the operator, DefaultOperatorName when empty.
+	Name string
+	// Dir, when set, is the directory the keys and JWTs are kept in,
+	// created on the first start and loaded on later ones, so they
+	// survive the process. Empty keeps them in memory for as long as the
+	// Server exists.
+	Dir string
+	// Accounts names the accounts created alongside the system account.
+	// With JetStream enabled, they are created with unlimited JetStream.
+	Accounts []string
+	// ResolverDir, when set, is the directory of the NATS server's
+	// account resolver, which keeps the account JWTs pushed to it across
+	// restarts. Empty resolves accounts from memory.
+	ResolverDir string
+}
+
+// UserJWT describes a user to issue credentials for; see
+// NewCredentials.
+type UserJWT struct {
+	// Name names the user in server events and monitoring.
+	Name string
+	// IssuerAccount is the public key of the account the user belongs to,
+	// needed when the JWT is signed with one of the account's signing keys
+	// rather than the account key itself.
+	IssuerAccount string
+	// Permissions limit the user; nil allows everything.
+	Permissions *natsserver.Permissions
+	// Expires, when set, is when the JWT expires, disconnecting the
+	// user's clients.
+	Expires time.Time
+}
+
+// Credentials are a user's JWT and the seed of its key, as held by a
+// .creds file.
+type Credentials struct {
+	// PublicKey is the user's public key, which revokes the user when
+	// given to jwt.AccountClaims.Revoke.
+	PublicKey string
+	JWT       string
+	Seed      []byte
+}
+
+// AuthCallout configures the auth callout service a Server runs for
+// itself. The NATS server sends every client connecting, other than the
+// service's own, to Handler, which decides the account and permissions the
+// client is bound to. The issuer key signing the responses, and the user
+// the service connects as, are generated each time the server starts.
+type AuthCallout struct {
+	// Handler authenticates the clients. It is called for every
+	// connection, so it must be safe for concurrent use. Returning an
+	// error refuses the connection.
+	Handler Authenticator
+	// Account is the account the service runs in, DefaultAuthCalloutAccount
+	// when empty. It is added to the server's accounts when none of
+	// Accounts has its name.
+	Account string
+}
+
+// Authenticator authenticates clients against an identity system of the
+// application's, deciding the account and permissions each is bound to.
+// It is called for every client and leafnode connection, and again for
+// each on reload, so it must be safe for concurrent use. Returning an
+// error refuses the connection; errors other than ErrInvalidCredentials
+// are logged.
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *AuthRequest) (*AuthResult, error)
+}
+
+// AuthenticatorFunc adapts a function to an Authenticator.
+type AuthenticatorFunc func(
+	ctx context.Context,
+	req *AuthRequest,
+) (*AuthResult, error)
+
+// AuthRequest is a connection asking to be authenticated.
+type AuthRequest struct {
+	// ClientID identifies the connection within the server.
+	ClientID uint64
+	// Kind is the kind of connection, natsserver.CLIENT or
+	// natsserver.LEAF.
+	Kind int
+	// RemoteAddr is the address the connection comes from.
+	RemoteAddr net.Addr
+	// Name, Lang, and Version are as the client reported them.
+	Name    string
+	Lang    string
+	Version string
+	// Username, Password, and Token are the credentials the client gave.
+	Username string
+	Password string
+	Token    string
+	// TLS is the state of the connection's TLS, or nil without TLS.
+	TLS *tls.ConnectionState
+}
+
+// AuthResult is how an authenticated connection is bound.
+type AuthResult struct {
+	// Account names the account to bind the connection to, which the
+	// server must know. Empty binds it to the global account.
+	Account string
+	// Permissions limit the connection; nil allows everything.
+	Permissions *natsserver.Permissions
+	// User names the connection in server events and monitoring. Empty
+	// keeps the username the client gave.
+	User string
+	// Expires, when set, disconnects the connection at that time.
+	Expires time.Time
+}
+
+// StaticAuthenticator is an Authenticator checking clients against a
+// fixed set of users, keyed by username.
+type StaticAuthenticator map[string]StaticUser
+
+// StaticUser is a user of a StaticAuthenticator.
+type StaticUser struct {
+	// Password is the user's password, or its bcrypt hash; see
+	// HashPassword.
+	Password    string
+	Account     string
+	Permissions *natsserver.Permissions
+}
+
+// HtpasswdAuthenticator is an Authenticator checking clients against the
+// bcrypt hashed passwords of an htpasswd file, as written by
+// "htpasswd -B", binding every user to Account with Permissions. The file
+// is read again whenever it changes.
+type HtpasswdAuthenticator struct {
+	Path        string
+	Account     string
+	Permissions *natsserver.Permissions
+
+	// mu guards the users read from the file, and the size and
+	// modification time it had when read.
+	mu      sync.Mutex
+	users   map[string][]byte
+	size    int64
+	modTime time.Time
+}
+
+// MutualTLS configures mutual TLS authentication. Client TLS is made to
+// require every client to present a certificate verified against its
+// certificate authorities, as the CAFile of TLSFiles, the certificate
+// authority of DevTLS, or the ClientCAs of TLSConfig, and Mapper maps the
+// verified certificate to the account and permissions the client is bound
+// to. Leafnode connections are authenticated the same way, so the
+// leafnode listener must verify their certificates too.
+type MutualTLS struct {
+	// Mapper maps the clients' certificates. Returning an error refuses
+	// the connection; errors other than ErrInvalidCredentials are logged.
+	Mapper CertificateMapper
+}
+
+// CertificateMapper maps the verified certificate of a client to the
+// account and permissions it is bound to, from the certificate's subject
+// distinguished name or subject alternative names. It is called for every
+// connection, and again for each on reload, so it must be safe for
+// concurrent use. A result naming no User names the connection after the
+// certificate's subject.
+type CertificateMapper interface {
+	MapCertificate(
+		ctx context.Context,
+		cert *x509.Certificate,
+		req *AuthRequest,
+	) (*AuthResult, error)
+}
+
+// CertificateMapperFunc adapts a function to a CertificateMapper.
+type CertificateMapperFunc func(
+	ctx context.Context,
+	cert *x509.Certificate,
+	req *AuthRequest,
+) (*AuthResult, error)
+
+// CertificateUsers is a CertificateMapper binding clients to the user
+// keyed by their certificate's identity: its subject distinguished name,
+// in the RFC 2253 form of pkix.Name.String such as "CN=alice,O=acme", or
+// one of its email, DNS, or URI subject alternative names, looked up in
+// that order. The connection is named after the key matched.
+type CertificateUsers map[string]CertificateUser
+
+// CertificateUser is a user of CertificateUsers.
+type CertificateUser struct {
+	Account     string
+	Permissions *natsserver.Permissions
+}
+
+// JetStreamAssets declares the JetStream assets of an account. Once its
+// Migrations are applied, Start creates the assets missing and updates
+// those whose configuration differs, streams first, then key-value
+// buckets, object stores, and consumers, so consumers may be on any of
+// them. A difference the NATS server cannot apply in place, such as a
+// stream's storage, is drift: the asset is left as it is and the drift is
+// logged and returned by Server.ReconcileJetStream. Assets not declared
+// are left alone.
+type JetStreamAssets struct {
+	// Account is the account the assets are in, which must be one of the
+	// accounts of the server. Empty is the global account, except in
+	// operator mode, where an account is needed.
+	Account      string
+	Streams      []jetstream.StreamConfig
+	Consumers    []JetStreamConsumer
+	KeyValues    []jetstream.KeyValueConfig
+	ObjectStores []jetstream.ObjectStoreConfig
+
+	// Migrations are applied before the assets are reconciled, in order
+	// of version, each once; see JetStreamMigration.
+	Migrations []JetStreamMigration
+	// MigrationsBucket is the key-value bucket recording the migrations
+	// applied, or DefaultMigrationsBucket when empty.
+	MigrationsBucket string
+	// DryRunMigrations, when set, logs the migrations not yet applied
+	// instead of applying them, and leaves the assets alone until they
+	// are.
+	DryRunMigrations bool
+}
+
+// JetStreamMigration is a versioned change to JetStream assets, such as
+// adding subjects to a stream or recreating it with another retention.
+// Apply should tolerate running again after failing part way, since the
+// migration is recorded as applied only once it succeeds.
+type JetStreamMigration struct {
+	// Version orders the migration; the store's version is the highest
+	// applied. It must be positive and unique.
+	Version     uint64
+	Description string
+	Apply       func(ctx context.Context, js jetstream.JetStream) error
+}
+
+// JetStreamMigrationRecord records a migration applied to the store.
+type JetStreamMigrationRecord struct {
+	Version     uint64    `json:"version"`
+	Description string    `json:"description"`
+	AppliedAt   time.Time `json:"applied_at"`
+}
+
+// JetStreamMigrationPlan is the state of the migrations of a store.
+type JetStreamMigrationPlan struct {
+	// Version is the version of the store, zero when no migration has
+	// been applied, and Target the highest version of the migrations.
+	// Start refuses a store whose Version is above Target.
+	Version uint64
+	Target  uint64
+	// Applied are the migrations applied to the store, and Pending those
+	// to apply, in order of version.
+	Applied []JetStreamMigrationRecord
+	Pending []JetStreamMigration
+}
+
+// JetStreamConsumer is a consumer of JetStreamAssets, on Stream. Its
+// Config must name it with Durable or Name.
+type JetStreamConsumer struct {
+	Stream string
+	Config jetstream.ConsumerConfig
+}
+
+// JetStreamDrift is a JetStream asset whose configuration differs from
+// its declaration in a way the NATS server cannot update in place.
+type JetStreamDrift struct {
+	// Kind is the kind of asset: "stream", "consumer", "key-value
+	// bucket", or "object store".
+	Kind string
+	// Name names the asset. A consumer's name follows its stream's, as in
+	// "ORDERS/worker".
+	Name string
+	// Reason is why the NATS server refused the update.
+	Reason string
+}
+
+// jetStreamRun provisions the JetStreamAssets of a run of a Server,
+// connecting in process as a user of their account.
+type jetStreamRun struct {
+	server *Server
+	// account is the account of the assets, and password the password of
+	// the user generated for the run, when it connects as one.
+	account  string
+	password string
+	// operator signs the user the run connects as in operator mode.
+	operator *operatorRun
+	// clientAuth is whether the run's user is authenticated by the
+	// server's clientAuth, rather than the NATS server.
+	clientAuth bool
+	// credentials are given when connecting, set by configure.
+	credentials nats.Option
+}
+
+// jetStreamAsset is an asset of JetStreamAssets, reconciled through
+// functions returning its configuration, creating it, and updating it.
+// config returns an error matching jetstream.ErrStreamNotFound or
+// jetstream.ErrConsumerNotFound when the asset does not exist, and update
+// returns the configuration it left the asset with.
+type jetStreamAsset struct {
+	kind   string
+	name   string
+	config func(ctx context.Context) (any, error)
+	create func(ctx context.Context) error
+	update func(ctx context.Context) (any, error)
+	// drift returns why the asset, configured as existing, cannot be
+	// updated to its declaration in place, or "" when it can.
+	drift func(existing any) string
+}
+
+// authCallout is the auth callout service of a run of a Server.
+type authCallout struct {
+	server *Server
+	// account is the account the service runs in, and password the
+	// password of the user it connects as.
+	account  string
+	password string
+	// issuer signs the responses to the NATS server's requests.
+	issuer nkeys.KeyPair
+	// conn is the service's connection to the NATS server, set once it
+	// is started.
+	conn *nats.Conn
+}
+
+// operator is the operator of a server in operator mode, with the system
+// account and the accounts it signed.
+type operator struct {
+	// dir is the directory the keys and JWTs are written to, or "" to
+	// keep them in memory only.
+	dir    string
+	key    nkeys.KeyPair
+	claims *jwt.OperatorClaims
+
+	// mu guards the accounts, keyed by name, which change as account
+	// JWTs are updated.
+	mu       sync.Mutex
+	accounts map[string]*operatorAccount
+}
+
+// operatorAccount is an account of an operator, with its current JWT.
+type operatorAccount struct {
+	key   nkeys.KeyPair
+	token string
+}
+
+// operatorRun is the operator mode of a run of a Server.
+type operatorRun struct {
+	operator *operator
+	// resolver gives the account JWTs to the NATS server, for the run's
+	// whole length, across reloads.
+	resolver natsserver.AccountResolver
+	// conn is the run's connection to the NATS server as a user of the
+	// system account, set once it is started, that account JWT updates
+	// are pushed through.
+	conn *nats.Conn
+}
+
+// clientAuth adapts the Authenticator of a server's options to the custom
+// client authentication of the NATS server.
+type clientAuth struct {
+	server *Server
+	// natsServer is the NATS server accounts are looked up in, set once it
+	// is created.
+	natsServer NATSServerInstance
+	// timeout bounds each authentication; zero leaves it unbounded.
+	timeout time.Duration
+	// jetStream provisions the run's JetStream assets, if any, as a user
+	// authenticated ahead of the Authenticator.
+	jetStream *jetStreamRun
+}
+
+// DevTLS configures development TLS. The first time the Server starts, it
+// generates an ephemeral certificate authority, kept in memory for as long
+// as the Server exists, and each time it starts it issues a server
+// certificate and a client certificate from it; see
+// Server.DevCertificates.
+type DevTLS struct {
+	// Hosts are the DNS names and IP addresses the server certificate is
+	// valid for, "localhost" and the loopback addresses when empty.
+	Hosts []string
+	// ClientName is the common name of the client certificate,
+	// DefaultDevTLSClientName when empty.
+	ClientName string
+	// Verify requires clients to present a certificate issued by the
+	// certificate authority.
+	Verify bool
+}
+
+// DevCertificates are the certificates of development TLS, PEM-encoded
+// alongside for clients configured with files, such as the nats CLI.
+type DevCertificates struct {
+	// CAPool holds the certificate authority, for clients to verify the
+	// server with.
+	CAPool *x509.CertPool
+	CAPEM  []byte
+	// ClientCertificate is the client certificate, with its key.
+	ClientCertificate tls.Certificate
+	ClientCertPEM     []byte
+	ClientKeyPEM      []byte
+}
+
+// devCA is the certificate authority of development TLS.
+type devCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+// devTLSRun is the development TLS of a run of a Server.
+type devTLSRun struct {
+	// config secures client connections, for the run's whole length,
+	// across reloads.
+	config *tls.Config
+	verify bool
+	certs  *DevCertificates
+}
+
+// CertWatcher configures the certificate watcher of a Server. While the
+// server runs, the watcher checks the TLS files of the options every
+// Interval, and when any has changed reloads the server with them, as
+// Server.ReloadCertificates does, so the listeners serve the new
+// certificates without a restart. Files failing to load, or holding a
+// certificate that is not valid, are logged and leave the listeners as
+// they are. The watcher also logs a warning once for each certificate of
+// the listeners expiring within ExpiryWarning.
+type CertWatcher struct {
+	// Interval is how often the files are checked,
+	// DefaultCertWatchInterval when zero.
+	Interval time.Duration
+	// ExpiryWarning is how long ahead of a certificate's expiry it is
+	// warned of, DefaultCertExpiryWarning when zero.
+	ExpiryWarning time.Duration
+}
+
+// certWatcher is the certificate watcher of a run of a Server.
+type certWatcher struct {
+	server   *Server
+	interval time.Duration
+	warning  time.Duration
+	// stamps are the files watched, as last checked.
+	stamps map[string]fileStamp
+	// warned holds the certificates whose expiry was warned of, by
+	// listener and serial number.
+	warned map[string]bool
+	// done is closed when the run ends, stopping the watcher.
+	done chan struct{}
+}
+
+// fileStamp is the size and modification time of a file, zero for a file
+// that cannot be read.
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// tlsListener is a listener of the NATS server that TLS files can secure.
+type tlsListener struct {
+	// name names the listener in logs and errors, field the field of the
+	// options holding its files, and label the files in errors.
+	name  string
+	field string
+	label string
+	files *TLSFiles
+	// config is the listener's TLS configuration in the NATS server
+	// options.
+	config **tls.Config
+}
+
+// SecretResolver resolves references to secrets, such as
+// "vault://nats/password", so options can name a secret instead of holding
+// it. A reference may be given wherever the options hold a password, token,
+// nkey seed, or TLS key, and is resolved each time the server starts or
+// reloads. Errors must not include the secret. The nkey seeds of
+// LeafNode.Remotes are the only seeds the options hold; the JWT signing
+// keys of Operator and AuthCallout are generated by the Server rather than
+// configured, so there is no reference to give for them.
+type SecretResolver interface {
+	ResolveSecret(ctx context.Context, ref *url.URL) (string, error)
+}
+
+// SecretResolverFunc adapts a function to a SecretResolver.
+type SecretResolverFunc func(ctx context.Context, ref *url.URL) (string, error)
+
+// TLSFiles are the PEM files securing the connections of a listener.
+type TLSFiles struct {
+	CertFile string
+	// KeyFile is the key's file, or a secret reference resolving to the
+	// PEM-encoded key itself; see SecretResolver.
+	KeyFile string
+	// CAFile holds the certificate authorities the certificates of
+	// connecting clients, servers, or leafnodes are verified against.
+	CAFile string
+	// Verify requires clients to present a verified certificate.
+	Verify bool
+}
+
+const (
+	// DefaultReadyTimeout is how long DefaultOptions waits for the NATS
+	// server to be ready for connections.
+	DefaultReadyTimeout = 5 * time.Second
+
+	// DefaultJetStreamMaxMemory is the memory storage limit DefaultOptions
+	// gives JetStream.
+	DefaultJetStreamMaxMemory = 256 << 20
+
+	// DefaultJetStreamMaxStore is the file storage limit DefaultOptions
+	// gives JetStream.
+	DefaultJetStreamMaxStore = 1 << 30
+)
+
+// configBlock is the block of a NATS server configuration file holding
+// the settings of this package; see LoadOptionsFile.
+const configBlock = "embedded"
+
+// DefaultPasswordCost is the bcrypt cost HashPassword uses when given no
+// other, as the nats CLI does.
+const DefaultPasswordCost = 11
+
+// DefaultAuthCalloutAccount is the account the auth callout service runs
+// in when AuthCallout names no other.
+const DefaultAuthCalloutAccount = "AUTH"
+
+// authCalloutUser is the user the auth callout service connects as.
+const authCalloutUser = "auth-callout"
+
+// DefaultOperatorName is the name of the operator when Operator names no
+// other.
+const DefaultOperatorName = "embedded"
+
+// SystemAccountName is the name of the system account of operator mode.
+const SystemAccountName = "SYS"
+
+// operatorSystemUser is the user operator mode pushes account JWT updates
+// as.
+const operatorSystemUser = "operator"
+
+// accountUpdateTimeout bounds how long the NATS server is given to accept
+// a pushed account JWT.
+const accountUpdateTimeout = 5 * time.Second
+
+// accountUpdateSubject is the subject account JWTs are pushed to the NATS
+// server on, given the account's public key.
+const accountUpdateSubject = "$SYS.REQ.ACCOUNT.%s.CLAIMS.UPDATE"
+
+// DefaultDevTLSClientName is the common name of the client certificate of
+// development TLS when DevTLS names no other.
+const DefaultDevTLSClientName = "dev-client"
+
+// devTLSValidity is how long the server and client certificates of
+// development TLS are valid for, and devCAValidity how long their
+// certificate authority is, which lasts as long as the Server.
+const (
+	devTLSValidity = 30 * 24 * time.Hour
+	devCAValidity  = 10 * 365 * 24 * time.Hour
+)
+
+// DefaultCertWatchInterval is how often the certificate watcher checks the
+// TLS files when CertWatcher gives no other interval.
+const DefaultCertWatchInterval = 10 * time.Second
+
+// DefaultCertExpiryWarning is how long ahead of a certificate's expiry the
+// certificate watcher warns of it when CertWatcher gives no other time.
+const DefaultCertExpiryWarning = 7 * 24 * time.Hour
+
+// jetStreamUser is the user JetStream assets are provisioned as.
+const jetStreamUser = "jetstream"
+
+// DefaultMigrationsBucket is the key-value bucket recording the applied
+// migrations of JetStreamAssets when they name no other.
+const DefaultMigrationsBucket = "migrations"
+
+// DefaultEnvPrefix is the prefix of the environment variables ApplyEnv
+// reads when given no other.
+const DefaultEnvPrefix = "NATS_EMBED_"
+
+// redacted replaces the values of secret settings in EnvVar reports.
+const redacted = "[REDACTED]"
+
+// Option configures Options; see NewWithOptions.
+type Option func(*Options)
+
+// Hooks are functions run by a Server as it starts and stops. Each list is
+// run in order.
+type Hooks struct {
+	// OnStarting is run before the NATS server is created. A failing hook
+	// aborts the start, and the rest are not run.
+	OnStarting []Hook
+	// OnReady is run once the NATS server is ready for connections, before
+	// the server moves to StateRunning. A failing hook shuts the NATS
+	// server down and fails the start, and the rest are not run.
+	OnReady []Hook
+	// OnStopping is run before the NATS server is shut down or drained.
+	// Every hook is run; failures are returned by the stop, which goes on.
+	OnStopping []Hook
+	// OnStopped is run once the NATS server has shut down, before the
+	// server moves to StateStopped. Every hook is run; failures are
+	// returned by the stop, or only logged when it has given up waiting.
+	OnStopped []Hook
+}
+
+// Hook is a function run at a HookStage.
+type Hook struct {
+	// Name identifies the hook in logs and errors.
+	Name string
+	// Timeout bounds how long Fn may run. Zero leaves it bounded only by
+	// the context of the lifecycle method running it.
+	Timeout time.Duration
+	// Fn is the hook. A hook that outlives its context is abandoned.
+	Fn func(ctx context.Context, s *Server) error
+}
+
+// HookStage is the point in the server lifecycle a Hook is run at.
+type HookStage string
+
+// Hook stages, one per list in Hooks.
+const (
+	HookStarting HookStage = "starting"
+	HookReady    HookStage = "ready"
+	HookStopping HookStage = "stopping"
+	HookStopped  HookStage = "stopped"
+)
+
+// HookError is returned when a Hook fails or outlives its context.
+type HookError struct {
+	Stage HookStage
+	Name  string
+	Err   error
+}
+
+// State is a stage in the lifecycle of a Server.
+type State int
+
+// Lifecycle states. A Server begins in StateNew; the transitions allowed
+// between states are listed in the documentation of Server.State.
+const (
+	// StateNew is a server that has never been started.
+	StateNew State = iota
+	// StateStarting is a server waiting for the NATS server to be ready.
+	StateStarting
+	// StateRunning is a server ready for connections.
+	StateRunning
+	// StateDraining is a server shutting down, draining its clients first
+	// when asked to.
+	StateDraining
+	// StateStopped is a server that has shut down.
+	StateStopped
+	// StateFailed is a server whose start failed.
+	StateFailed
+)
+
+// Transition is a move of a Server from one State to another.
+type Transition struct {
+	From State
+	To   State
+}
+
+// TransitionError is returned by a lifecycle method called in a state that
+// does not allow it, such as Start on a running server. It matches
+// ErrInvalidTransition with errors.Is.
+type TransitionError struct {
+	From State
+	To   State
+}
+
+// subscriber is a function registered with Server.Subscribe.
+type subscriber struct {
+	id int
+	fn func(Transition)
+}
+
+// listener is a listener the NATS server opens, checked before it starts.
+type listener struct {
+	name string
+	host string
+	port int
+}
+
+// run is one run of a Server, from a start until it stops or fails.
+type run struct {
+	// done is closed when the run ends, after cause is set to why. Both
+	// are guarded by Server.mu.
+	done  chan struct{}
+	cause error
+
+	// storeLock is the lock on the JetStream store directory held for the
+	// run, released when it ends.
+	storeLock *os.File
+
+	// callout is the auth callout service of the run, if any, stopped
+	// when it ends.
+	callout *authCallout
+
+	// operator is the operator mode of the run, if any, stopped when it
+	// ends.
+	operator *operatorRun
+
+	// devTLS is the development TLS of the run, if any.
+	devTLS *devTLSRun
+
+	// certWatcher is the certificate watcher of the run, if any, stopped
+	// when it ends.
+	certWatcher *certWatcher
+
+	// jetStream provisions the JetStream assets of the run, if any.
+	jetStream *jetStreamRun
+}
+
+// ReloadError is returned by Reload when the new options change settings
+// that the NATS server cannot apply without a restart. It matches
+// ErrNotReloadable with errors.Is.
+type ReloadError struct {
+	// Fields names the natsserver.Options fields that changed.
+	Fields []string
+}
+
+// StartPhase is the part of a start that failed, reported by StartError.
+type StartPhase string
+
+// Start phases, in the order a start goes through them.
+const (
+	// StartPhaseValidate is checking the options with Options.Validate;
+	// the cause is a *ValidationError.
+	StartPhaseValidate StartPhase = "validate"
+	// StartPhaseHooks is running the OnStarting or OnReady hooks; the
+	// cause is a *HookError.
+	StartPhaseHooks StartPhase = "hooks"
+	// StartPhasePreflight is checking that the listeners and JetStream
+	// store directory are available.
+	StartPhasePreflight StartPhase = "preflight"
+	// StartPhaseCreate is creating the NATS server from the options,
+	// including resolving secrets and loading Options.TLSFiles.
+	StartPhaseCreate StartPhase = "create"
+	// StartPhaseReady is waiting for the NATS server to be ready for
+	// connections.
+	StartPhaseReady StartPhase = "ready"
+	// StartPhaseProvision is applying migrations and creating and
+	// updating Options.JetStreamAssets.
+	StartPhaseProvision StartPhase = "provision"
+)
+
+// StartError is returned by Start and StartContext when a start fails
+// after the server moved to StateStarting.
+type StartError struct {
+	Phase StartPhase
+	Cause error
+}
+
+// ListenerError is the cause of a StartError when a listener's address
+// cannot be listened on. It matches ErrPortInUse with errors.Is when the
+// address is taken.
+type ListenerError struct {
+	// Listener names the listener, such as "client" or "cluster".
+	Listener string
+	// Address is the host and port listened on.
+	Address string
+	Err     error
+}
+
+// ValidationError is returned by Options.Validate, listing every problem
+// found with the options. It matches ErrInvalidOptions with errors.Is.
+type ValidationError struct {
+	Errs []error
+}
+
+// ConfigError is a problem with a setting in the embedded block of a NATS
+// server configuration file, at the position given.
+type ConfigError struct {
+	File   string
+	Line   int
+	Column int
+	Reason string
+}
+
+// configToken is a value parsed from a NATS server configuration file,
+// along with where it was found.
+type configToken interface {
+	Value() any
+	Line() int
+	Position() int
+	SourceFile() string
+}
+
+// EnvVar is an option set from an environment variable by ApplyEnv.
+type EnvVar struct {
+	// Name is the variable, such as "NATS_EMBED_PORT".
+	Name string
+	// Field is the Options field set, such as "Port" or "Cluster.Port".
+	Field string
+	// Value is the variable's value, or "[REDACTED]" for credentials.
+	Value string
+}
+
+// envSetting is an option ApplyEnv reads from the variable named by the
+// prefix followed by suffix.
+type envSetting struct {
+	suffix string
+	field  string
+	secret bool
+	set    func(opts *Options, value string) error
+}
+
+// SecretError is a secret reference that could not be resolved.
+type SecretError struct {
+	// Field names the option holding the reference, such as
+	// "Users[0].Password".
+	Field string
+	// Scheme is the scheme of the reference, such as "file".
+	Scheme string
+	Err    error
 }
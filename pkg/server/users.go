@@ -0,0 +1,225 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"fmt"
+	"slices"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+)
+
+// AddUser adds a user authenticating with a username and password to the
+// running server, through a configuration reload. The user must not
+// already exist, and its account must be one of Opts.Accounts.
+func (s *Server) AddUser(
+	user *natsserver.User,
+) error {
+	err := s.updateOptions(func(natsOpts *natsserver.Options) error {
+		natsOpts.Users = append(natsOpts.Users, user)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error adding user %q: %w", user.Username, err)
+	}
+
+	return nil
+}
+
+// AddNkeyUser adds a user authenticating with an nkey to the running
+// server, as AddUser does.
+func (s *Server) AddNkeyUser(
+	user *natsserver.NkeyUser,
+) error {
+	err := s.updateOptions(func(natsOpts *natsserver.Options) error {
+		natsOpts.Nkeys = append(natsOpts.Nkeys, user)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error adding nkey user %q: %w", user.Nkey, err)
+	}
+
+	return nil
+}
+
+// RemoveUser removes the user with the username or nkey name from the
+// running server, through a configuration reload, disconnecting the
+// clients it authenticated. Removing the only way clients authenticate
+// returns ErrLastUser.
+func (s *Server) RemoveUser(
+	name string,
+) error {
+	err := s.updateOptions(func(natsOpts *natsserver.Options) error {
+		removed := removeUsers(natsOpts, func(username string, _ string) bool {
+			return username == name
+		})
+		if removed == 0 {
+			return ErrUnknownUser
+		}
+
+		return s.checkAuthLeft(natsOpts)
+	})
+	if err != nil {
+		return fmt.Errorf("error removing user %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// UpdatePermissions replaces the permissions of the user with the username
+// or nkey name on the running server, through a configuration reload. The
+// clients it authenticated keep their connections and are held to perms
+// from then on; nil perms allow everything.
+func (s *Server) UpdatePermissions(
+	name string,
+	perms *natsserver.Permissions,
+) error {
+	err := s.updateOptions(func(natsOpts *natsserver.Options) error {
+		found := false
+		for _, u := range natsOpts.Users {
+			if u.Username == name {
+				u.Permissions = perms
+				found = true
+			}
+		}
+		for _, u := range natsOpts.Nkeys {
+			if u.Nkey == name {
+				u.Permissions = perms
+				found = true
+			}
+		}
+		if !found {
+			return ErrUnknownUser
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error updating permissions of %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// AddAccount adds acc, with its imports and exports, to the running
+// server, through a configuration reload. No account may already have its
+// name.
+func (s *Server) AddAccount(
+	acc *natsserver.Account,
+) error {
+	err := s.updateOptions(func(natsOpts *natsserver.Options) error {
+		natsOpts.Accounts = append(natsOpts.Accounts, acc)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error adding account %q: %w", acc.Name, err)
+	}
+
+	return nil
+}
+
+// RemoveAccount removes the account name and the users bound to it from
+// the running server, through a configuration reload, disconnecting the
+// clients of the account. Removing the system account is refused by
+// validation, and leaving no way for clients to authenticate returns
+// ErrLastUser.
+func (s *Server) RemoveAccount(
+	name string,
+) error {
+	err := s.updateOptions(func(natsOpts *natsserver.Options) error {
+		before := len(natsOpts.Accounts)
+		natsOpts.Accounts = slices.DeleteFunc(
+			natsOpts.Accounts,
+			func(acc *natsserver.Account) bool {
+				return acc.Name == name
+			},
+		)
+		if len(natsOpts.Accounts) == before {
+			return ErrUnknownAccount
+		}
+
+		removeUsers(natsOpts, func(_ string, account string) bool {
+			return account == name
+		})
+
+		return s.checkAuthLeft(natsOpts)
+	})
+	if err != nil {
+		return fmt.Errorf("error removing account %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// removeUsers removes the users and nkey users of natsOpts for which
+// match, given the username or nkey and account name, reports true,
+// returning how many were removed.
+func removeUsers(
+	natsOpts *natsserver.Options,
+	match func(name string, account string) bool,
+) int {
+	before := len(natsOpts.Users) + len(natsOpts.Nkeys)
+	natsOpts.Users = slices.DeleteFunc(
+		natsOpts.Users,
+		func(u *natsserver.User) bool {
+			return match(u.Username, accountName(u.Account))
+		},
+	)
+	natsOpts.Nkeys = slices.DeleteFunc(
+		natsOpts.Nkeys,
+		func(u *natsserver.NkeyUser) bool {
+			return match(u.Nkey, accountName(u.Account))
+		},
+	)
+
+	return before - len(natsOpts.Users) - len(natsOpts.Nkeys)
+}
+
+// checkAuthLeft returns ErrLastUser when natsOpts leave clients no way to
+// authenticate, which would let any client connect. The auth callout
+// service authenticates clients without any users of natsOpts.
+func (s *Server) checkAuthLeft(
+	natsOpts *natsserver.Options,
+) error {
+	if s.options().AuthCallout != nil ||
+		len(natsOpts.Users) > 0 || len(natsOpts.Nkeys) > 0 ||
+		natsOpts.Username != "" || natsOpts.Authorization != "" ||
+		natsOpts.CustomClientAuthentication != nil ||
+		len(natsOpts.TrustedOperators) > 0 || len(natsOpts.TrustedKeys) > 0 {
+		return nil
+	}
+
+	return ErrLastUser
+}
+
+// accountName returns the name of acc, or "" for none.
+func accountName(
+	acc *natsserver.Account,
+) string {
+	if acc == nil {
+		return ""
+	}
+
+	return acc.Name
+}
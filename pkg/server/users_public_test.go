@@ -0,0 +1,618 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+type UsersPublicTestSuite struct {
+	suite.Suite
+
+	mockCtrl       *gomock.Controller
+	mockNATSServer *mocks.MockNATSServerInstance
+	srv            *server.Server
+	opts           *server.Options
+	terminate      func()
+	newNATSServer  func(*natsserver.Options) (server.NATSServerInstance, error)
+}
+
+func (s *UsersPublicTestSuite) SetupSuite() {
+	s.newNATSServer = server.NewNATSServer
+}
+
+func (s *UsersPublicTestSuite) TearDownSuite() {
+	server.NewNATSServer = s.newNATSServer
+}
+
+func (s *UsersPublicTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockNATSServer = mocks.NewMockNATSServerInstance(s.mockCtrl)
+	s.terminate = expectWaitForShutdown(s.mockNATSServer)
+
+	app := natsserver.NewAccount("APP")
+	s.opts = &server.Options{
+		Options: &natsserver.Options{
+			Accounts: []*natsserver.Account{
+				app,
+				natsserver.NewAccount("SYS"),
+			},
+			SystemAccount: "SYS",
+			Users: []*natsserver.User{
+				{Username: "alice", Password: "secret", Account: app},
+			},
+			Nkeys: []*natsserver.NkeyUser{
+				{Nkey: nkeyService1, Account: app},
+			},
+		},
+		ReadyTimeout: 5 * time.Second,
+	}
+	s.srv = server.New(slog.New(slog.DiscardHandler), s.opts)
+
+	server.NewNATSServer = func(
+		_ *natsserver.Options,
+	) (server.NATSServerInstance, error) {
+		return s.mockNATSServer, nil
+	}
+}
+
+func (s *UsersPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *UsersPublicTestSuite) TearDownSubTest() {
+	s.terminate()
+}
+
+func (s *UsersPublicTestSuite) TearDownTest() {
+	s.terminate()
+	s.mockCtrl.Finish()
+}
+
+// start starts the server on the mock NATS server.
+func (s *UsersPublicTestSuite) start() {
+	s.mockNATSServer.EXPECT().Start().AnyTimes()
+	s.mockNATSServer.EXPECT().ReadyForConnections(gomock.Any()).Return(true)
+	s.mockNATSServer.EXPECT().SetLogger(gomock.Any(), true, true)
+	s.Require().NoError(s.srv.Start())
+}
+
+// expectReload expects a reload, passing the options reloaded to fn.
+func (s *UsersPublicTestSuite) expectReload(
+	fn func(opts *natsserver.Options),
+) {
+	s.mockNATSServer.EXPECT().
+		ReloadOptions(gomock.Any()).
+		DoAndReturn(func(opts *natsserver.Options) error {
+			fn(opts)
+			return nil
+		})
+}
+
+// expectReloadError expects a reload, failing it.
+func (s *UsersPublicTestSuite) expectReloadError() {
+	s.mockNATSServer.EXPECT().
+		ReloadOptions(gomock.Any()).
+		Return(errors.New("reload failed"))
+}
+
+// usernames returns the usernames of the users of opts.
+func usernames(
+	opts *natsserver.Options,
+) []string {
+	names := make([]string, 0, len(opts.Users))
+	for _, u := range opts.Users {
+		names = append(names, u.Username)
+	}
+
+	return names
+}
+
+func (s *UsersPublicTestSuite) TestAddUser() {
+	tests := []struct {
+		name         string
+		stopped      bool
+		user         *natsserver.User
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name: "adds user through reload",
+			user: &natsserver.User{
+				Username: "bob",
+				Password: "secret",
+				Account:  natsserver.NewAccount("APP"),
+			},
+			mockSetup: func() {
+				s.expectReload(func(opts *natsserver.Options) {
+					s.Equal([]string{"alice", "bob"}, usernames(opts))
+				})
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Equal(
+					[]string{"alice", "bob"},
+					usernames(s.srv.Opts.Options),
+				)
+				s.Equal([]string{"alice"}, usernames(s.opts.Options))
+			},
+		},
+		{
+			name:      "rejects existing user",
+			user:      &natsserver.User{Username: "alice"},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrInvalidOptions)
+				s.ErrorContains(err, `error adding user "alice": `+
+					`invalid options: duplicate user "alice"`)
+				s.Same(s.opts, s.srv.Opts)
+			},
+		},
+		{
+			name: "rejects user of unknown account",
+			user: &natsserver.User{
+				Username: "bob",
+				Account:  natsserver.NewAccount("NONE"),
+			},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorContains(
+					err,
+					`user "bob" references unknown account "NONE"`,
+				)
+			},
+		},
+		{
+			name:      "returns reload error",
+			user:      &natsserver.User{Username: "bob"},
+			mockSetup: s.expectReloadError,
+			validateFunc: func(err error) {
+				s.EqualError(err, `error adding user "bob": `+
+					"error reloading server: reload failed")
+				s.Same(s.opts, s.srv.Opts)
+			},
+		},
+		{
+			name:      "returns error when not running",
+			stopped:   true,
+			user:      &natsserver.User{Username: "bob"},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrNotRunning)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			if !tc.stopped {
+				s.start()
+			}
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.AddUser(tc.user))
+		})
+	}
+}
+
+func (s *UsersPublicTestSuite) TestAddNkeyUser() {
+	tests := []struct {
+		name         string
+		user         *natsserver.NkeyUser
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name: "adds nkey user through reload",
+			user: &natsserver.NkeyUser{Nkey: nkeyService2},
+			mockSetup: func() {
+				s.expectReload(func(opts *natsserver.Options) {
+					s.Len(opts.Nkeys, 2)
+				})
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Require().Len(s.srv.Opts.Nkeys, 2)
+				s.Equal(nkeyService2, s.srv.Opts.Nkeys[1].Nkey)
+			},
+		},
+		{
+			name:      "rejects invalid nkey",
+			user:      &natsserver.NkeyUser{Nkey: "bogus"},
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.EqualError(err, `error adding nkey user "bogus": `+
+					`invalid options: invalid user nkey "bogus"`)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.start()
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.AddNkeyUser(tc.user))
+		})
+	}
+}
+
+func (s *UsersPublicTestSuite) TestRemoveUser() {
+	tests := []struct {
+		name         string
+		setup        func()
+		user         string
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name:  "removes user through reload",
+			setup: func() {},
+			user:  "alice",
+			mockSetup: func() {
+				s.expectReload(func(opts *natsserver.Options) {
+					s.Empty(opts.Users)
+					s.Len(opts.Nkeys, 1)
+				})
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Empty(s.srv.Opts.Users)
+				s.Len(s.opts.Users, 1)
+			},
+		},
+		{
+			name:  "removes nkey user",
+			setup: func() {},
+			user:  nkeyService1,
+			mockSetup: func() {
+				s.expectReload(func(opts *natsserver.Options) {
+					s.Empty(opts.Nkeys)
+				})
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Empty(s.srv.Opts.Nkeys)
+			},
+		},
+		{
+			name:      "returns error for unknown user",
+			setup:     func() {},
+			user:      "bob",
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrUnknownUser)
+				s.EqualError(err, `error removing user "bob": unknown user`)
+			},
+		},
+		{
+			name: "refuses to remove last user",
+			setup: func() {
+				s.opts.Nkeys = nil
+			},
+			user:      "alice",
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrLastUser)
+				s.Len(s.srv.Opts.Users, 1)
+			},
+		},
+		{
+			name: "removes last user when a token remains",
+			setup: func() {
+				s.opts.Nkeys = nil
+				s.opts.Authorization = "token"
+			},
+			user: "alice",
+			mockSetup: func() {
+				s.expectReload(func(opts *natsserver.Options) {
+					s.Empty(opts.Users)
+				})
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+			s.start()
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.RemoveUser(tc.user))
+		})
+	}
+}
+
+func (s *UsersPublicTestSuite) TestUpdatePermissions() {
+	perms := &natsserver.Permissions{
+		Publish: &natsserver.SubjectPermission{Allow: []string{"app.>"}},
+	}
+
+	tests := []struct {
+		name         string
+		user         string
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name: "updates permissions of user through reload",
+			user: "alice",
+			mockSetup: func() {
+				s.expectReload(func(opts *natsserver.Options) {
+					s.Equal(perms, opts.Users[0].Permissions)
+				})
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Equal(perms, s.srv.Opts.Users[0].Permissions)
+				s.Nil(s.opts.Users[0].Permissions)
+			},
+		},
+		{
+			name: "updates permissions of nkey user",
+			user: nkeyService1,
+			mockSetup: func() {
+				s.expectReload(func(opts *natsserver.Options) {
+					s.Equal(perms, opts.Nkeys[0].Permissions)
+					s.Nil(opts.Users[0].Permissions)
+				})
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name:      "returns error for unknown user",
+			user:      "bob",
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrUnknownUser)
+				s.ErrorContains(err, `error updating permissions of "bob"`)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.start()
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.UpdatePermissions(tc.user, perms))
+		})
+	}
+}
+
+func (s *UsersPublicTestSuite) TestAddAccount() {
+	tests := []struct {
+		name         string
+		account      string
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name:    "adds account through reload",
+			account: "OTHER",
+			mockSetup: func() {
+				s.expectReload(func(opts *natsserver.Options) {
+					s.Len(opts.Accounts, 3)
+				})
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Len(s.srv.Opts.Accounts, 3)
+				s.Len(s.opts.Accounts, 2)
+			},
+		},
+		{
+			name:      "rejects existing account",
+			account:   "APP",
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.EqualError(err, `error adding account "APP": `+
+					`invalid options: duplicate account "APP"`)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.start()
+			tc.mockSetup()
+
+			tc.validateFunc(
+				s.srv.AddAccount(natsserver.NewAccount(tc.account)),
+			)
+		})
+	}
+}
+
+func (s *UsersPublicTestSuite) TestRemoveAccount() {
+	tests := []struct {
+		name         string
+		setup        func()
+		account      string
+		mockSetup    func()
+		validateFunc func(err error)
+	}{
+		{
+			name: "removes account and its users through reload",
+			setup: func() {
+				s.opts.Users = append(
+					s.opts.Users,
+					&natsserver.User{Username: "admin"},
+				)
+			},
+			account: "APP",
+			mockSetup: func() {
+				s.expectReload(func(opts *natsserver.Options) {
+					s.Len(opts.Accounts, 1)
+					s.Equal([]string{"admin"}, usernames(opts))
+					s.Empty(opts.Nkeys)
+				})
+			},
+			validateFunc: func(err error) {
+				s.Require().NoError(err)
+				s.Len(s.srv.Opts.Accounts, 1)
+				s.Len(s.opts.Accounts, 2)
+			},
+		},
+		{
+			name:      "returns error for unknown account",
+			setup:     func() {},
+			account:   "NONE",
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrUnknownAccount)
+				s.EqualError(
+					err,
+					`error removing account "NONE": unknown account`,
+				)
+			},
+		},
+		{
+			name:      "refuses to remove system account",
+			setup:     func() {},
+			account:   "SYS",
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorContains(
+					err,
+					`system account "SYS" is not one of the accounts`,
+				)
+			},
+		},
+		{
+			name:      "refuses to remove account of last users",
+			setup:     func() {},
+			account:   "APP",
+			mockSetup: func() {},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrLastUser)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+			s.start()
+			tc.mockSetup()
+
+			tc.validateFunc(s.srv.RemoveAccount(tc.account))
+		})
+	}
+}
+
+func (s *UsersPublicTestSuite) TestConcurrentChanges() {
+	s.start()
+	s.mockNATSServer.EXPECT().ReloadOptions(gomock.Any()).AnyTimes()
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			s.NoError(s.srv.AddUser(&natsserver.User{
+				Username: fmt.Sprintf("user%d", i),
+			}))
+		})
+	}
+	wg.Wait()
+
+	s.Len(s.srv.Opts.Users, 11)
+}
+
+func (s *UsersPublicTestSuite) TestRevokesClients() {
+	server.NewNATSServer = s.newNATSServer
+	s.opts.Host = "127.0.0.1"
+	s.opts.Port = freePort(s.T())
+	s.opts.NoSigs = true
+	s.Require().NoError(s.srv.Start())
+	defer s.srv.Stop()
+
+	url := fmt.Sprintf("nats://127.0.0.1:%d", s.opts.Port)
+	s.Require().NoError(s.srv.AddUser(&natsserver.User{
+		Username: "bob",
+		Password: "secret",
+		Account:  s.opts.Accounts[0],
+	}))
+
+	closed := make(chan struct{})
+	bob, err := nats.Connect(
+		url,
+		nats.UserInfo("bob", "secret"),
+		nats.NoReconnect(),
+		nats.ClosedHandler(func(*nats.Conn) { close(closed) }),
+	)
+	s.Require().NoError(err)
+	defer bob.Close()
+
+	violations := make(chan error, 1)
+	alice, err := nats.Connect(
+		url,
+		nats.UserInfo("alice", "secret"),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, e error) {
+			violations <- e
+		}),
+	)
+	s.Require().NoError(err)
+	defer alice.Close()
+
+	s.Require().NoError(s.srv.RemoveUser("bob"))
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		s.Fail("client of removed user not disconnected")
+	}
+
+	s.Require().NoError(s.srv.UpdatePermissions(
+		"alice",
+		&natsserver.Permissions{
+			Publish: &natsserver.SubjectPermission{Deny: []string{"admin"}},
+		},
+	))
+	s.Require().NoError(alice.Publish("admin", nil))
+	select {
+	case err := <-violations:
+		s.ErrorContains(err, "permissions violation")
+	case <-time.After(5 * time.Second):
+		s.Fail("updated permissions not applied")
+	}
+	s.True(alice.IsConnected())
+}
+
+func TestUsersPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(UsersPublicTestSuite))
+}
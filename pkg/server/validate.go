@@ -0,0 +1,635 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nkeys"
+)
+
+// Error lists every problem found.
+func (e *ValidationError) Error() string {
+	problems := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		problems = append(problems, err.Error())
+	}
+
+	return fmt.Sprintf(
+		"%s: %s",
+		ErrInvalidOptions,
+		strings.Join(problems, "; "),
+	)
+}
+
+// Unwrap returns the problems found.
+func (e *ValidationError) Unwrap() []error {
+	return e.Errs
+}
+
+// Is reports whether target is ErrInvalidOptions.
+func (e *ValidationError) Is(
+	target error,
+) bool {
+	return target == ErrInvalidOptions
+}
+
+// Validate checks the options for mistakes that would otherwise only show
+// up as the NATS server failing to start or never becoming ready. Every
+// problem found is reported in a single *ValidationError. Start calls
+// Validate before starting the NATS server.
+func (o *Options) Validate() error {
+	var errs []error
+	if o.ReadyTimeout <= 0 {
+		errs = append(errs, fmt.Errorf(
+			"ready timeout must be positive, got %s",
+			o.ReadyTimeout,
+		))
+	}
+
+	if o.Options == nil {
+		errs = append(errs, fmt.Errorf("nats server options are required"))
+	} else {
+		errs = append(errs, validateStore(o.Options)...)
+		errs = append(errs, validatePorts(o.Options)...)
+		errs = append(errs, validateAuth(o.Options)...)
+	}
+
+	if o.Authenticator != nil && o.Options != nil &&
+		o.CustomClientAuthentication != nil {
+		errs = append(errs, fmt.Errorf(
+			"authenticator and custom client authentication both set",
+		))
+	}
+
+	if o.AuthCallout != nil {
+		errs = append(errs, o.validateAuthCallout()...)
+	}
+
+	if o.Operator != nil {
+		errs = append(errs, o.validateOperator()...)
+	}
+
+	errs = append(errs, o.validateTLSFiles()...)
+
+	if o.Options != nil && o.TLS && o.TLSConfig == nil &&
+		o.TLSFiles == nil && o.DevTLS == nil {
+		errs = append(errs, fmt.Errorf("tls enabled without a tls config"))
+	}
+
+	if o.DevTLS != nil {
+		errs = append(errs, o.validateDevTLS()...)
+	}
+
+	if o.MutualTLS != nil {
+		errs = append(errs, o.validateMutualTLS()...)
+	}
+
+	if o.JetStreamAssets != nil && o.Options != nil {
+		errs = append(errs, o.validateJetStreamAssets()...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Errs: errs}
+}
+
+// validateAuthCallout checks that the auth callout service has a handler
+// and is the only authentication the options replace.
+func (o *Options) validateAuthCallout() []error {
+	var errs []error
+	if o.AuthCallout.Handler == nil {
+		errs = append(errs, fmt.Errorf("auth callout has no handler"))
+	}
+
+	if o.Authenticator != nil {
+		errs = append(errs, fmt.Errorf("authenticator and auth callout both set"))
+	}
+
+	if o.Options == nil {
+		return errs
+	}
+
+	if o.Options.AuthCallout != nil {
+		errs = append(errs, fmt.Errorf(
+			"auth callout set in both server and nats server options",
+		))
+	}
+
+	if o.CustomClientAuthentication != nil {
+		errs = append(errs, fmt.Errorf(
+			"auth callout and custom client authentication both set",
+		))
+	}
+
+	if len(o.TrustedOperators) > 0 || len(o.TrustedKeys) > 0 ||
+		o.Operator != nil {
+		errs = append(errs, fmt.Errorf(
+			"auth callout not supported with trusted operators",
+		))
+	}
+
+	for _, u := range o.Users {
+		if u.Username == authCalloutUser {
+			errs = append(errs, fmt.Errorf(
+				"user %q reserved for auth callout",
+				u.Username,
+			))
+		}
+	}
+
+	return errs
+}
+
+// validateOperator checks that operator mode is the only authentication
+// of the options, and that its accounts can be created.
+func (o *Options) validateOperator() []error {
+	var errs []error
+	if o.Authenticator != nil {
+		errs = append(errs, fmt.Errorf("authenticator and operator both set"))
+	}
+
+	seen := map[string]bool{SystemAccountName: true}
+	for _, name := range o.Operator.Accounts {
+		switch {
+		case !validAccountName(name):
+			errs = append(errs, fmt.Errorf("invalid account name %q", name))
+		case seen[name]:
+			errs = append(errs, fmt.Errorf(
+				"duplicate operator account %q",
+				name,
+			))
+		}
+		seen[name] = true
+	}
+
+	if o.Options == nil {
+		return errs
+	}
+
+	if len(o.TrustedOperators) > 0 || len(o.TrustedKeys) > 0 ||
+		o.SystemAccount != "" || o.AccountResolver != nil {
+		errs = append(errs, fmt.Errorf(
+			"operator set in both server and nats server options",
+		))
+	}
+
+	if len(o.Users) > 0 || len(o.Nkeys) > 0 || len(o.Accounts) > 0 ||
+		o.Username != "" || o.Authorization != "" {
+		errs = append(errs, fmt.Errorf(
+			"operator mode does not allow users or accounts in options",
+		))
+	}
+
+	if o.CustomClientAuthentication != nil {
+		errs = append(errs, fmt.Errorf(
+			"operator and custom client authentication both set",
+		))
+	}
+
+	return errs
+}
+
+// validAccountName reports whether name can name an account of operator
+// mode, which is also the name of its files in Operator.Dir.
+func validAccountName(
+	name string,
+) bool {
+	return name != "" && name != "." && name != ".." &&
+		!strings.ContainsAny(name, `/\`)
+}
+
+// validateTLSFiles checks that the TLS files of each listener name a
+// certificate and key, and that the certificate watcher has files to
+// watch.
+func (o *Options) validateTLSFiles() []error {
+	var errs []error
+	watched := false
+	for _, l := range o.tlsListeners(&natsserver.Options{}) {
+		if l.files == nil {
+			continue
+		}
+		watched = true
+
+		if l.files.CertFile == "" || l.files.KeyFile == "" {
+			errs = append(errs, fmt.Errorf(
+				"%s need a certificate and key",
+				l.label,
+			))
+		}
+	}
+
+	if o.CertWatcher != nil {
+		if !watched {
+			errs = append(errs, fmt.Errorf("cert watcher has no tls files"))
+		}
+
+		if o.CertWatcher.Interval < 0 {
+			errs = append(errs, fmt.Errorf("cert watcher interval is negative"))
+		}
+	}
+
+	return errs
+}
+
+// validateDevTLS checks that development TLS is the only TLS of the
+// options, and that its hosts are named.
+func (o *Options) validateDevTLS() []error {
+	var errs []error
+	if o.TLSFiles != nil {
+		errs = append(errs, fmt.Errorf("dev tls and tls files both set"))
+	}
+
+	if o.Options != nil && o.TLSConfig != nil {
+		errs = append(errs, fmt.Errorf("dev tls and tls config both set"))
+	}
+
+	for _, host := range o.DevTLS.Hosts {
+		if host == "" || strings.ContainsAny(host, " /") {
+			errs = append(errs, fmt.Errorf("invalid dev tls host %q", host))
+		}
+	}
+
+	return errs
+}
+
+// validateMutualTLS checks that mutual TLS has a mapper, is the only
+// authentication of the options, and has certificate authorities to
+// verify clients against.
+func (o *Options) validateMutualTLS() []error {
+	var errs []error
+	if o.MutualTLS.Mapper == nil {
+		errs = append(errs, fmt.Errorf("mutual tls has no mapper"))
+	}
+
+	if o.Authenticator != nil {
+		errs = append(errs, fmt.Errorf("authenticator and mutual tls both set"))
+	}
+
+	if o.AuthCallout != nil {
+		errs = append(errs, fmt.Errorf("mutual tls and auth callout both set"))
+	}
+
+	if o.Operator != nil {
+		errs = append(errs, fmt.Errorf("mutual tls and operator both set"))
+	}
+
+	if o.Options != nil && o.CustomClientAuthentication != nil {
+		errs = append(errs, fmt.Errorf(
+			"mutual tls and custom client authentication both set",
+		))
+	}
+
+	switch {
+	case o.DevTLS != nil:
+	case o.TLSFiles != nil:
+		if o.TLSFiles.CAFile == "" {
+			errs = append(errs, fmt.Errorf(
+				"mutual tls needs tls files with a ca file",
+			))
+		}
+	case o.Options != nil && o.TLSConfig != nil:
+		if o.TLSConfig.ClientCAs == nil {
+			errs = append(errs, fmt.Errorf(
+				"mutual tls needs a tls config with client cas",
+			))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("mutual tls needs client tls"))
+	}
+
+	return errs
+}
+
+// validateJetStreamAssets checks that the JetStream assets have an
+// account the server can provision them in as a user of its own, that
+// each has a name no other of its kind has, and that the migrations are
+// well formed.
+func (o *Options) validateJetStreamAssets() []error {
+	assets := o.JetStreamAssets
+	var errs []error
+	if !o.JetStream {
+		errs = append(errs, fmt.Errorf("jetstream assets without jetstream"))
+	}
+
+	if len(o.TrustedOperators) > 0 || len(o.TrustedKeys) > 0 {
+		errs = append(errs, fmt.Errorf(
+			"jetstream assets not supported with trusted operators",
+		))
+	}
+
+	if o.CustomClientAuthentication != nil {
+		errs = append(errs, fmt.Errorf(
+			"jetstream assets and custom client authentication both set",
+		))
+	}
+
+	hasUsers := len(o.Users) > 0 || len(o.Nkeys) > 0 ||
+		o.authenticator() != nil || o.AuthCallout != nil
+	switch {
+	case o.Operator != nil:
+		if assets.Account == "" {
+			errs = append(errs, fmt.Errorf(
+				"jetstream assets need an account in operator mode",
+			))
+		}
+	case assets.Account == "":
+	case !hasUsers:
+		errs = append(errs, fmt.Errorf(
+			"jetstream assets account %q set without users",
+			assets.Account,
+		))
+	case !slices.ContainsFunc(
+		o.Accounts,
+		func(acc *natsserver.Account) bool {
+			return acc.Name == assets.Account
+		},
+	):
+		errs = append(errs, fmt.Errorf(
+			"jetstream assets account %q is not one of the accounts",
+			assets.Account,
+		))
+	}
+
+	// Without users of its own, the server provisions the assets as the
+	// single user, which takes the password itself.
+	if !hasUsers && o.Operator == nil && o.Username != "" &&
+		IsHashedPassword(o.Password) {
+		errs = append(errs, fmt.Errorf(
+			"jetstream assets need the plaintext password of the user",
+		))
+	}
+
+	var streams, keyValues, objectStores, consumers []string
+	for _, cfg := range assets.Streams {
+		streams = append(streams, cfg.Name)
+	}
+	for _, cfg := range assets.KeyValues {
+		keyValues = append(keyValues, cfg.Bucket)
+	}
+	for _, cfg := range assets.ObjectStores {
+		objectStores = append(objectStores, cfg.Bucket)
+	}
+	for i, c := range assets.Consumers {
+		name := consumerName(c.Config)
+		if c.Stream == "" {
+			errs = append(errs, fmt.Errorf(
+				"jetstream consumer %d has no stream",
+				i,
+			))
+		}
+		if name != "" {
+			name = c.Stream + "/" + name
+		}
+		consumers = append(consumers, name)
+	}
+
+	errs = append(errs, validateAssetNames("stream", streams)...)
+	errs = append(errs, validateAssetNames("key-value bucket", keyValues)...)
+	errs = append(errs, validateAssetNames("object store", objectStores)...)
+	errs = append(errs, validateAssetNames("consumer", consumers)...)
+	errs = append(errs, validateMigrations(assets.Migrations)...)
+
+	return errs
+}
+
+// validateMigrations checks that each of migrations has a positive
+// version no other has, and a function applying it.
+func validateMigrations(
+	migrations []JetStreamMigration,
+) []error {
+	var errs []error
+	seen := make(map[uint64]bool, len(migrations))
+	for i, m := range migrations {
+		switch {
+		case m.Version == 0:
+			errs = append(errs, fmt.Errorf(
+				"jetstream migration %d has no version",
+				i,
+			))
+		case seen[m.Version]:
+			errs = append(errs, fmt.Errorf(
+				"duplicate jetstream migration version %d",
+				m.Version,
+			))
+		}
+		seen[m.Version] = true
+
+		if m.Apply == nil {
+			errs = append(errs, fmt.Errorf(
+				"jetstream migration %d has no apply function",
+				i,
+			))
+		}
+	}
+
+	return errs
+}
+
+// validateAssetNames checks that each of names, the names of the
+// JetStream assets of kind, is set and unique.
+func validateAssetNames(
+	kind string,
+	names []string,
+) []error {
+	var errs []error
+	seen := make(map[string]bool, len(names))
+	for i, name := range names {
+		switch {
+		case name == "":
+			errs = append(errs, fmt.Errorf(
+				"jetstream %s %d has no name",
+				kind,
+				i,
+			))
+		case seen[name]:
+			errs = append(errs, fmt.Errorf(
+				"duplicate jetstream %s %q",
+				kind,
+				name,
+			))
+		}
+		seen[name] = true
+	}
+
+	return errs
+}
+
+// validateStore checks that JetStream has a store directory it can write
+// to.
+func validateStore(
+	opts *natsserver.Options,
+) []error {
+	if opts.StoreDir != "" {
+		if err := checkStoreDir(opts.StoreDir); err != nil {
+			return []error{err}
+		}
+	}
+
+	if opts.JetStream && opts.StoreDir == "" {
+		return []error{fmt.Errorf("jetstream enabled without a store directory")}
+	}
+
+	return nil
+}
+
+// checkStoreDir checks that the store directory dir, or the nearest
+// directory above it that exists when dir does not, can be written to.
+func checkStoreDir(
+	dir string,
+) error {
+	existing := dir
+	info, err := os.Stat(existing)
+	for err != nil && existing != filepath.Dir(existing) {
+		existing = filepath.Dir(existing)
+		info, err = os.Stat(existing)
+	}
+
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf(
+			"store directory %s cannot be created: %s is not a directory",
+			dir,
+			existing,
+		)
+	}
+
+	f, err := os.CreateTemp(existing, ".nats-server-validate-*")
+	if err != nil {
+		return fmt.Errorf("store directory %s is not writable: %w", dir, err)
+	}
+	_ = f.Close()
+
+	return os.Remove(f.Name())
+}
+
+// validatePorts checks that no two listeners share a fixed port on
+// overlapping hosts, the client listener's being the default port when
+// Port is unset.
+func validatePorts(
+	opts *natsserver.Options,
+) []error {
+	var errs []error
+	all := listeners(opts)
+	for i, a := range all {
+		for _, b := range all[i+1:] {
+			if a.port != b.port {
+				continue
+			}
+
+			if a.host == b.host || isWildcard(a.host) || isWildcard(b.host) {
+				errs = append(errs, fmt.Errorf(
+					"%s and %s listeners both use port %d",
+					a.name,
+					b.name,
+					a.port,
+				))
+			}
+		}
+	}
+
+	return errs
+}
+
+// isWildcard reports whether host listens on every interface.
+func isWildcard(
+	host string,
+) bool {
+	return host == "" || host == "0.0.0.0" || host == "::"
+}
+
+// validateAuth checks accounts, users, nkey users, and the system account.
+// References to accounts are only checked without an operator, since with
+// one they are resolved at runtime.
+func validateAuth(
+	opts *natsserver.Options,
+) []error {
+	var errs []error
+	operator := len(opts.TrustedOperators) > 0 || len(opts.TrustedKeys) > 0
+	accounts := []string{
+		natsserver.DEFAULT_GLOBAL_ACCOUNT,
+		natsserver.DEFAULT_SYSTEM_ACCOUNT,
+	}
+	declared := make(map[string]bool, len(opts.Accounts))
+	for _, acc := range opts.Accounts {
+		if declared[acc.Name] {
+			errs = append(errs, fmt.Errorf("duplicate account %q", acc.Name))
+		}
+		declared[acc.Name] = true
+		accounts = append(accounts, acc.Name)
+	}
+
+	unknown := func(acc *natsserver.Account) bool {
+		return !operator && acc != nil && !slices.Contains(accounts, acc.Name)
+	}
+
+	usernames := make(map[string]bool, len(opts.Users))
+	for _, u := range opts.Users {
+		if usernames[u.Username] {
+			errs = append(errs, fmt.Errorf("duplicate user %q", u.Username))
+		}
+		usernames[u.Username] = true
+
+		if unknown(u.Account) {
+			errs = append(errs, fmt.Errorf(
+				"user %q references unknown account %q",
+				u.Username,
+				u.Account.Name,
+			))
+		}
+	}
+
+	nkeyUsers := make(map[string]bool, len(opts.Nkeys))
+	for _, u := range opts.Nkeys {
+		if !nkeys.IsValidPublicUserKey(u.Nkey) {
+			errs = append(errs, fmt.Errorf("invalid user nkey %q", u.Nkey))
+		}
+
+		if nkeyUsers[u.Nkey] {
+			errs = append(errs, fmt.Errorf("duplicate nkey %q", u.Nkey))
+		}
+		nkeyUsers[u.Nkey] = true
+
+		if unknown(u.Account) {
+			errs = append(errs, fmt.Errorf(
+				"nkey %q references unknown account %q",
+				u.Nkey,
+				u.Account.Name,
+			))
+		}
+	}
+
+	if !operator && opts.SystemAccount != "" &&
+		!slices.Contains(accounts, opts.SystemAccount) {
+		errs = append(errs, fmt.Errorf(
+			"system account %q is not one of the accounts",
+			opts.SystemAccount,
+		))
+	}
+
+	return errs
+}
@@ -0,0 +1,843 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+const (
+	nkeyService1 = "UAZMBGU3ASBL22E5WW6F3EFAW3CNUGGFRRYI6VRPPOHLNCNJZDTXFOPG"
+	nkeyService2 = "UCL5D5YPOGDRFAS354LW7D3E4S7FOJXZGR3ULHGQFWTFQ3PCRTUAT3ZD"
+)
+
+type ValidatePublicTestSuite struct {
+	suite.Suite
+}
+
+func (s *ValidatePublicTestSuite) TestValidate() {
+	tests := []struct {
+		name         string
+		opts         func() *server.Options
+		validateFunc func(err error)
+	}{
+		{
+			name: "accepts valid options",
+			opts: func() *server.Options {
+				app := natsserver.NewAccount("APP")
+				return &server.Options{
+					Options: &natsserver.Options{
+						Host:      "127.0.0.1",
+						Port:      4222,
+						HTTPHost:  "127.0.0.1",
+						HTTPPort:  4222 + 1,
+						JetStream: true,
+						StoreDir:  filepath.Join(s.T().TempDir(), "jetstream"),
+						Accounts:  []*natsserver.Account{app},
+						Users: []*natsserver.User{
+							{Username: "app", Account: app},
+							{Username: "default"},
+						},
+						Nkeys: []*natsserver.NkeyUser{
+							{Nkey: nkeyService1, Account: app},
+							{Nkey: nkeyService2},
+						},
+						SystemAccount: "APP",
+					},
+					ReadyTimeout: 5 * time.Second,
+				}
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name: "skips account checks with an operator",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						TrustedKeys: []string{"OPERATOR"},
+						Users: []*natsserver.User{
+							{
+								Username: "app",
+								Account:  natsserver.NewAccount("APP"),
+							},
+						},
+						SystemAccount: "SYS",
+					},
+					ReadyTimeout: 5 * time.Second,
+				}
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name: "rejects missing nats server options",
+			opts: func() *server.Options {
+				return &server.Options{ReadyTimeout: 5 * time.Second}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(
+					err,
+					"invalid options: nats server options are required",
+				)
+			},
+		},
+		{
+			name: "rejects ready timeout that is not positive",
+			opts: func() *server.Options {
+				return &server.Options{Options: &natsserver.Options{}}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(
+					err,
+					"invalid options: ready timeout must be positive, got 0s",
+				)
+			},
+		},
+		{
+			name: "rejects jetstream without store directory",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options:      &natsserver.Options{JetStream: true},
+					ReadyTimeout: 5 * time.Second,
+				}
+			},
+			validateFunc: func(err error) {
+				s.ErrorContains(
+					err,
+					"jetstream enabled without a store directory",
+				)
+			},
+		},
+		{
+			name: "rejects store directory below a file",
+			opts: func() *server.Options {
+				file := filepath.Join(s.T().TempDir(), "file")
+				s.Require().NoError(os.WriteFile(file, nil, 0o600))
+
+				return &server.Options{
+					Options: &natsserver.Options{
+						JetStream: true,
+						StoreDir:  filepath.Join(file, "jetstream"),
+					},
+					ReadyTimeout: 5 * time.Second,
+				}
+			},
+			validateFunc: func(err error) {
+				s.ErrorContains(err, "is not a directory")
+			},
+		},
+		{
+			name: "rejects store directory that is not writable",
+			opts: func() *server.Options {
+				if _, err := os.Stat("/proc"); err != nil {
+					s.T().Skip("needs /proc, which cannot be written to")
+				}
+
+				return &server.Options{
+					Options: &natsserver.Options{
+						JetStream: true,
+						StoreDir:  "/proc/jetstream",
+					},
+					ReadyTimeout: 5 * time.Second,
+				}
+			},
+			validateFunc: func(err error) {
+				s.ErrorContains(
+					err,
+					"store directory /proc/jetstream is not writable",
+				)
+			},
+		},
+		{
+			name: "rejects listeners sharing a port",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						Host:     "127.0.0.1",
+						Port:     4222,
+						HTTPPort: 4222,
+						Cluster: natsserver.ClusterOpts{
+							Host: "10.0.0.1",
+							Port: 4222,
+						},
+						LeafNode: natsserver.LeafNodeOpts{
+							Host: "127.0.0.2",
+							Port: 7422,
+						},
+						Websocket: natsserver.WebsocketOpts{
+							Host: "127.0.0.3",
+							Port: 7422,
+						},
+					},
+					ReadyTimeout: 5 * time.Second,
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(
+					err,
+					"invalid options: client and monitoring listeners "+
+						"both use port 4222",
+				)
+			},
+		},
+		{
+			name: "rejects listeners sharing the default client port",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						HTTPPort: 4222,
+					},
+					ReadyTimeout: 5 * time.Second,
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(
+					err,
+					"invalid options: client and monitoring listeners "+
+						"both use port 4222",
+				)
+			},
+		},
+		{
+			name: "accepts listeners with random ports",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						Port:     -1,
+						HTTPPort: -1,
+					},
+					ReadyTimeout: 5 * time.Second,
+				}
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name: "rejects tls without a tls config",
+			opts: func() *server.Options {
+				opts := server.DefaultOptions()
+				server.WithTLS(nil)(opts)
+
+				return opts
+			},
+			validateFunc: func(err error) {
+				s.EqualError(
+					err,
+					"invalid options: tls enabled without a tls config",
+				)
+			},
+		},
+		{
+			name: "rejects tls files without a key",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options:      &natsserver.Options{},
+					ReadyTimeout: 5 * time.Second,
+					TLSFiles:     &server.TLSFiles{CertFile: "server.pem"},
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(
+					err,
+					"invalid options: tls files need a certificate and key",
+				)
+			},
+		},
+		{
+			name: "rejects listener tls files without a certificate",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options:      &natsserver.Options{},
+					ReadyTimeout: 5 * time.Second,
+					ClusterTLSFiles: &server.TLSFiles{
+						KeyFile: "server-key.pem",
+					},
+					LeafNodeTLSFiles:  &server.TLSFiles{},
+					WebsocketTLSFiles: &server.TLSFiles{},
+					CertWatcher:       &server.CertWatcher{Interval: -1},
+				}
+			},
+			validateFunc: func(err error) {
+				var validationErr *server.ValidationError
+				s.Require().ErrorAs(err, &validationErr)
+				s.Equal([]error{
+					errors.New("cluster tls files need a certificate and key"),
+					errors.New("leafnode tls files need a certificate and key"),
+					errors.New("websocket tls files need a certificate and key"),
+					errors.New("cert watcher interval is negative"),
+				}, validationErr.Errs)
+			},
+		},
+		{
+			name: "rejects cert watcher without tls files",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options:      &natsserver.Options{},
+					ReadyTimeout: 5 * time.Second,
+					CertWatcher:  &server.CertWatcher{},
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(
+					err,
+					"invalid options: cert watcher has no tls files",
+				)
+			},
+		},
+		{
+			name: "rejects authenticator with custom client authentication",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						CustomClientAuthentication: mocks.NewMockAuthentication(
+							gomock.NewController(s.T()),
+						),
+					},
+					ReadyTimeout:  5 * time.Second,
+					Authenticator: server.StaticAuthenticator{},
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(err, "invalid options: authenticator and "+
+					"custom client authentication both set")
+			},
+		},
+		{
+			name: "rejects auth callout with other authentication",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						AuthCallout: &natsserver.AuthCallout{},
+						CustomClientAuthentication: mocks.NewMockAuthentication(
+							gomock.NewController(s.T()),
+						),
+						TrustedKeys: []string{
+							"ODSKR7MYFQZ5MMAJ6FPMEEXFMB4OWBI5TC3YJ4IB6IUBAGHW2RCVIJD",
+						},
+						Users: []*natsserver.User{
+							{Username: "auth-callout", Password: "secret"},
+						},
+					},
+					ReadyTimeout:  5 * time.Second,
+					Authenticator: server.StaticAuthenticator{},
+					AuthCallout:   &server.AuthCallout{},
+				}
+			},
+			validateFunc: func(err error) {
+				var validationErr *server.ValidationError
+				s.Require().ErrorAs(err, &validationErr)
+				for _, msg := range []string{
+					"auth callout has no handler",
+					"authenticator and auth callout both set",
+					"auth callout set in both server and nats server options",
+					"auth callout and custom client authentication both set",
+					"auth callout not supported with trusted operators",
+					`user "auth-callout" reserved for auth callout`,
+				} {
+					s.ErrorContains(err, msg)
+				}
+			},
+		},
+		{
+			name: "rejects auth callout without handler or options",
+			opts: func() *server.Options {
+				return &server.Options{
+					ReadyTimeout: 5 * time.Second,
+					AuthCallout:  &server.AuthCallout{},
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(err, "invalid options: nats server options are "+
+					"required; auth callout has no handler")
+			},
+		},
+		{
+			name: "rejects operator with other authentication",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						CustomClientAuthentication: mocks.NewMockAuthentication(
+							gomock.NewController(s.T()),
+						),
+						SystemAccount: "SYS",
+						Users: []*natsserver.User{
+							{Username: "app", Password: "secret"},
+						},
+					},
+					ReadyTimeout:  5 * time.Second,
+					Authenticator: server.StaticAuthenticator{},
+					Operator: &server.Operator{
+						Accounts: []string{"APP", "APP", "SYS", "a/b", ""},
+					},
+				}
+			},
+			validateFunc: func(err error) {
+				var validationErr *server.ValidationError
+				s.Require().ErrorAs(err, &validationErr)
+				for _, msg := range []string{
+					"authenticator and operator both set",
+					`duplicate operator account "APP"`,
+					`duplicate operator account "SYS"`,
+					`invalid account name "a/b"`,
+					`invalid account name ""`,
+					"operator set in both server and nats server options",
+					"operator mode does not allow users or accounts in options",
+					"operator and custom client authentication both set",
+				} {
+					s.ErrorContains(err, msg)
+				}
+			},
+		},
+		{
+			name: "rejects operator without options",
+			opts: func() *server.Options {
+				return &server.Options{
+					ReadyTimeout: 5 * time.Second,
+					Operator:     &server.Operator{Accounts: []string{".."}},
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(err, "invalid options: nats server options are "+
+					`required; invalid account name ".."`)
+			},
+		},
+		{
+			name: "rejects dev tls alongside other tls",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						TLSConfig: &tls.Config{MinVersion: tls.VersionTLS12},
+					},
+					ReadyTimeout: 5 * time.Second,
+					TLSFiles: &server.TLSFiles{
+						CertFile: "server.pem",
+						KeyFile:  "server-key.pem",
+					},
+					DevTLS: &server.DevTLS{Hosts: []string{"", "a b"}},
+				}
+			},
+			validateFunc: func(err error) {
+				var validationErr *server.ValidationError
+				s.Require().ErrorAs(err, &validationErr)
+				for _, msg := range []string{
+					"dev tls and tls files both set",
+					"dev tls and tls config both set",
+					`invalid dev tls host ""`,
+					`invalid dev tls host "a b"`,
+				} {
+					s.ErrorContains(err, msg)
+				}
+			},
+		},
+		{
+			name: "rejects dev tls without options",
+			opts: func() *server.Options {
+				return &server.Options{
+					ReadyTimeout: 5 * time.Second,
+					DevTLS:       &server.DevTLS{},
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(
+					err,
+					"invalid options: nats server options are required",
+				)
+			},
+		},
+		{
+			name: "rejects auth callout in operator mode",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options:      &natsserver.Options{},
+					ReadyTimeout: 5 * time.Second,
+					AuthCallout: &server.AuthCallout{
+						Handler: server.StaticAuthenticator{},
+					},
+					Operator: &server.Operator{},
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(err, "invalid options: auth callout not "+
+					"supported with trusted operators")
+			},
+		},
+		{
+			name: "rejects mutual tls alongside other authentication",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						CustomClientAuthentication: mocks.NewMockAuthentication(
+							gomock.NewController(s.T()),
+						),
+					},
+					ReadyTimeout:  5 * time.Second,
+					Authenticator: server.StaticAuthenticator{},
+					AuthCallout: &server.AuthCallout{
+						Handler: server.StaticAuthenticator{},
+					},
+					Operator:  &server.Operator{},
+					MutualTLS: &server.MutualTLS{},
+				}
+			},
+			validateFunc: func(err error) {
+				var validationErr *server.ValidationError
+				s.Require().ErrorAs(err, &validationErr)
+				for _, msg := range []string{
+					"mutual tls has no mapper",
+					"authenticator and mutual tls both set",
+					"mutual tls and auth callout both set",
+					"mutual tls and operator both set",
+					"mutual tls and custom client authentication both set",
+					"mutual tls needs client tls",
+				} {
+					s.ErrorContains(err, msg)
+				}
+			},
+		},
+		{
+			name: "rejects mutual tls without client cas",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options:      &natsserver.Options{},
+					ReadyTimeout: 5 * time.Second,
+					TLSFiles: &server.TLSFiles{
+						CertFile: "server.pem",
+						KeyFile:  "server-key.pem",
+					},
+					MutualTLS: &server.MutualTLS{
+						Mapper: server.CertificateUsers{},
+					},
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(err, "invalid options: mutual tls needs tls "+
+					"files with a ca file")
+			},
+		},
+		{
+			name: "rejects mutual tls with a tls config without client cas",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						TLSConfig: &tls.Config{MinVersion: tls.VersionTLS12},
+					},
+					ReadyTimeout: 5 * time.Second,
+					MutualTLS: &server.MutualTLS{
+						Mapper: server.CertificateUsers{},
+					},
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(err, "invalid options: mutual tls needs a tls "+
+					"config with client cas")
+			},
+		},
+		{
+			name: "rejects jetstream assets the server cannot provision",
+			opts: func() *server.Options {
+				hashed, err := server.HashPassword("secret", 4)
+				s.Require().NoError(err)
+
+				return &server.Options{
+					Options: &natsserver.Options{
+						Username: "app",
+						Password: hashed,
+						CustomClientAuthentication: mocks.NewMockAuthentication(
+							gomock.NewController(s.T()),
+						),
+					},
+					ReadyTimeout: 5 * time.Second,
+					JetStreamAssets: &server.JetStreamAssets{
+						Streams: []jetstream.StreamConfig{
+							{Name: "ORDERS"},
+							{Name: "ORDERS"},
+							{},
+						},
+						Consumers: []server.JetStreamConsumer{
+							{Config: jetstream.ConsumerConfig{Durable: "a"}},
+							{
+								Stream: "ORDERS",
+								Config: jetstream.ConsumerConfig{Name: "b"},
+							},
+							{
+								Stream: "ORDERS",
+								Config: jetstream.ConsumerConfig{Durable: "b"},
+							},
+						},
+						KeyValues: []jetstream.KeyValueConfig{{}},
+						ObjectStores: []jetstream.ObjectStoreConfig{
+							{Bucket: "files"},
+							{Bucket: "files"},
+						},
+					},
+				}
+			},
+			validateFunc: func(err error) {
+				var validationErr *server.ValidationError
+				s.Require().ErrorAs(err, &validationErr)
+				for _, msg := range []string{
+					"jetstream assets without jetstream",
+					"jetstream assets and custom client authentication both set",
+					"jetstream assets need the plaintext password of the user",
+					`duplicate jetstream stream "ORDERS"`,
+					"jetstream stream 2 has no name",
+					"jetstream consumer 0 has no stream",
+					`duplicate jetstream consumer "ORDERS/b"`,
+					"jetstream key-value bucket 0 has no name",
+					`duplicate jetstream object store "files"`,
+				} {
+					s.ErrorContains(err, msg)
+				}
+			},
+		},
+		{
+			name: "rejects malformed jetstream migrations",
+			opts: func() *server.Options {
+				apply := func(context.Context, jetstream.JetStream) error {
+					return nil
+				}
+
+				return &server.Options{
+					Options: &natsserver.Options{
+						JetStream: true,
+						StoreDir:  s.T().TempDir(),
+					},
+					ReadyTimeout: 5 * time.Second,
+					JetStreamAssets: &server.JetStreamAssets{
+						Migrations: []server.JetStreamMigration{
+							{Version: 1, Apply: apply},
+							{Version: 1, Apply: apply},
+							{Apply: apply},
+							{Version: 2},
+						},
+					},
+				}
+			},
+			validateFunc: func(err error) {
+				var validationErr *server.ValidationError
+				s.Require().ErrorAs(err, &validationErr)
+				s.Len(validationErr.Errs, 3)
+				for _, msg := range []string{
+					"duplicate jetstream migration version 1",
+					"jetstream migration 2 has no version",
+					"jetstream migration 3 has no apply function",
+				} {
+					s.ErrorContains(err, msg)
+				}
+			},
+		},
+		{
+			name: "rejects jetstream assets in an unknown account",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						JetStream: true,
+						StoreDir:  s.T().TempDir(),
+						Users: []*natsserver.User{
+							{Username: "app", Password: "secret"},
+						},
+					},
+					ReadyTimeout: 5 * time.Second,
+					JetStreamAssets: &server.JetStreamAssets{
+						Account: "ORDERS",
+					},
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(err, `invalid options: jetstream assets account `+
+					`"ORDERS" is not one of the accounts`)
+			},
+		},
+		{
+			name: "rejects jetstream assets account without users",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						JetStream: true,
+						StoreDir:  s.T().TempDir(),
+						Accounts: []*natsserver.Account{
+							natsserver.NewAccount("ORDERS"),
+						},
+					},
+					ReadyTimeout: 5 * time.Second,
+					JetStreamAssets: &server.JetStreamAssets{
+						Account: "ORDERS",
+					},
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(err, `invalid options: jetstream assets account `+
+					`"ORDERS" set without users`)
+			},
+		},
+		{
+			name: "rejects jetstream assets without account in operator mode",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						JetStream: true,
+						StoreDir:  s.T().TempDir(),
+					},
+					ReadyTimeout:    5 * time.Second,
+					Operator:        &server.Operator{},
+					JetStreamAssets: &server.JetStreamAssets{},
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(err, "invalid options: jetstream assets need an "+
+					"account in operator mode")
+			},
+		},
+		{
+			name: "rejects duplicate accounts",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						Accounts: []*natsserver.Account{
+							natsserver.NewAccount("APP"),
+							natsserver.NewAccount("APP"),
+						},
+					},
+					ReadyTimeout: 5 * time.Second,
+				}
+			},
+			validateFunc: func(err error) {
+				s.EqualError(err, `invalid options: duplicate account "APP"`)
+			},
+		},
+		{
+			name: "rejects users and nkeys with problems",
+			opts: func() *server.Options {
+				return &server.Options{
+					Options: &natsserver.Options{
+						Users: []*natsserver.User{
+							{Username: "app"},
+							{
+								Username: "app",
+								Account:  natsserver.NewAccount("MISSING"),
+							},
+						},
+						Nkeys: []*natsserver.NkeyUser{
+							{Nkey: nkeyService1},
+							{Nkey: nkeyService1},
+							{
+								Nkey:    "bogus",
+								Account: natsserver.NewAccount("MISSING"),
+							},
+						},
+						SystemAccount: "SYS",
+					},
+					ReadyTimeout: 5 * time.Second,
+				}
+			},
+			validateFunc: func(err error) {
+				var validationErr *server.ValidationError
+				s.Require().ErrorAs(err, &validationErr)
+				s.Len(validationErr.Errs, 6)
+				s.ErrorContains(err, `duplicate user "app"`)
+				s.ErrorContains(
+					err,
+					`user "app" references unknown account "MISSING"`,
+				)
+				s.ErrorContains(err, `duplicate nkey "`+nkeyService1+`"`)
+				s.ErrorContains(err, `invalid user nkey "bogus"`)
+				s.ErrorContains(
+					err,
+					`nkey "bogus" references unknown account "MISSING"`,
+				)
+				s.ErrorContains(
+					err,
+					`system account "SYS" is not one of the accounts`,
+				)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.validateFunc(tc.opts().Validate())
+		})
+	}
+}
+
+func (s *ValidatePublicTestSuite) TestError() {
+	err := &server.ValidationError{
+		Errs: []error{errors.New("first"), errors.New("second")},
+	}
+
+	s.EqualError(err, "invalid options: first; second")
+}
+
+func (s *ValidatePublicTestSuite) TestUnwrap() {
+	cause := errors.New("first")
+	err := &server.ValidationError{Errs: []error{cause}}
+
+	s.ErrorIs(err, cause)
+}
+
+func (s *ValidatePublicTestSuite) TestIs() {
+	tests := []struct {
+		name     string
+		target   error
+		expected bool
+	}{
+		{
+			name:     "matches ErrInvalidOptions",
+			target:   server.ErrInvalidOptions,
+			expected: true,
+		},
+		{
+			name:     "does not match other errors",
+			target:   server.ErrNotReady,
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			err := &server.ValidationError{}
+
+			s.Equal(tc.expected, errors.Is(err, tc.target))
+		})
+	}
+}
+
+func TestValidatePublicTestSuite(t *testing.T) {
+	suite.Run(t, new(ValidatePublicTestSuite))
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server
+
+import "fmt"
+
+// Done returns a channel closed when the current run of the server ends:
+// when it has been stopped, when its start failed, or when the NATS server
+// shut down on its own. Each start begins a new run with a new channel.
+func (s *Server) Done() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.run.done
+}
+
+// Wait blocks until the current run of the server ends and returns why. It
+// returns nil when the server was stopped or drained, the error Start
+// returned when the start failed, and an error wrapping ErrServerTerminated
+// when the NATS server shut down on its own, so that a supervisor can
+// restart it or exit.
+func (s *Server) Wait() error {
+	s.mu.Lock()
+	r := s.run
+	s.mu.Unlock()
+
+	<-r.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return r.cause
+}
+
+// monitor waits for natsServer, started for run r, to shut down and, when
+// that happens while r is running rather than being stopped, moves the
+// server to StateFailed. The last fatal message natsServer logged, if any,
+// is included in the cause.
+func (s *Server) monitor(
+	r *run,
+	natsServer NATSServerInstance,
+	logger *SlogWrapper,
+) {
+	natsServer.WaitForShutdown()
+
+	cause := ErrServerTerminated
+	if msg := logger.lastFatalMessage(); msg != "" {
+		cause = fmt.Errorf("%w: %s", ErrServerTerminated, msg)
+	}
+
+	// A server being stopped, or started again since, cannot fail.
+	if err := s.fail(r, cause); err != nil {
+		return
+	}
+
+	s.logger.Error("nats server terminated unexpectedly", "error", cause)
+}
@@ -0,0 +1,269 @@
+// Copyright (c) 2026 John Dewey
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+// DEALINGS IN THE SOFTWARE.
+
+package server_test
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/osapi-io/nats-server/pkg/server"
+	"github.com/osapi-io/nats-server/pkg/server/mocks"
+)
+
+type WaitPublicTestSuite struct {
+	suite.Suite
+
+	mockCtrl       *gomock.Controller
+	mockNATSServer *mocks.MockNATSServerInstance
+	srv            *server.Server
+	terminate      func()
+	newNATSServer  func(*natsserver.Options) (server.NATSServerInstance, error)
+}
+
+func (s *WaitPublicTestSuite) SetupSuite() {
+	s.newNATSServer = server.NewNATSServer
+}
+
+func (s *WaitPublicTestSuite) TearDownSuite() {
+	server.NewNATSServer = s.newNATSServer
+}
+
+func (s *WaitPublicTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.mockNATSServer = mocks.NewMockNATSServerInstance(s.mockCtrl)
+	s.terminate = expectWaitForShutdown(s.mockNATSServer)
+	s.srv = server.New(
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		&server.Options{
+			Options:      &natsserver.Options{},
+			ReadyTimeout: 5 * time.Second,
+		},
+	)
+
+	server.NewNATSServer = func(
+		_ *natsserver.Options,
+	) (server.NATSServerInstance, error) {
+		return s.mockNATSServer, nil
+	}
+}
+
+func (s *WaitPublicTestSuite) SetupSubTest() {
+	s.SetupTest()
+}
+
+func (s *WaitPublicTestSuite) TearDownSubTest() {
+	s.terminate()
+}
+
+func (s *WaitPublicTestSuite) TearDownTest() {
+	s.terminate()
+	s.mockCtrl.Finish()
+}
+
+func (s *WaitPublicTestSuite) TestDone() {
+	var previous <-chan struct{}
+
+	tests := []struct {
+		name         string
+		setup        func()
+		validateFunc func(done <-chan struct{})
+	}{
+		{
+			name: "open while running",
+			setup: func() {
+				expectStart(s.mockNATSServer)
+				s.Require().NoError(s.srv.Start())
+			},
+			validateFunc: func(done <-chan struct{}) {
+				s.NotNil(done)
+				s.Never(
+					func() bool { return isClosed(done) },
+					20*time.Millisecond,
+					time.Millisecond,
+				)
+			},
+		},
+		{
+			name: "closed once stopped",
+			setup: func() {
+				expectStart(s.mockNATSServer)
+				s.mockNATSServer.EXPECT().Shutdown()
+				s.Require().NoError(s.srv.Start())
+				s.srv.Stop()
+			},
+			validateFunc: func(done <-chan struct{}) {
+				s.True(isClosed(done))
+			},
+		},
+		{
+			name: "closed when start fails",
+			setup: func() {
+				expectNotReady(s.mockNATSServer)
+				s.Require().Error(s.srv.Start())
+			},
+			validateFunc: func(done <-chan struct{}) {
+				s.True(isClosed(done))
+			},
+		},
+		{
+			name: "closed when nats server shuts down on its own",
+			setup: func() {
+				expectStart(s.mockNATSServer)
+				s.Require().NoError(s.srv.Start())
+				s.terminate()
+			},
+			validateFunc: func(done <-chan struct{}) {
+				s.Eventually(
+					func() bool { return isClosed(done) },
+					time.Second,
+					time.Millisecond,
+				)
+			},
+		},
+		{
+			name: "replaced when restarted",
+			setup: func() {
+				expectStart(s.mockNATSServer)
+				s.mockNATSServer.EXPECT().Shutdown()
+				expectStart(s.mockNATSServer)
+				s.Require().NoError(s.srv.Start())
+				s.srv.Stop()
+				previous = s.srv.Done()
+				s.Require().NoError(s.srv.Start())
+			},
+			validateFunc: func(done <-chan struct{}) {
+				s.NotEqual(previous, done)
+				s.True(isClosed(previous))
+				s.False(isClosed(done))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+
+			tc.validateFunc(s.srv.Done())
+		})
+	}
+}
+
+func (s *WaitPublicTestSuite) TestWait() {
+	tests := []struct {
+		name         string
+		setup        func()
+		validateFunc func(err error)
+	}{
+		{
+			name: "returns nil once stopped",
+			setup: func() {
+				expectStart(s.mockNATSServer)
+				s.mockNATSServer.EXPECT().Shutdown()
+				s.Require().NoError(s.srv.Start())
+				s.srv.Stop()
+			},
+			validateFunc: func(err error) {
+				s.NoError(err)
+			},
+		},
+		{
+			name: "returns start error when start fails",
+			setup: func() {
+				expectNotReady(s.mockNATSServer)
+				s.Require().Error(s.srv.Start())
+			},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrNotReady)
+			},
+		},
+		{
+			name: "returns cause when nats server shuts down on its own",
+			setup: func() {
+				expectStart(s.mockNATSServer)
+				s.Require().NoError(s.srv.Start())
+				s.terminate()
+			},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrServerTerminated)
+				s.EqualError(err, "nats server terminated unexpectedly")
+				s.Equal(server.StateFailed, s.srv.State())
+			},
+		},
+		{
+			name: "includes last fatal message in cause",
+			setup: func() {
+				s.mockNATSServer.EXPECT().Start().AnyTimes()
+				s.mockNATSServer.EXPECT().
+					ReadyForConnections(gomock.Any()).
+					Return(true)
+				s.mockNATSServer.EXPECT().
+					SetLogger(gomock.Any(), true, true).
+					Do(func(logger natsserver.Logger, _, _ bool) {
+						logger.Fatalf(
+							"Can't start JetStream: %v",
+							errors.New("disk full"),
+						)
+					})
+				s.Require().NoError(s.srv.Start())
+				s.terminate()
+			},
+			validateFunc: func(err error) {
+				s.ErrorIs(err, server.ErrServerTerminated)
+				s.EqualError(
+					err,
+					"nats server terminated unexpectedly: "+
+						"Can't start JetStream: disk full",
+				)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.setup()
+
+			tc.validateFunc(s.srv.Wait())
+		})
+	}
+}
+
+// isClosed reports whether ch is closed, without blocking.
+func isClosed(
+	ch <-chan struct{},
+) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestWaitPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(WaitPublicTestSuite))
+}